@@ -0,0 +1,112 @@
+package gomessagestore
+
+import (
+	"context"
+
+	"github.com/blackhatbrigade/gomessagestore/streamname"
+	"github.com/blackhatbrigade/gomessagestore/uuid"
+)
+
+// Decider decides which events a command produces, given the state projected from the entity's stream
+// Returning no events writes nothing; returning an error stops Handle without writing.
+type Decider func(ctx context.Context, state interface{}) ([]Message, error)
+
+// Aggregate handles commands for entities: it loads an entity's state and version, asks a Decider for new events, and writes them at that version
+// When another writer gets to the stream first, the whole cycle starts over, up to the number of retries set with AggregateRetries.
+type Aggregate interface {
+	Handle(ctx context.Context, category string, entityID uuid.UUID, decide Decider) error
+	HandleStreamID(ctx context.Context, category string, streamID string, decide Decider) error
+}
+
+// AggregateOption is used for creating aggregates with optional settings
+type AggregateOption func(agg *aggregate)
+
+// aggregate is the Aggregate built on a projector
+type aggregate struct {
+	ms        *msgStore
+	projector Projector
+	retries   int
+}
+
+// CreateAggregate creates a new Aggregate that projects state with the provided ProjectorOptions
+func (ms *msgStore) CreateAggregate(projectorOpts []ProjectorOption, opts ...AggregateOption) (Aggregate, error) {
+	proj, err := ms.CreateProjector(projectorOpts...)
+	if err != nil {
+		return nil, err
+	}
+
+	agg := &aggregate{
+		ms:        ms,
+		projector: proj,
+		retries:   3,
+	}
+
+	for _, option := range opts {
+		option(agg)
+	}
+
+	if agg.retries < 0 {
+		return nil, ErrInvalidAggregateRetries
+	}
+
+	return agg, nil
+}
+
+// AggregateRetries sets how many times Handle starts over after a concurrency conflict; defaults to 3, and 0 turns retrying off
+func AggregateRetries(retries int) AggregateOption {
+	return func(agg *aggregate) {
+		agg.retries = retries
+	}
+}
+
+// Handle runs decide against the current state of the entity, and writes the events it returns to the entity's stream
+// ErrExpectedVersionFailed is returned when the stream kept changing underneath every attempt.
+func (agg *aggregate) Handle(ctx context.Context, category string, entityID uuid.UUID, decide Decider) error {
+	return agg.HandleStreamID(ctx, category, entityID.String(), decide)
+}
+
+// HandleStreamID is Handle for an entity whose ID is not a UUID, such as order-ORD123
+func (agg *aggregate) HandleStreamID(ctx context.Context, category string, streamID string, decide Decider) error {
+	var err error
+	for attempt := 0; attempt <= agg.retries; attempt++ {
+		if err = agg.handleOnce(ctx, category, streamID, decide); err != ErrExpectedVersionFailed {
+			return err
+		}
+
+		agg.
+			ms.
+			GetLogger().
+			WithError(err).
+			WithField("streamName", streamname.Compose(category, streamID)).
+			Debug("Aggregate: Stream changed while handling, starting over")
+	}
+
+	return err
+}
+
+// handleOnce loads, decides and writes once
+// The events are written together, so a conflict leaves none of them written and the whole cycle can safely start over.
+func (agg *aggregate) handleOnce(ctx context.Context, category string, streamID string, decide Decider) error {
+	projection, err := agg.projector.RunStreamIDWithVersion(ctx, category, streamID)
+	if err != nil {
+		return err
+	}
+
+	events, err := decide(ctx, projection.State)
+	if err != nil {
+		return err
+	}
+	if len(events) == 0 {
+		return nil
+	}
+
+	streamName := streamname.Compose(category, streamID)
+	messages := make([]Message, len(events))
+	for i, event := range events {
+		if messages[i], err = inStream(event, streamName); err != nil {
+			return err
+		}
+	}
+
+	return agg.ms.writeAll(ctx, messages, projection.Version)
+}
@@ -0,0 +1,309 @@
+package gomessagestore_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	. "github.com/blackhatbrigade/gomessagestore"
+	"github.com/blackhatbrigade/gomessagestore/inmem_repository"
+	"github.com/blackhatbrigade/gomessagestore/repository"
+	mock_repository "github.com/blackhatbrigade/gomessagestore/repository/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+var errWrongExpectedVersion = errors.New("ERROR: Wrong expected version: 1 (Stream: account-" + uuid8.String() + ", Stream Version: 2) (SQLSTATE P0001)")
+
+func getSampleDeposit(version int64, amount float64) *repository.MessageEnvelope {
+	envelope, err := (&Event{
+		ID:             NewID(),
+		MessageType:    "Deposited",
+		EntityID:       uuid8,
+		StreamCategory: "account",
+		MessageVersion: version,
+		Data:           map[string]interface{}{"amount": amount},
+	}).ToEnvelope()
+	panicIf(err)
+	return envelope
+}
+
+func getBalanceProjectorOptions(opts ...ProjectorOption) []ProjectorOption {
+	return append([]ProjectorOption{
+		DefaultState(float64(0)),
+		WithReducerFunc("Deposited", func(msg Message, previousState interface{}) interface{} {
+			return previousState.(float64) + msg.(*Event).Data["amount"].(float64)
+		}),
+	}, opts...)
+}
+
+func withdraw(amount float64) Decider {
+	return func(ctx context.Context, state interface{}) ([]Message, error) {
+		if state.(float64) < amount {
+			return nil, errors.New("insufficient funds")
+		}
+		return []Message{&Event{
+			ID:          NewID(),
+			MessageType: "Withdrawn",
+			Data:        map[string]interface{}{"amount": amount},
+		}}, nil
+	}
+}
+
+func TestAggregateWritesEventsAtTheLoadedVersion(t *testing.T) {
+	tests := []struct {
+		name             string
+		existing         []*repository.MessageEnvelope
+		decide           Decider
+		expectedState    float64
+		expectedTypes    []string
+		expectedPosition int64
+		expectedErr      error
+	}{{
+		name:             "writes at the version of the last message",
+		existing:         []*repository.MessageEnvelope{getSampleDeposit(0, 10), getSampleDeposit(1, 5)},
+		decide:           withdraw(12),
+		expectedState:    15,
+		expectedTypes:    []string{"Withdrawn"},
+		expectedPosition: 1,
+	}, {
+		name:     "writes several events together",
+		existing: []*repository.MessageEnvelope{getSampleDeposit(0, 10)},
+		decide: func(ctx context.Context, state interface{}) ([]Message, error) {
+			return []Message{
+				&Event{ID: NewID(), MessageType: "Withdrawn", Data: map[string]interface{}{"amount": 4}},
+				&StreamMessage{ID: NewID(), MessageType: "FeeCharged", Data: map[string]interface{}{"amount": 1}},
+			}, nil
+		},
+		expectedState:    10,
+		expectedTypes:    []string{"Withdrawn", "FeeCharged"},
+		expectedPosition: 0,
+	}, {
+		name:             "writes the first event of an empty stream at -1",
+		decide:           withdraw(0),
+		expectedTypes:    []string{"Withdrawn"},
+		expectedPosition: -1,
+	}, {
+		name:     "writes nothing when there are no events",
+		existing: []*repository.MessageEnvelope{getSampleDeposit(0, 10)},
+		decide: func(ctx context.Context, state interface{}) ([]Message, error) {
+			return nil, nil
+		},
+		expectedState: 10,
+	}, {
+		name:          "writes nothing when decide fails",
+		existing:      []*repository.MessageEnvelope{getSampleDeposit(0, 10)},
+		decide:        withdraw(20),
+		expectedState: 10,
+		expectedErr:   errors.New("insufficient funds"),
+	}, {
+		name:     "fails for Commands, which belong in command streams",
+		existing: []*repository.MessageEnvelope{getSampleDeposit(0, 10)},
+		decide: func(ctx context.Context, state interface{}) ([]Message, error) {
+			return []Message{getSampleCommand()}, nil
+		},
+		expectedState: 10,
+		expectedErr:   ErrUnsupportedMessage,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mock_repository.NewMockRepository(ctrl)
+			ctx := context.Background()
+			streamName := "account-" + uuid8.String()
+
+			mockRepo.
+				EXPECT().
+				GetAllMessagesInStream(ctx, streamName, 1000).
+				Return(test.existing, nil)
+			if len(test.expectedTypes) > 0 {
+				mockRepo.
+					EXPECT().
+					WriteMessagesWithExpectedPosition(ctx, gomock.Any(), test.expectedPosition).
+					Do(func(_ context.Context, msgEnvs []*repository.MessageEnvelope, _ int64) {
+						var types []string
+						for _, msgEnv := range msgEnvs {
+							assert.Equal(t, streamName, msgEnv.StreamName)
+							types = append(types, msgEnv.MessageType)
+						}
+						assert.Equal(t, test.expectedTypes, types)
+					})
+			}
+
+			msgStore := NewMessageStoreFromRepository(mockRepo, logrus.New())
+			agg, err := msgStore.CreateAggregate(getBalanceProjectorOptions())
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			var state interface{}
+			err = agg.Handle(ctx, "account", uuid8, func(ctx context.Context, projected interface{}) ([]Message, error) {
+				state = projected
+				return test.decide(ctx, projected)
+			})
+
+			assert.Equal(t, test.expectedErr, err)
+			assert.Equal(t, test.expectedState, state)
+		})
+	}
+}
+
+func TestAggregateStartsOverOnConflict(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockRepository(ctrl)
+	ctx := context.Background()
+	streamName := "account-" + uuid8.String()
+
+	gomock.InOrder(
+		mockRepo.
+			EXPECT().
+			GetAllMessagesInStream(ctx, streamName, 1000).
+			Return([]*repository.MessageEnvelope{getSampleDeposit(0, 10)}, nil),
+		mockRepo.
+			EXPECT().
+			WriteMessagesWithExpectedPosition(ctx, gomock.Any(), int64(0)).
+			Return(errWrongExpectedVersion),
+		mockRepo.
+			EXPECT().
+			GetAllMessagesInStream(ctx, streamName, 1000).
+			Return([]*repository.MessageEnvelope{getSampleDeposit(0, 10), getSampleDeposit(1, 5)}, nil),
+		mockRepo.
+			EXPECT().
+			WriteMessagesWithExpectedPosition(ctx, gomock.Any(), int64(1)),
+	)
+
+	msgStore := NewMessageStoreFromRepository(mockRepo, logrus.New())
+	agg, err := msgStore.CreateAggregate(getBalanceProjectorOptions())
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	var states []interface{}
+	err = agg.HandleStreamID(ctx, "account", uuid8.String(), func(ctx context.Context, state interface{}) ([]Message, error) {
+		states = append(states, state)
+		return withdraw(8)(ctx, state)
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{float64(10), float64(15)}, states, "decide sees the state written by the other writer")
+}
+
+func TestAggregateWritesEachDecisionOnce(t *testing.T) {
+	ctx := context.Background()
+	msgStore := NewMessageStoreFromRepository(inmem_repository.NewInMemoryRepository(nil), logrus.New())
+	writeDeposits(t, msgStore, 10)
+
+	agg, err := msgStore.CreateAggregate(getSnapshotProjectorOptions())
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	attempts := 0
+	err = agg.Handle(ctx, "account", uuid8, func(ctx context.Context, state interface{}) ([]Message, error) {
+		attempts++
+		if attempts == 1 {
+			writeDeposits(t, msgStore, 5) // another writer gets to the stream first
+		}
+		return []Message{
+			&Event{ID: NewID(), MessageType: "Withdrawn", Data: map[string]interface{}{"amount": 4}},
+			&Event{ID: NewID(), MessageType: "FeeCharged", Data: map[string]interface{}{"amount": 1}},
+		}, nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, attempts, "the conflict in memory starts the cycle over")
+	msgs, err := msgStore.Get(ctx, EventStream("account", uuid8))
+	assert.NoError(t, err)
+	var types []string
+	for _, msg := range msgs {
+		types = append(types, msg.Type())
+	}
+	assert.Equal(t, []string{"Deposited", "Deposited", "Withdrawn", "FeeCharged"}, types, "the events of the conflicting attempt aren't written")
+}
+
+func TestAggregateGivesUpAfterItsRetries(t *testing.T) {
+	tests := []struct {
+		name             string
+		opts             []AggregateOption
+		expectedAttempts int
+	}{{
+		name:             "retries 3 times by default",
+		expectedAttempts: 4,
+	}, {
+		name:             "retries as many times as asked",
+		opts:             []AggregateOption{AggregateRetries(1)},
+		expectedAttempts: 2,
+	}, {
+		name:             "doesn't retry when retries are off",
+		opts:             []AggregateOption{AggregateRetries(0)},
+		expectedAttempts: 1,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mock_repository.NewMockRepository(ctrl)
+			ctx := context.Background()
+
+			mockRepo.
+				EXPECT().
+				GetAllMessagesInStream(ctx, gomock.Any(), 1000).
+				Return([]*repository.MessageEnvelope{getSampleDeposit(0, 10)}, nil).
+				Times(test.expectedAttempts)
+			mockRepo.
+				EXPECT().
+				WriteMessagesWithExpectedPosition(ctx, gomock.Any(), int64(0)).
+				Return(errWrongExpectedVersion).
+				Times(test.expectedAttempts)
+
+			msgStore := NewMessageStoreFromRepository(mockRepo, logrus.New())
+			agg, err := msgStore.CreateAggregate(getBalanceProjectorOptions(), test.opts...)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			err = agg.Handle(ctx, "account", uuid8, withdraw(1))
+
+			assert.Equal(t, ErrExpectedVersionFailed, err)
+		})
+	}
+}
+
+func TestCreateAggregateFails(t *testing.T) {
+	tests := []struct {
+		name          string
+		projectorOpts []ProjectorOption
+		opts          []AggregateOption
+		expectedErr   error
+	}{{
+		name:          "with negative retries",
+		projectorOpts: getBalanceProjectorOptions(),
+		opts:          []AggregateOption{AggregateRetries(-1)},
+		expectedErr:   ErrInvalidAggregateRetries,
+	}, {
+		name:          "when the projector can't be created",
+		projectorOpts: []ProjectorOption{DefaultState(float64(0))},
+		expectedErr:   ErrProjectorNeedsAtLeastOneReducer,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			msgStore := NewMessageStoreFromRepository(mock_repository.NewMockRepository(ctrl), logrus.New())
+			agg, err := msgStore.CreateAggregate(test.projectorOpts, test.opts...)
+
+			assert.Equal(t, test.expectedErr, err)
+			assert.Nil(t, agg)
+		})
+	}
+}
@@ -0,0 +1,256 @@
+package gomessagestore
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+
+	"github.com/blackhatbrigade/gomessagestore/repository"
+	"github.com/vmihailenco/msgpack/v4"
+)
+
+// CodecKey is the metadata key holding the name of the codec a message's data was written with; messages without it are JSON
+// It is namespaced so it can't clash with metadata of the caller's own.
+const CodecKey = "gomessagestore.codec"
+
+// Codec encodes and decodes message data
+// Message DB keeps data in a jsonb column, so whatever Marshal returns must be valid JSON; wrap binary encodings with NewBinaryCodec.
+type Codec interface {
+	Name() string                               // recorded in metadata so reads know how to decode the data
+	Marshal(v interface{}) ([]byte, error)      // encodes data for the data column
+	Unmarshal(data []byte, v interface{}) error // decodes data from the data column
+}
+
+// JSONCodec writes data as plain JSON; it is the default for every message store
+var JSONCodec Codec = jsonCodec{}
+
+// MessagePackCodec writes data as base64 encoded MessagePack, which is denser than JSON for data with many numbers or repeated keys
+// Data is encoded from its JSON form, so registered types read back the same as with JSON.
+var MessagePackCodec = NewBinaryCodec("msgpack", marshalMessagePack, unmarshalMessagePack)
+
+type jsonCodec struct{}
+
+func (jsonCodec) Name() string {
+	return "json"
+}
+
+func (jsonCodec) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (jsonCodec) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+// marshalMessagePack encodes the JSON form of v, so types with MarshalJSON or MarshalText, such as uuid.UUID, keep their value
+func marshalMessagePack(v interface{}) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var value interface{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := msgpack.NewEncoder(&buf).UseCompactEncoding(true).Encode(numbersFromJSON(value)); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func unmarshalMessagePack(data []byte, v interface{}) error {
+	return msgpack.NewDecoder(bytes.NewReader(data)).UseJSONTag(true).Decode(v)
+}
+
+// numbersFromJSON swaps the json.Number values in maps and slices, which MessagePack would write as strings, for exact integers or floats
+func numbersFromJSON(v interface{}) interface{} {
+	switch value := v.(type) {
+	case json.Number:
+		if i, err := strconv.ParseInt(string(value), 10, 64); err == nil {
+			return i
+		}
+		if u, err := strconv.ParseUint(string(value), 10, 64); err == nil {
+			return u
+		}
+		if f, err := value.Float64(); err == nil {
+			return f
+		}
+		return string(value)
+	case map[string]interface{}:
+		converted := make(map[string]interface{}, len(value))
+		for key, item := range value {
+			converted[key] = numbersFromJSON(item)
+		}
+		return converted
+	case []interface{}:
+		converted := make([]interface{}, len(value))
+		for i, item := range value {
+			converted[i] = numbersFromJSON(item)
+		}
+		return converted
+	}
+
+	return v
+}
+
+// binaryCodec wraps a binary encoding, such as MessagePack or Protobuf, in a JSON object so it fits in the jsonb data column
+type binaryCodec struct {
+	name      string
+	marshal   func(v interface{}) ([]byte, error)
+	unmarshal func(data []byte, v interface{}) error
+}
+
+// binaryPayload is the JSON object binary codecs write; encoding/json stores the bytes as base64
+type binaryPayload struct {
+	Payload []byte `json:"payload"`
+}
+
+// NewBinaryCodec creates a Codec that stores the output of marshal as base64 inside a JSON object, such as {"payload":"gqNmb2/DpGJhcgE="}
+func NewBinaryCodec(name string, marshal func(v interface{}) ([]byte, error), unmarshal func(data []byte, v interface{}) error) Codec {
+	return &binaryCodec{
+		name:      name,
+		marshal:   marshal,
+		unmarshal: unmarshal,
+	}
+}
+
+func (codec *binaryCodec) Name() string {
+	return codec.name
+}
+
+func (codec *binaryCodec) Marshal(v interface{}) ([]byte, error) {
+	payload, err := codec.marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(binaryPayload{Payload: payload})
+}
+
+func (codec *binaryCodec) Unmarshal(data []byte, v interface{}) error {
+	wrapped := binaryPayload{}
+	if err := json.Unmarshal(data, &wrapped); err != nil {
+		return err
+	}
+
+	return codec.unmarshal(wrapped.Payload, v)
+}
+
+// codecs holds the codecs a message store writes with and can read
+type codecs struct {
+	byName        map[string]Codec
+	defaultCodec  Codec
+	byMessageType map[string]Codec
+}
+
+// newCodecs knows the codecs shipped with this package, and writes JSON
+func newCodecs() *codecs {
+	return &codecs{
+		byName: map[string]Codec{
+			JSONCodec.Name():        JSONCodec,
+			MessagePackCodec.Name(): MessagePackCodec,
+		},
+		defaultCodec:  JSONCodec,
+		byMessageType: make(map[string]Codec),
+	}
+}
+
+// forMessageType returns the codec messages of messageType are written with
+// Snapshots are always JSON, as their state is already JSON that other codecs can't carry through to the read.
+func (c *codecs) forMessageType(messageType string) Codec {
+	if messageType == SnapshotMessageType {
+		return JSONCodec
+	}
+	if codec, ok := c.byMessageType[messageType]; ok {
+		return codec
+	}
+
+	return c.defaultCodec
+}
+
+// encode re-encodes the data of an envelope that ToEnvelope wrote as JSON with the codec for its message type, and records the codec in its metadata
+func (c *codecs) encode(messageEnvelope *repository.MessageEnvelope, message Message) error {
+	codec := c.forMessageType(messageEnvelope.MessageType)
+	if codec.Name() == JSONCodec.Name() {
+		return nil // ToEnvelope already wrote JSON
+	}
+
+	value, ok := messageData(message)
+	if !ok {
+		if err := json.Unmarshal(messageEnvelope.Data, &value); err != nil {
+			return err
+		}
+	}
+	data, err := codec.Marshal(value)
+	if err != nil {
+		return err
+	}
+	if !json.Valid(data) {
+		return ErrCodecInvalidJSON
+	}
+
+	metadata, err := decodeMetadata(messageEnvelope.Metadata)
+	if err != nil {
+		return err
+	}
+	metadata[CodecKey] = codec.Name()
+	if messageEnvelope.Metadata, err = json.Marshal(metadata); err != nil {
+		return err
+	}
+
+	messageEnvelope.Data = data
+	return nil
+}
+
+// decode returns a copy of an envelope with its data turned back into JSON, so the converters and upcasters can read it
+// Envelopes written as JSON are returned as is; those written with a codec this store doesn't know are an error.
+func (c *codecs) decode(messageEnvelope *repository.MessageEnvelope) (*repository.MessageEnvelope, error) {
+	metadata, err := decodeMetadata(messageEnvelope.Metadata)
+	if err != nil {
+		return messageEnvelope, nil // without readable metadata there is no codec, so leave it to the converters as JSON
+	}
+	name, ok := metadata[CodecKey]
+	if !ok || name == JSONCodec.Name() {
+		return messageEnvelope, nil
+	}
+
+	nameString, _ := name.(string)
+	codec, ok := c.byName[nameString]
+	if !ok {
+		return nil, ErrUnknownCodec
+	}
+
+	var value interface{}
+	if err := codec.Unmarshal(messageEnvelope.Data, &value); err != nil {
+		return nil, err
+	}
+
+	decoded := *messageEnvelope
+	if decoded.Data, err = json.Marshal(value); err != nil {
+		return nil, err
+	}
+
+	return &decoded, nil
+}
+
+// messageData returns the data of the messages in this package as it was before ToEnvelope marshalled it
+func messageData(message Message) (interface{}, bool) {
+	switch msg := message.(type) {
+	case *Command:
+		return msg.Data, true
+	case *Event:
+		return msg.Data, true
+	case *StreamMessage:
+		return msg.Data, true
+	case *TypedMessage:
+		return msg.Data, true
+	}
+
+	return nil, false
+}
@@ -0,0 +1,328 @@
+package gomessagestore_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	. "github.com/blackhatbrigade/gomessagestore"
+	"github.com/blackhatbrigade/gomessagestore/inmem_repository"
+	"github.com/blackhatbrigade/gomessagestore/repository"
+	"github.com/blackhatbrigade/gomessagestore/repository/mocks"
+	"github.com/blackhatbrigade/gomessagestore/uuid"
+	"github.com/golang/mock/gomock"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+type telemetryReading struct {
+	SensorID    string  `json:"sensorId"`
+	Temperature float64 `json:"temperature"`
+	Samples     []int   `json:"samples"`
+}
+
+// accountOwned has a field with its own MarshalJSON, which MessagePack would otherwise encode by its Go fields
+type accountOwned struct {
+	AccountID uuid.UUID `json:"accountId"`
+	Owner     string    `json:"owner"`
+}
+
+// rawCodec writes bytes that aren't JSON, which Message DB can't store
+type rawCodec struct{}
+
+func (rawCodec) Name() string                               { return "raw" }
+func (rawCodec) Marshal(v interface{}) ([]byte, error)      { return []byte{0x81, 0xa1}, nil }
+func (rawCodec) Unmarshal(data []byte, v interface{}) error { return nil }
+
+func TestCodecsWriteJSON(t *testing.T) {
+	tests := []struct {
+		name  string
+		codec Codec
+	}{{
+		name:  "JSON",
+		codec: JSONCodec,
+	}, {
+		name:  "MessagePack",
+		codec: MessagePackCodec,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			reading := telemetryReading{SensorID: "s-1", Temperature: 21.5, Samples: []int{1, 2, 3}}
+
+			data, err := test.codec.Marshal(reading)
+			assert.NoError(t, err)
+			assert.True(t, json.Valid(data), "codec output must fit in a jsonb column: %s", data)
+
+			decoded := telemetryReading{}
+			assert.NoError(t, test.codec.Unmarshal(data, &decoded))
+			assert.Equal(t, reading, decoded)
+		})
+	}
+}
+
+func TestMessagePackCodecWrapsPayload(t *testing.T) {
+	data, err := MessagePackCodec.Marshal(map[string]interface{}{"a": 1})
+
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"payload":"gaFhAQ=="}`, string(data))
+}
+
+func TestWriteWithCodec(t *testing.T) {
+	tests := []struct {
+		name          string
+		opts          []MessageStoreOption
+		messageType   string
+		expectedCodec string
+	}{{
+		name:        "writes JSON by default",
+		messageType: "Reading",
+	}, {
+		name:          "writes with the store's codec",
+		opts:          []MessageStoreOption{WithCodec(MessagePackCodec)},
+		messageType:   "Reading",
+		expectedCodec: "msgpack",
+	}, {
+		name:          "writes with the message type's codec",
+		opts:          []MessageStoreOption{WithMessageTypeCodec("Reading", MessagePackCodec)},
+		messageType:   "Reading",
+		expectedCodec: "msgpack",
+	}, {
+		name:        "writes other message types with the store's codec",
+		opts:        []MessageStoreOption{WithMessageTypeCodec("Reading", MessagePackCodec)},
+		messageType: "Calibrated",
+	}, {
+		name:        "prefers the message type's codec over the store's",
+		opts:        []MessageStoreOption{WithCodec(MessagePackCodec), WithMessageTypeCodec("Reading", JSONCodec)},
+		messageType: "Reading",
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mock_repository.NewMockRepository(ctrl)
+			ctx := context.Background()
+
+			var written *repository.MessageEnvelope
+			mockRepo.
+				EXPECT().
+				WriteMessage(ctx, gomock.Any()).
+				Do(func(_ context.Context, msgEnv *repository.MessageEnvelope) {
+					written = msgEnv
+				})
+
+			msgStore := NewMessageStoreFromRepository(mockRepo, logrus.New(), test.opts...)
+			err := msgStore.Write(ctx, &Event{
+				ID:             uuid5,
+				EntityID:       uuid8,
+				StreamCategory: "sensor",
+				MessageType:    test.messageType,
+				Data:           map[string]interface{}{"temperature": 21.5},
+				Metadata:       map[string]interface{}{"correlationId": "abc"},
+			})
+
+			assert.NoError(t, err)
+			if !assert.NotNil(t, written) {
+				return
+			}
+			metadata := make(map[string]interface{})
+			assert.NoError(t, json.Unmarshal(written.Metadata, &metadata))
+			assert.Equal(t, "abc", metadata["correlationId"])
+			if test.expectedCodec == "" {
+				assert.NotContains(t, metadata, CodecKey)
+				assert.JSONEq(t, `{"temperature":21.5}`, string(written.Data))
+				return
+			}
+			assert.Equal(t, test.expectedCodec, metadata[CodecKey])
+			data := make(map[string]interface{})
+			assert.NoError(t, MessagePackCodec.Unmarshal(written.Data, &data))
+			assert.Equal(t, map[string]interface{}{"temperature": 21.5}, data)
+		})
+	}
+}
+
+func TestWriteFailsWhenCodecDoesNotWriteJSON(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockRepository(ctrl)
+
+	msgStore := NewMessageStoreFromRepository(mockRepo, logrus.New(), WithCodec(rawCodec{}))
+	err := msgStore.Write(context.Background(), getSampleEvent())
+
+	assert.Equal(t, ErrCodecInvalidJSON, err)
+}
+
+func TestGetDecodesCodecs(t *testing.T) {
+	packed, _ := MessagePackCodec.Marshal(map[string]interface{}{"sensorId": "s-1", "temperature": 21.5})
+
+	tests := []struct {
+		name         string
+		metadata     string
+		data         []byte
+		expectedData map[string]interface{}
+	}{{
+		name:         "reads JSON without a codec in metadata",
+		metadata:     `{}`,
+		data:         []byte(`{"sensorId":"s-1","temperature":21.5}`),
+		expectedData: map[string]interface{}{"sensorId": "s-1", "temperature": 21.5},
+	}, {
+		name:         "reads JSON with the codec in metadata",
+		metadata:     `{"gomessagestore.codec":"json"}`,
+		data:         []byte(`{"sensorId":"s-1","temperature":21.5}`),
+		expectedData: map[string]interface{}{"sensorId": "s-1", "temperature": 21.5},
+	}, {
+		name:         "reads MessagePack",
+		metadata:     `{"gomessagestore.codec":"msgpack"}`,
+		data:         packed,
+		expectedData: map[string]interface{}{"sensorId": "s-1", "temperature": 21.5},
+	}, {
+		name:         "reads JSON with a codec property of the caller's own in metadata",
+		metadata:     `{"codec":"msgpack"}`,
+		data:         []byte(`{"sensorId":"s-1","temperature":21.5}`),
+		expectedData: map[string]interface{}{"sensorId": "s-1", "temperature": 21.5},
+	}, {
+		name:     "skips data from a codec the store doesn't know",
+		metadata: `{"gomessagestore.codec":"protobuf"}`,
+		data:     []byte(`{"payload":"CgNzLTE="}`),
+	}, {
+		name:     "skips data the codec can't decode",
+		metadata: `{"gomessagestore.codec":"msgpack"}`,
+		data:     []byte(`{"payload":"not base64"}`),
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mock_repository.NewMockRepository(ctrl)
+			ctx := context.Background()
+
+			msgEnv := &repository.MessageEnvelope{
+				ID:             uuid5,
+				MessageType:    "Reading",
+				StreamName:     "sensor-" + uuid8.String(),
+				StreamCategory: "sensor",
+				Data:           test.data,
+				Metadata:       []byte(test.metadata),
+			}
+
+			mockRepo.
+				EXPECT().
+				GetAllMessagesInStream(ctx, msgEnv.StreamName, 1000).
+				Return([]*repository.MessageEnvelope{msgEnv}, nil)
+
+			msgStore := NewMessageStoreFromRepository(mockRepo, logrus.New())
+			msgs, err := msgStore.Get(ctx, EventStream("sensor", uuid8))
+
+			assert.NoError(t, err)
+			if test.expectedData == nil {
+				assert.Empty(t, msgs)
+				return
+			}
+			if assert.Len(t, msgs, 1) {
+				event, ok := msgs[0].(*Event)
+				if assert.True(t, ok, "expected an Event, got %T", msgs[0]) {
+					assert.Equal(t, test.expectedData, event.Data)
+				}
+			}
+		})
+	}
+}
+
+func TestCodecRoundTripWithRegistry(t *testing.T) {
+	registry := NewRegistry()
+	if err := registry.Register("Reading", telemetryReading{}); err != nil {
+		t.Fatalf("registering Reading failed: %s", err)
+	}
+	protobufish := NewBinaryCodec("custom", json.Marshal, json.Unmarshal) // stands in for a codec supplied by the caller
+
+	repo := inmem_repository.NewInMemoryRepository(nil)
+	writer := NewMessageStoreFromRepository(repo, logrus.New(), WithRegistry(registry), WithMessageTypeCodec("Reading", protobufish))
+	reading := telemetryReading{SensorID: "s-1", Temperature: 21.5, Samples: []int{4, 5}}
+	ctx := context.Background()
+
+	err := writer.Write(ctx, &TypedMessage{
+		ID:         uuid5,
+		StreamName: "sensor-" + uuid8.String(),
+		Data:       reading,
+	})
+	assert.NoError(t, err)
+
+	msgs, err := writer.Get(ctx, EventStream("sensor", uuid8))
+	assert.NoError(t, err)
+	if assert.Len(t, msgs, 1) {
+		typed, ok := msgs[0].(*TypedMessage)
+		if assert.True(t, ok, "expected a TypedMessage, got %T", msgs[0]) {
+			assert.Equal(t, reading, typed.Data)
+			assert.Equal(t, "custom", typed.Metadata[CodecKey])
+		}
+	}
+
+	reader := NewMessageStoreFromRepository(repo, logrus.New())
+	msgs, err = reader.Get(ctx, EventStream("sensor", uuid8))
+	assert.NoError(t, err)
+	assert.Empty(t, msgs, "a store without the codec can't read the data")
+
+	_, err = reader.Get(ctx, EventStream("sensor", uuid8), Strict())
+	assert.Equal(t, ErrUnknownCodec, err)
+}
+
+func TestMessagePackCodecKeepsValuesOfTypesWithMarshalers(t *testing.T) {
+	registry := NewRegistry()
+	if err := registry.Register("Owned", accountOwned{}); err != nil {
+		t.Fatalf("registering Owned failed: %s", err)
+	}
+
+	repo := inmem_repository.NewInMemoryRepository(nil)
+	msgStore := NewMessageStoreFromRepository(repo, logrus.New(), WithRegistry(registry), WithCodec(MessagePackCodec))
+	data := accountOwned{AccountID: uuid4, Owner: "ada"}
+	ctx := context.Background()
+
+	err := msgStore.Write(ctx, &TypedMessage{
+		ID:         uuid5,
+		StreamName: "account-" + uuid8.String(),
+		Data:       data,
+	})
+	assert.NoError(t, err)
+
+	msgs, err := msgStore.Get(ctx, EventStream("account", uuid8), Strict())
+	assert.NoError(t, err)
+	if assert.Len(t, msgs, 1) {
+		typed, ok := msgs[0].(*TypedMessage)
+		if assert.True(t, ok, "expected a TypedMessage, got %T", msgs[0]) {
+			assert.Equal(t, data, typed.Data)
+			assert.Equal(t, "msgpack", typed.Metadata[CodecKey])
+		}
+	}
+}
+
+func TestMessagePackCodecKeepsExactNumbers(t *testing.T) {
+	repo := inmem_repository.NewInMemoryRepository(nil)
+	msgStore := NewMessageStoreFromRepository(repo, logrus.New(), WithCodec(MessagePackCodec), WithExactNumbers())
+	ctx := context.Background()
+
+	data, err := Pack(largeNumbers{AccountID: 9007199254740993, Counter: 18446744073709551615}, ExactNumbers())
+	assert.NoError(t, err)
+
+	err = msgStore.Write(ctx, &Event{
+		ID:             uuid5,
+		EntityID:       uuid8,
+		StreamCategory: "account",
+		MessageType:    "Deposited",
+		Data:           data,
+	})
+	assert.NoError(t, err)
+
+	msgs, err := msgStore.Get(ctx, EventStream("account", uuid8))
+	assert.NoError(t, err)
+	if assert.Len(t, msgs, 1) {
+		numbers := largeNumbers{}
+		assert.NoError(t, Unpack(msgs[0].(*Event).Data, &numbers))
+		assert.Equal(t, int64(9007199254740993), numbers.AccountID)
+		assert.Equal(t, uint64(18446744073709551615), numbers.Counter)
+	}
+}
@@ -21,7 +21,8 @@ import "errors"
 //	ErrPositionVersionMissing                       |	./worker_getposition.go
 //	ErrSubscriberNeedsAtLeastOneMessageHandler      |	./subscriber.go
 //	ErrSubscriberCannotSubscribeToMultipleStreams   |	./subscriber_options.go
-//	ErrSubscriberCannotSubscribeToMultipleCategories|	./subscriber_options.go
+//	ErrSubscriberCannotSubscribeToMultipleCategories|	no uses
+//	ErrSubscriberCannotSubscribeToSameCategoryTwice |	./subscriber_options.go
 //	ErrProjectorNeedsAtLeastOneReducer              |	./projector.go
 //	ErrSubscriberMessageHandlerEqualToNil           |	./subscriber.go
 //	ErrSubscriberMessageHandlersEqualToNil          |	./subscriber.go
@@ -29,7 +30,7 @@ import "errors"
 //	ErrDefaultStateNotSet                           |	./projector.go
 //	ErrDefaultStateCannotBePointer                  |	./projector.go
 //	ErrGetMessagesCannotUseBothStreamAndCategory    |	./get.go
-//	ErrMessageNoID                                  |	./models.go | ./worker_getposition.go
+//	ErrMessageNoID                                  |	./models.go | ./worker_getposition.go | ./get_by_id.go
 //	ErrGetMessagesRequiresEitherStreamOrCategory    |	./get.go
 //	ErrGetLastRequiresStream                        |	./get.go
 //	ErrIncorrectNumberOfPositionsFound              |	no uses
@@ -43,11 +44,38 @@ import "errors"
 //	ErrInvalidEventStream                           |	./get.go
 //	ErrInvalidSubscriberID                          |	./subscriber.go
 //	ErrInvalidPositionStream                        |	./get.go | ./worker_getposition.go
-//	ErrMissingMessageCategoryID                     |	./models.go
+//	ErrMissingMessageCategoryID                     |	./models.go | ./get.go
 //	ErrMissingMessageData                           |	./models.go
 //	ErrUnserializableData                           |	./models.go | ./worker_getposition.go
 //	ErrDataIsNilPointer                             |	no uses
 //	ErrMissingGetOptions                            |	./get.go
+//	ErrInvalidSubscriberPosition                    |	./poller.go
+//	ErrInvalidMaxPollTime                           |	./subscriber_options.go
+//	ErrInvalidMaxPollErrorDelay                     |	./subscriber_options.go
+//	ErrInvalidPollJitter                            |	./subscriber_options.go
+//	ErrInvalidCheckpointInterval                    |	./subscriber_options.go
+//	ErrSubscriberAllStreamsCannotBeCombined         |	./subscriber_options.go
+//	ErrMessageNotFound                              |	./get_by_id.go
+//	ErrInvalidTimeRange                             |	./get.go
+//	ErrInvalidSubscriberStartTime                   |	./subscriber_options.go
+//	ErrEntityIDMismatch                             |	./models.go
+//	ErrMissingStreamName                            |	./models.go | ./get.go | ./registry.go
+//	ErrRegistryNilType                              |	./registry.go
+//	ErrRegistryTypeAlreadyRegistered                |	./registry.go
+//	ErrUnregisteredMessageData                      |	./registry.go | ./write.go
+//	ErrMissingUpcaster                              |	./upcast.go
+//	ErrUpcasterOutOfOrder                           |	./upcast.go
+//	ErrInvalidSchemaVersion                         |	./upcast.go | ./get_by_id.go
+//	ErrCodecInvalidJSON                             |	./codec.go | ./write.go
+//	ErrUnknownCodec                                 |	./codec.go
+//	ErrRequestTimedOut                              |	./request.go
+//	ErrMissingReplyStreamName                       |	./request.go
+//	ErrUnsupportedMessage                           |	./request.go | ./aggregate.go
+//	ErrInvalidAggregateRetries                      |	./aggregate.go
+//	ErrInvalidSnapshotInterval                      |	./projector.go
+//	ErrInvalidCacheSize                             |	./projector.go
+//	ErrMissingRunBoundary                           |	./projector.go
+//	ErrMetadataUnsupported                          |	./metadata.go | ./request.go
 var (
 	ErrInvalidOptionCombination                      = errors.New("Cannot have the current combination of options for Get()")
 	ErrSubscriberCannotUseBothStreamAndCategory      = errors.New("Subscriber function cannot use both Stream and Category")
@@ -63,6 +91,7 @@ var (
 	ErrSubscriberNeedsAtLeastOneMessageHandler       = errors.New("Subscriber needs at least one handler upon creation")
 	ErrSubscriberCannotSubscribeToMultipleStreams    = errors.New("Subscribers can only subscribe to one stream")
 	ErrSubscriberCannotSubscribeToMultipleCategories = errors.New("Subscribers can only subscribe to one category")
+	ErrSubscriberCannotSubscribeToSameCategoryTwice  = errors.New("Subscribers cannot subscribe to the same category more than once")
 	ErrProjectorNeedsAtLeastOneReducer               = errors.New("Projector needs at least one reducer upon creation")
 	ErrSubscriberMessageHandlerEqualToNil            = errors.New("Subscriber Message Handler cannot be equal to nil")
 	ErrSubscriberMessageHandlersEqualToNil           = errors.New("Subscriber Message Handler array cannot be equal to nil")
@@ -90,4 +119,31 @@ var (
 	ErrDataIsNilPointer                              = errors.New("Message data is a nil pointer")
 	ErrMissingGetOptions                             = errors.New("Options are required for the Get command")
 	ErrExpectedVersionFailed                         = errors.New("Provided version does not match the expected version")
+	ErrInvalidSubscriberPosition                     = errors.New("Subscriber position cannot be negative")
+	ErrInvalidMaxPollTime                            = errors.New("Invalid Subscriber max poll time provided, can not be less than the poll time")
+	ErrInvalidMaxPollErrorDelay                      = errors.New("Invalid Subscriber max poll error delay provided, can not be less than the poll error delay")
+	ErrInvalidPollJitter                             = errors.New("Invalid Subscriber poll jitter provided, must be at least 0 and less than 1")
+	ErrInvalidCheckpointInterval                     = errors.New("Invalid Subscriber checkpoint interval provided, can not be negative or zero")
+	ErrSubscriberAllStreamsCannotBeCombined          = errors.New("Subscribers to all streams cannot also subscribe to a stream or category")
+	ErrMessageNotFound                               = errors.New("No message was found with the given ID")
+	ErrInvalidTimeRange                              = errors.New("Time range must have non-zero times, with the end after the start")
+	ErrInvalidSubscriberStartTime                    = errors.New("Invalid Subscriber start time provided, can not be zero")
+	ErrEntityIDMismatch                              = errors.New("EntityID and StreamID must name the same entity when both are set")
+	ErrMissingStreamName                             = errors.New("Stream name cannot be blank")
+	ErrRegistryNilType                               = errors.New("Registry cannot register a nil type")
+	ErrRegistryTypeAlreadyRegistered                 = errors.New("Registry already has the message type or Go type registered")
+	ErrUnregisteredMessageData                       = errors.New("Message data is not a registered type, so its message type is unknown")
+	ErrMissingUpcaster                               = errors.New("Upcaster cannot be nil")
+	ErrUpcasterOutOfOrder                            = errors.New("Upcasters must be registered in order, starting from schema version 1")
+	ErrInvalidSchemaVersion                          = errors.New("Schema version in metadata must be a whole number of at least 1")
+	ErrCodecInvalidJSON                              = errors.New("Codec must encode data as valid JSON, wrap binary encodings with NewBinaryCodec")
+	ErrUnknownCodec                                  = errors.New("Message data was written with a codec this store doesn't know")
+	ErrRequestTimedOut                               = errors.New("No reply arrived before the request timed out")
+	ErrMissingReplyStreamName                        = errors.New("Message has no replyStreamName in its metadata to reply to")
+	ErrUnsupportedMessage                            = errors.New("Message must be an Event, StreamMessage or TypedMessage to be written to a given stream")
+	ErrInvalidAggregateRetries                       = errors.New("Aggregate retries cannot be negative")
+	ErrInvalidSnapshotInterval                       = errors.New("Snapshot interval cannot be negative")
+	ErrInvalidCacheSize                              = errors.New("Projector cache size cannot be negative")
+	ErrMissingRunBoundary                            = errors.New("RunUntil needs a boundary from UntilVersion, UntilPosition or UntilTime")
+	ErrMetadataUnsupported                           = errors.New("Message must be a Command, Event, StreamMessage or TypedMessage to have metadata added")
 )
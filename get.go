@@ -2,34 +2,65 @@ package gomessagestore
 
 import (
 	"context"
-	"fmt"
 	"strings"
+	"time"
 
 	"github.com/blackhatbrigade/gomessagestore/repository"
+	"github.com/blackhatbrigade/gomessagestore/streamname"
 	"github.com/blackhatbrigade/gomessagestore/uuid"
 	"github.com/sirupsen/logrus"
 )
 
 type getOpts struct {
-	stream        *string            // when set, only messages from the specified stream are retrieved
-	category      *string            // when set, only messages from the specified category are retrieved
-	sincePosition bool               // when set to true, only messages that occured after the specified position (since) for the category are retrieved; invalid for use with streams
-	sinceVersion  bool               // when set to true, only messages that occured since teh specified version (since) for the stream are retrieved; invalid for use with categories
-	since         *int64             // the position or version after which messages will be retrieved
-	converters    []MessageConverter // convert non-command/event messages
-	batchsize     int                // the number of messages to retrieve each round
-	last          bool               // when set to true, retrieves the last message in the specified stream; invalid if stream is unspecified or since is not nil
+	stream         *string            // when set, only messages from the specified stream are retrieved
+	category       *string            // when set, only messages from the specified category are retrieved
+	categories     []string           // when set, messages from any of the specified categories are retrieved in global order
+	allStreams     bool               // when set to true, messages from every stream in the store are retrieved in global order
+	sincePosition  bool               // when set to true, only messages that occured after the specified position (since) for the category are retrieved; invalid for use with streams
+	sinceVersion   bool               // when set to true, only messages that occured since teh specified version (since) for the stream are retrieved; invalid for use with categories
+	since          *int64             // the position or version after which messages will be retrieved
+	converters     []MessageConverter // convert non-command/event messages
+	batchsize      int                // the number of messages to retrieve each round
+	last           bool               // when set to true, retrieves the last message in the specified stream; invalid if stream is unspecified or since is not nil
+	backward       bool               // when set to true, messages are retrieved newest first; invalid with since, last, categories or all streams
+	beforeVersion  bool               // when set to true, only messages before the specified version (before) for the stream are retrieved; invalid for use with categories
+	beforePosition bool               // when set to true, only messages before the specified position (before) for the category are retrieved; invalid for use with streams
+	before         *int64             // the position or version before which messages will be retrieved; requires backward
+	sinceTime      time.Time          // when set, only messages written at or after this time are retrieved; invalid with since, last, backward or categories
+	untilTime      time.Time          // when set, only messages written before this time are retrieved; invalid with last, backward or categories
+	where          *repository.Filter // when set, only messages matching the filter are retrieved; invalid with last, backward, categories, all streams or a time range
+	streamMessages bool               // when set to true, messages that no converter claims are returned as StreamMessages rather than commands or events
+	read           *ReadBatch         // when set, filled in with what was read from the repository
+	strict         bool               // when set to true, a message that can't be decoded or upcast fails the read rather than being skipped
+}
+
+// ReadBatch reports on the envelopes a Get read from the repository, counting any that were skipped or that no converter claimed
+type ReadBatch struct {
+	Count        int       // the number of envelopes read
+	LastVersion  int64     // the stream version of the last envelope read
+	LastPosition int64     // the global position of the last envelope read
+	LastTime     time.Time // when the last envelope read was written
 }
 
 // GetOption provide optional arguments to the Get function
 // Invalid combinations:
-// EventStream() and/or CommandStream() are called more than once
-// EventStream()/CommandStream() and Category() are both called
-// EventStream()/CommandStream() and Category() are both not called
-// Last() is called and EventStream()/CommandStream is not called
+// EventStream()/EventStreamID()/CommandStream()/EntityCommandStream()/EntityCommandStreamID()/Stream() are called more than once
+// any of those streams and Category()/Categories() are both called
+// EventStream()/CommandStream(), Category()/Categories() and AllStreams() are all not called
+// Category() and Categories() are both called
+// AllStreams() and any of EventStream()/CommandStream()/Category()/Categories() are both called
+// Last() is called and EventStream()/CommandStream()/AllStreams() is not called
 // Last() and SincePosition()/SinceVersion() are both called
 // SincePosition() and eventStream()/CommandStream() are both called
 // SinceVersion() and eventStream()/CommandStream() are both called
+// Backward() and any of SincePosition()/SinceVersion()/Last()/Categories()/AllStreams() are both called
+// BeforeVersion()/BeforePosition() is called and Backward() is not called
+// BeforeVersion() and Category() are both called
+// BeforePosition() and EventStream()/CommandStream() are both called
+// Since() and SincePosition()/SinceVersion() are both called
+// Since()/Until() and any of Last()/Backward()/Categories() are both called
+// Until() is not after Since()
+// Where() and any of Last()/Backward()/Categories()/AllStreams()/Since()/Until() are both called
 type GetOption func(g *getOpts) error
 
 // checkGetOptions returns the supplied options
@@ -69,18 +100,46 @@ func (ms *msgStore) Get(ctx context.Context, opts ...GetOption) ([]Message, erro
 		return nil, err
 	}
 
-	return MsgEnvelopesToMessages(msgEnvelopes, getOptions.converters...), nil
+	if getOptions.read != nil {
+		*getOptions.read = readBatchOf(msgEnvelopes)
+	}
+
+	converters := ms.converters(getOptions.converters)
+	if getOptions.streamMessages {
+		converters = append(converters, withExactNumbers(convertEnvelopeToStreamMessage, ms.exactNumbers)) // ahead of the default converters
+	}
+
+	return msgEnvelopesToMessages(msgEnvelopes, ms.codecs, ms.registry, ms.exactNumbers, getOptions.strict, converters...)
+}
+
+// readBatchOf describes the envelopes read from the repository
+func readBatchOf(msgEnvelopes []*repository.MessageEnvelope) ReadBatch {
+	read := ReadBatch{Count: len(msgEnvelopes)}
+	if read.Count > 0 && msgEnvelopes[read.Count-1] != nil {
+		last := msgEnvelopes[read.Count-1]
+		read.LastVersion = last.Version
+		read.LastPosition = last.GlobalPosition
+		read.LastTime = last.Time
+	}
+
+	return read
 }
 
 // Ensure that only proper combinations of getOpts are provided.
 // See getOpts for more info regarding these checks
 func validateGetParams(getOptions *getOpts) error {
-	if getOptions.stream != nil && getOptions.category != nil {
+	if getOptions.stream != nil && (getOptions.category != nil || getOptions.categories != nil) {
 		return ErrGetMessagesCannotUseBothStreamAndCategory
-	} else if getOptions.stream == nil && getOptions.category == nil {
+	} else if getOptions.stream == nil && getOptions.category == nil && getOptions.categories == nil && !getOptions.allStreams {
 		return ErrGetMessagesRequiresEitherStreamOrCategory
 	}
-	if getOptions.last && getOptions.stream == nil {
+	if getOptions.allStreams && (getOptions.stream != nil || getOptions.category != nil || getOptions.categories != nil) {
+		return ErrInvalidOptionCombination
+	}
+	if getOptions.category != nil && getOptions.categories != nil {
+		return ErrInvalidOptionCombination
+	}
+	if getOptions.last && getOptions.stream == nil && !getOptions.allStreams {
 		return ErrGetLastRequiresStream
 	}
 	if getOptions.last && getOptions.since != nil {
@@ -89,8 +148,32 @@ func validateGetParams(getOptions *getOpts) error {
 	if getOptions.stream != nil && getOptions.sincePosition {
 		return ErrInvalidOptionCombination // need to use SinceVersion with Streams
 	}
-	if getOptions.category != nil && getOptions.sinceVersion {
-		return ErrInvalidOptionCombination // need to use SincePosition with Categories
+	if (getOptions.category != nil || getOptions.categories != nil || getOptions.allStreams) && getOptions.sinceVersion {
+		return ErrInvalidOptionCombination // need to use SincePosition with Categories and AllStreams
+	}
+	if getOptions.backward && (getOptions.since != nil || getOptions.last || getOptions.categories != nil || getOptions.allStreams) {
+		return ErrInvalidOptionCombination
+	}
+	if getOptions.before != nil && !getOptions.backward {
+		return ErrInvalidOptionCombination // only backward reads have an upper bound
+	}
+	if getOptions.stream != nil && getOptions.beforePosition {
+		return ErrInvalidOptionCombination // need to use BeforeVersion with Streams
+	}
+	if getOptions.category != nil && getOptions.beforeVersion {
+		return ErrInvalidOptionCombination // need to use BeforePosition with Categories
+	}
+	if hasTimeRange(getOptions) && (getOptions.last || getOptions.backward || getOptions.categories != nil) {
+		return ErrInvalidOptionCombination
+	}
+	if !getOptions.sinceTime.IsZero() && getOptions.since != nil {
+		return ErrInvalidOptionCombination // a time and a position/version can't both be where to start
+	}
+	if !getOptions.sinceTime.IsZero() && !getOptions.untilTime.IsZero() && !getOptions.untilTime.After(getOptions.sinceTime) {
+		return ErrInvalidTimeRange
+	}
+	if getOptions.where != nil && (getOptions.last || getOptions.backward || getOptions.categories != nil || getOptions.allStreams || hasTimeRange(getOptions)) {
+		return ErrInvalidOptionCombination // use repository.TimeRange() to filter on time as well
 	}
 
 	return nil
@@ -98,6 +181,63 @@ func validateGetParams(getOptions *getOpts) error {
 
 // callCorrectRepositoryGetFunction uses the getOptions to determine which function should be called to retrieve the correct messages.
 func (ms *msgStore) callCorrectRepositoryGetFunction(ctx context.Context, getOptions *getOpts) (msgEnvelopes []*repository.MessageEnvelope, err error) {
+	if hasTimeRange(getOptions) {
+		var since int64
+		if getOptions.since != nil {
+			since = *getOptions.since
+		}
+		switch {
+		case getOptions.stream != nil:
+			return ms.repo.GetAllMessagesInStreamBetween(ctx, *getOptions.stream, since, getOptions.sinceTime, getOptions.untilTime, getOptions.batchsize)
+		case getOptions.category != nil:
+			return ms.repo.GetAllMessagesInCategoryBetween(ctx, *getOptions.category, since, getOptions.sinceTime, getOptions.untilTime, getOptions.batchsize)
+		default:
+			return ms.repo.GetAllMessagesBetween(ctx, since, getOptions.sinceTime, getOptions.untilTime, getOptions.batchsize)
+		}
+	}
+
+	if getOptions.where != nil {
+		var since int64
+		if getOptions.since != nil {
+			since = *getOptions.since
+		}
+		if getOptions.stream != nil {
+			return ms.repo.GetAllMessagesInStreamWhere(ctx, *getOptions.stream, since, *getOptions.where, getOptions.batchsize)
+		}
+		return ms.repo.GetAllMessagesInCategoryWhere(ctx, *getOptions.category, since, *getOptions.where, getOptions.batchsize)
+	}
+
+	if getOptions.last && getOptions.allStreams {
+		var msg *repository.MessageEnvelope
+		msg, err = ms.repo.GetLastMessage(ctx)
+		if msg != nil {
+			msgEnvelopes = []*repository.MessageEnvelope{msg}
+		}
+		return
+	}
+
+	if getOptions.categories != nil || getOptions.allStreams {
+		var since int64
+		if getOptions.since != nil {
+			since = *getOptions.since
+		}
+		if getOptions.allStreams {
+			return ms.repo.GetAllMessagesSince(ctx, since, getOptions.batchsize)
+		}
+		return ms.repo.GetAllMessagesInCategoriesSince(ctx, getOptions.categories, since, getOptions.batchsize)
+	}
+
+	if getOptions.backward {
+		var before int64 = -1 // start at the newest message
+		if getOptions.before != nil {
+			before = *getOptions.before
+		}
+		if getOptions.stream != nil {
+			return ms.repo.GetMessagesInStreamBackward(ctx, *getOptions.stream, before, getOptions.batchsize)
+		}
+		return ms.repo.GetMessagesInCategoryBackward(ctx, *getOptions.category, before, getOptions.batchsize)
+	}
+
 	if getOptions.since != nil {
 		if getOptions.stream != nil {
 			msgEnvelopes, err = ms.repo.GetAllMessagesInStreamSince(ctx, *getOptions.stream, *getOptions.since, getOptions.batchsize)
@@ -133,10 +273,33 @@ func CommandStream(category string) GetOption {
 		if g.stream != nil {
 			return ErrInvalidOptionCombination
 		}
-		if strings.Contains(category, "-") {
+		if !streamname.IsCategory(category) {
+			return ErrInvalidCommandStream
+		}
+		stream := streamname.AddTypes(category, streamname.CommandType)
+		g.stream = &stream
+		return nil
+	}
+}
+
+// EntityCommandStream allows for getting the commands for a single entity, written with Command.EntityID set
+func EntityCommandStream(category string, entityID uuid.UUID) GetOption {
+	return EntityCommandStreamID(category, entityID.String())
+}
+
+// EntityCommandStreamID allows for getting the commands for a single entity whose ID is not a UUID, written with Command.StreamID set
+func EntityCommandStreamID(category string, streamID string) GetOption {
+	return func(g *getOpts) error {
+		if g.stream != nil {
+			return ErrInvalidOptionCombination
+		}
+		if !streamname.IsCategory(category) {
 			return ErrInvalidCommandStream
 		}
-		stream := fmt.Sprintf("%s:command", category)
+		if streamID == "" {
+			return ErrMissingMessageCategoryID
+		}
+		stream := streamname.Compose(streamname.AddTypes(category, streamname.CommandType), streamID)
 		g.stream = &stream
 		return nil
 	}
@@ -144,14 +307,22 @@ func CommandStream(category string) GetOption {
 
 // EventStream allows for getting events in a specific stream
 func EventStream(category string, entityID uuid.UUID) GetOption {
+	return EventStreamID(category, entityID.String())
+}
+
+// EventStreamID allows for getting events in a specific stream whose entity ID is not a UUID, such as order-ORD123
+func EventStreamID(category string, streamID string) GetOption {
 	return func(g *getOpts) error {
 		if g.stream != nil {
 			return ErrInvalidOptionCombination
 		}
-		if strings.Contains(category, "-") {
+		if !streamname.IsCategory(category) {
 			return ErrInvalidEventStream
 		}
-		stream := fmt.Sprintf("%s-%s", category, entityID)
+		if streamID == "" {
+			return ErrMissingMessageCategoryID
+		}
+		stream := streamname.Compose(category, streamID)
 		g.stream = &stream
 		return nil
 	}
@@ -163,7 +334,7 @@ func Category(category string) GetOption {
 		if g.category != nil {
 			return ErrInvalidOptionCombination
 		}
-		if strings.Contains(category, "-") {
+		if !streamname.IsCategory(category) {
 			return ErrInvalidMessageCategory
 		}
 		g.category = &category
@@ -171,6 +342,48 @@ func Category(category string) GetOption {
 	}
 }
 
+// Categories allows for getting messages from several categories at once, merged in global position order
+func Categories(categories ...string) GetOption {
+	return func(g *getOpts) error {
+		if g.categories != nil || len(categories) == 0 {
+			return ErrInvalidOptionCombination
+		}
+		for _, category := range categories {
+			if !streamname.IsCategory(category) {
+				return ErrInvalidMessageCategory
+			}
+		}
+		g.categories = categories
+		return nil
+	}
+}
+
+// AllStreams allows for getting messages from every stream in the store, in global position order
+func AllStreams() GetOption {
+	return func(g *getOpts) error {
+		if g.allStreams {
+			return ErrInvalidOptionCombination
+		}
+		g.allStreams = true
+		return nil
+	}
+}
+
+// Stream allows for getting messages from any stream by its exact name, such as account:snapshot-123; they are returned as StreamMessages unless a Converter() claims them
+func Stream(streamName string) GetOption {
+	return func(g *getOpts) error {
+		if g.stream != nil {
+			return ErrInvalidOptionCombination
+		}
+		if streamName == "" {
+			return ErrMissingStreamName
+		}
+		g.stream = &streamName
+		g.streamMessages = true
+		return nil
+	}
+}
+
 // PositionStream allows for getting messages by position subscriber
 func PositionStream(subscriberID string) GetOption {
 	return func(g *getOpts) error {
@@ -180,7 +393,7 @@ func PositionStream(subscriberID string) GetOption {
 		if strings.Contains(subscriberID, "-") {
 			return ErrInvalidPositionStream
 		}
-		stream := fmt.Sprintf("%s+position", subscriberID)
+		stream := positionStream(subscriberID)
 		g.stream = &stream
 		return nil
 	}
@@ -221,6 +434,74 @@ func SinceVersion(version int64) GetOption {
 	}
 }
 
+// Backward allows for getting messages newest first, such as the latest events for an entity; use BeforeVersion()/BeforePosition() to page further back
+func Backward() GetOption {
+	return func(g *getOpts) error {
+		if g.backward {
+			return ErrInvalidOptionCombination
+		}
+		g.backward = true
+		return nil
+	}
+}
+
+// BeforeVersion allows for getting only older messages in a stream when reading Backward()
+func BeforeVersion(version int64) GetOption {
+	return func(g *getOpts) error {
+		if g.before != nil || version < 0 {
+			return ErrInvalidOptionCombination
+		}
+		g.before = &version
+		g.beforeVersion = true
+		return nil
+	}
+}
+
+// BeforePosition allows for getting only older messages in a category when reading Backward()
+func BeforePosition(position int64) GetOption {
+	return func(g *getOpts) error {
+		if g.before != nil || position < 0 {
+			return ErrInvalidOptionCombination
+		}
+		g.before = &position
+		g.beforePosition = true
+		return nil
+	}
+}
+
+// Since allows for getting only messages written at or after a time; it can't be combined with SincePosition()/SinceVersion(), so page on through a time range with those and Until() alone
+func Since(since time.Time) GetOption {
+	return func(g *getOpts) error {
+		if !g.sinceTime.IsZero() {
+			return ErrInvalidOptionCombination
+		}
+		if since.IsZero() {
+			return ErrInvalidTimeRange
+		}
+		g.sinceTime = since
+		return nil
+	}
+}
+
+// Until allows for getting only messages written before a time
+func Until(until time.Time) GetOption {
+	return func(g *getOpts) error {
+		if !g.untilTime.IsZero() {
+			return ErrInvalidOptionCombination
+		}
+		if until.IsZero() {
+			return ErrInvalidTimeRange
+		}
+		g.untilTime = until
+		return nil
+	}
+}
+
+// hasTimeRange reports whether Since() or Until() was used
+func hasTimeRange(getOptions *getOpts) bool {
+	return !getOptions.sinceTime.IsZero() || !getOptions.untilTime.IsZero()
+}
+
 //Converter allows for automatic converting of non-Command/Event type messages
 func Converter(converter MessageConverter) GetOption {
 	return func(g *getOpts) error {
@@ -229,6 +510,22 @@ func Converter(converter MessageConverter) GetOption {
 	}
 }
 
+// ReportRead has Get fill in read with how many envelopes it read and where the last of them is, including any it skipped; page with it, as fewer messages than envelopes may be returned
+func ReportRead(read *ReadBatch) GetOption {
+	return func(g *getOpts) error {
+		g.read = read
+		return nil
+	}
+}
+
+// Strict has Get fail with the error of the first message that can't be decoded or upcast, rather than logging and skipping it; projectors read with it, so a state never silently misses a message
+func Strict() GetOption {
+	return func(g *getOpts) error {
+		g.strict = true
+		return nil
+	}
+}
+
 //BatchSize changes how many messages are returned (default 1000)
 func BatchSize(batchsize int) GetOption {
 	return func(g *getOpts) error {
@@ -236,3 +533,18 @@ func BatchSize(batchsize int) GetOption {
 		return nil
 	}
 }
+
+// Where allows for getting only messages matching a filter built with repository.MetadataEquals(), DataEquals(), TypeIn(), TimeRange() and And().
+// Postgres must have message_store.sql_condition enabled, as the filter is passed to Message DB as its condition.
+func Where(filter repository.Filter) GetOption {
+	return func(g *getOpts) error {
+		if g.where != nil {
+			return ErrInvalidOptionCombination
+		}
+		if _, err := filter.Condition(); err != nil {
+			return err
+		}
+		g.where = &filter
+		return nil
+	}
+}
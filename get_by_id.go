@@ -0,0 +1,36 @@
+package gomessagestore
+
+import (
+	"context"
+
+	"github.com/blackhatbrigade/gomessagestore/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// GetByID retrieves a single message from the message store by its ID, such as the causationMessageId in another message's metadata.
+// The message is converted with the supplied converters, the registry if the store has one, and then the default Command/Event converters.
+func (ms *msgStore) GetByID(ctx context.Context, id uuid.UUID, converters ...MessageConverter) (Message, error) {
+	if id == NilUUID {
+		return nil, ErrMessageNoID
+	}
+
+	msgEnvelope, err := ms.repo.GetMessageByID(ctx, id)
+	if err != nil {
+		logrus.WithError(err).Error("GetByID: Error getting message")
+
+		return nil, err
+	}
+	if msgEnvelope == nil {
+		return nil, ErrMessageNotFound
+	}
+
+	converters = append(ms.converters(converters), defaultConverters(ms.exactNumbers)...)
+	msg, err := msgEnvelopeToMessage(msgEnvelope, ms.codecs, ms.registry, ms.exactNumbers, converters)
+	if err != nil {
+		logrus.WithError(err).Error("GetByID: Can't decode or upcast message envelope")
+
+		return nil, err
+	}
+
+	return msg, nil // the default Event converter always succeeds
+}
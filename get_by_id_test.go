@@ -0,0 +1,94 @@
+package gomessagestore_test
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/blackhatbrigade/gomessagestore"
+	"github.com/blackhatbrigade/gomessagestore/repository"
+	"github.com/blackhatbrigade/gomessagestore/repository/mocks"
+	"github.com/blackhatbrigade/gomessagestore/uuid"
+	"github.com/golang/mock/gomock"
+	"github.com/sirupsen/logrus"
+)
+
+func TestGetByID(t *testing.T) {
+	tests := []struct {
+		name            string
+		id              uuid.UUID
+		converters      []MessageConverter
+		repoEnvelope    *repository.MessageEnvelope
+		repoError       error
+		skipRepo        bool
+		expectedError   error
+		expectedEvent   *Event
+		expectedCommand *Command
+		expectedOther   *otherMessage
+	}{{
+		name:          "returns an event",
+		id:            getSampleEvent().ID,
+		repoEnvelope:  getSampleEventAsEnvelope(),
+		expectedEvent: getSampleEvent(),
+	}, {
+		name:            "returns a command",
+		id:              getSampleCommand().ID,
+		repoEnvelope:    getSampleCommandAsEnvelope(),
+		expectedCommand: getSampleCommand(),
+	}, {
+		name:          "uses the supplied converters first",
+		id:            getSampleOtherMessage().ID,
+		converters:    []MessageConverter{convertEnvelopeToOtherMessage},
+		repoEnvelope:  getSampleOtherMessageAsEnvelope(),
+		expectedOther: getSampleOtherMessage(),
+	}, {
+		name:          "returns a not found error when there is no message with the ID",
+		id:            uuid1,
+		expectedError: ErrMessageNotFound,
+	}, {
+		name:          "passes on repository errors",
+		id:            uuid1,
+		repoError:     potato,
+		expectedError: potato,
+	}, {
+		name:          "requires an ID",
+		id:            NilUUID,
+		skipRepo:      true,
+		expectedError: ErrMessageNoID,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mock_repository.NewMockRepository(ctrl)
+			ctx := context.Background()
+
+			if !test.skipRepo {
+				mockRepo.
+					EXPECT().
+					GetMessageByID(ctx, test.id).
+					Return(test.repoEnvelope, test.repoError)
+			}
+
+			msgStore := NewMessageStoreFromRepository(mockRepo, logrus.New())
+			msg, err := msgStore.GetByID(ctx, test.id, test.converters...)
+
+			if err != test.expectedError {
+				t.Errorf("Failed to get expected error from GetByID()\nExpected: %s\n and got: %s\n", test.expectedError, err)
+			}
+			if test.expectedEvent != nil {
+				assertMessageMatchesEvent(t, msg, test.expectedEvent)
+			}
+			if test.expectedCommand != nil {
+				assertMessageMatchesCommand(t, msg, test.expectedCommand)
+			}
+			if test.expectedOther != nil {
+				assertMessageMatchesOtherMessage(t, msg, test.expectedOther)
+			}
+			if test.expectedError != nil && msg != nil {
+				t.Errorf("Expected no message with an error, got %v", msg)
+			}
+		})
+	}
+}
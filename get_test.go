@@ -2,14 +2,17 @@ package gomessagestore_test
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"testing"
+	"time"
 
 	. "github.com/blackhatbrigade/gomessagestore"
 	"github.com/blackhatbrigade/gomessagestore/repository"
 	"github.com/blackhatbrigade/gomessagestore/repository/mocks"
 	"github.com/golang/mock/gomock"
 	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestGetWithCommandStream(t *testing.T) {
@@ -43,6 +46,122 @@ func TestGetWithCommandStream(t *testing.T) {
 	}
 }
 
+func TestGetWithEntityCommandStream(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockRepository(ctrl)
+
+	msg := getSampleEntityCommand()
+	ctx := context.Background()
+
+	msgEnv := getSampleEntityCommandAsEnvelope()
+
+	mockRepo.
+		EXPECT().
+		GetAllMessagesInStream(ctx, msgEnv.StreamName, 1000).
+		Return([]*repository.MessageEnvelope{msgEnv}, nil)
+
+	msgStore := NewMessageStoreFromRepository(mockRepo, logrus.New())
+	msgs, err := msgStore.Get(ctx, EntityCommandStream(msgEnv.StreamCategory, msg.EntityID))
+
+	if err != nil {
+		t.Error("An error has ocurred while getting messages from message store")
+	}
+	if len(msgs) != 1 {
+		t.Error("Incorrect number of messages returned")
+	} else {
+		assertMessageMatchesCommand(t, msgs[0], msg)
+	}
+}
+
+func TestGetWithStreamIDs(t *testing.T) {
+	tests := []struct {
+		name           string
+		opt            GetOption
+		expectedStream string
+	}{{
+		name:           "reads an event stream by its stream ID",
+		opt:            EventStreamID("order", "ORD123"),
+		expectedStream: "order-ORD123",
+	}, {
+		name:           "reads an entity command stream by its stream ID",
+		opt:            EntityCommandStreamID("account", "42"),
+		expectedStream: "account:command-42",
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mock_repository.NewMockRepository(ctrl)
+			ctx := context.Background()
+
+			mockRepo.
+				EXPECT().
+				GetAllMessagesInStream(ctx, test.expectedStream, 1000).
+				Return([]*repository.MessageEnvelope{}, nil)
+
+			msgStore := NewMessageStoreFromRepository(mockRepo, logrus.New())
+			_, err := msgStore.Get(ctx, test.opt)
+
+			if err != nil {
+				t.Errorf("An error has ocurred while getting messages from message store: %s", err)
+			}
+		})
+	}
+}
+
+func TestGetWithStream(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockRepository(ctrl)
+	ctx := context.Background()
+
+	msgEnv := getSampleStreamMessageAsEnvelope()
+
+	mockRepo.
+		EXPECT().
+		GetAllMessagesInStream(ctx, msgEnv.StreamName, 1000).
+		Return([]*repository.MessageEnvelope{msgEnv}, nil)
+
+	msgStore := NewMessageStoreFromRepository(mockRepo, logrus.New())
+	msgs, err := msgStore.Get(ctx, Stream(msgEnv.StreamName))
+
+	if err != nil {
+		t.Error("An error has ocurred while getting messages from message store")
+	}
+	assert.Equal(t, []Message{getSampleStreamMessage()}, msgs)
+}
+
+func TestGetWithStreamAndConverter(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockRepository(ctrl)
+	ctx := context.Background()
+
+	msgEnv := getSampleOtherMessageAsEnvelope()
+
+	mockRepo.
+		EXPECT().
+		GetAllMessagesInStream(ctx, msgEnv.StreamName, 1000).
+		Return([]*repository.MessageEnvelope{msgEnv}, nil)
+
+	msgStore := NewMessageStoreFromRepository(mockRepo, logrus.New())
+	msgs, err := msgStore.Get(ctx, Stream(msgEnv.StreamName), Converter(convertEnvelopeToOtherMessage))
+
+	if err != nil {
+		t.Error("An error has ocurred while getting messages from message store")
+	}
+	if len(msgs) != 1 {
+		t.Fatal("Incorrect number of messages returned")
+	}
+	assertMessageMatchesOtherMessage(t, msgs[0], getSampleOtherMessage())
+}
+
 func TestGetWithBatchSize(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -77,6 +196,54 @@ func TestGetWithBatchSize(t *testing.T) {
 	}
 }
 
+func TestGetReportsWhatWasRead(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockRepository(ctrl)
+	ctx := context.Background()
+
+	msgEnvs := getLotsOfSampleEventsAsEnvelopes(3, 100)
+	msgEnvs[2].Data = []byte(`{"payload":"not base64"}`)
+	msgEnvs[2].Metadata = []byte(`{"gomessagestore.codec":"msgpack"}`)
+
+	mockRepo.
+		EXPECT().
+		GetAllMessagesInCategorySince(ctx, "test cat", int64(600), 1000).
+		Return(msgEnvs, nil)
+
+	var read ReadBatch
+	msgStore := NewMessageStoreFromRepository(mockRepo, logrus.New())
+	msgs, err := msgStore.Get(ctx, Category("test cat"), SincePosition(600), ReportRead(&read))
+
+	assert.NoError(t, err)
+	assert.Len(t, msgs, 2, "the envelope that can't be decoded is skipped")
+	assert.Equal(t, ReadBatch{Count: 3, LastVersion: 106, LastPosition: 602, LastTime: time.Unix(1, 3)}, read, "but it is still counted")
+}
+
+func TestGetStrictFailsOnMessagesThatCantBeDecoded(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockRepository(ctrl)
+	ctx := context.Background()
+
+	msgEnvs := getLotsOfSampleEventsAsEnvelopes(3, 100)
+	msgEnvs[1].Data = []byte(`{"payload":"not base64"}`)
+	msgEnvs[1].Metadata = []byte(`{"gomessagestore.codec":"msgpack"}`)
+
+	mockRepo.
+		EXPECT().
+		GetAllMessagesInCategorySince(ctx, "test cat", int64(600), 1000).
+		Return(msgEnvs, nil)
+
+	msgStore := NewMessageStoreFromRepository(mockRepo, logrus.New())
+	msgs, err := msgStore.Get(ctx, Category("test cat"), SincePosition(600), Strict())
+
+	assert.Error(t, err)
+	assert.Nil(t, msgs)
+}
+
 func TestGetWithoutOptionsReturnsError(t *testing.T) {
 
 	ctrl := gomock.NewController(t)
@@ -191,6 +358,345 @@ func TestGetWithCategoryAndSince(t *testing.T) {
 	}
 }
 
+func TestGetWithCategories(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	var globalPosition int64 = 345
+
+	mockRepo := mock_repository.NewMockRepository(ctrl)
+
+	ctx := context.Background()
+	expectedEvents := getSampleEvents()
+	expectedCommand := getSampleCommands()[0]
+	msgEnvs := []*repository.MessageEnvelope{
+		getSampleEventsAsEnvelopes()[0],
+		getSampleCommandsAsEnvelopes()[0],
+	}
+
+	mockRepo.
+		EXPECT().
+		GetAllMessagesInCategoriesSince(ctx, []string{"test cat", "other cat"}, globalPosition, 1000).
+		Return(msgEnvs, nil)
+
+	msgStore := NewMessageStoreFromRepository(mockRepo, logrus.New())
+	msgs, err := msgStore.Get(
+		ctx,
+		Categories("test cat", "other cat"),
+		SincePosition(globalPosition),
+	)
+
+	if err != nil {
+		t.Error("An error has ocurred while getting messages from message store")
+	}
+	if len(msgs) != 2 {
+		t.Error("Incorrect number of messages returned")
+	} else {
+		assertMessageMatchesEvent(t, msgs[0], expectedEvents[0])
+		assertMessageMatchesCommand(t, msgs[1], expectedCommand)
+	}
+}
+
+func TestGetWithAllStreams(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	var globalPosition int64 = 345
+
+	mockRepo := mock_repository.NewMockRepository(ctrl)
+
+	ctx := context.Background()
+	expectedEvent := getSampleEvents()[0]
+	expectedCommand := getSampleCommands()[0]
+	msgEnvs := []*repository.MessageEnvelope{
+		getSampleEventsAsEnvelopes()[0],
+		getSampleCommandsAsEnvelopes()[0],
+	}
+
+	mockRepo.
+		EXPECT().
+		GetAllMessagesSince(ctx, globalPosition, 1000).
+		Return(msgEnvs, nil)
+
+	msgStore := NewMessageStoreFromRepository(mockRepo, logrus.New())
+	msgs, err := msgStore.Get(
+		ctx,
+		AllStreams(),
+		SincePosition(globalPosition),
+	)
+
+	if err != nil {
+		t.Error("An error has ocurred while getting messages from message store")
+	}
+	if len(msgs) != 2 {
+		t.Error("Incorrect number of messages returned")
+	} else {
+		assertMessageMatchesEvent(t, msgs[0], expectedEvent)
+		assertMessageMatchesCommand(t, msgs[1], expectedCommand)
+	}
+}
+
+func TestGetBackward(t *testing.T) {
+	tests := []struct {
+		name           string
+		opts           []GetOption
+		expectedStream string
+		expectedCat    string
+		expectedBefore int64
+		expectedBatch  int
+	}{{
+		name:           "reads a stream from the newest message",
+		opts:           []GetOption{EventStream("test cat", uuid9), Backward()},
+		expectedStream: "test cat-" + uuid9.String(),
+		expectedBefore: -1,
+		expectedBatch:  1000,
+	}, {
+		name:           "reads a stream before a version",
+		opts:           []GetOption{Backward(), EventStream("test cat", uuid9), BeforeVersion(12), BatchSize(50)},
+		expectedStream: "test cat-" + uuid9.String(),
+		expectedBefore: 12,
+		expectedBatch:  50,
+	}, {
+		name:           "reads a command stream before a version",
+		opts:           []GetOption{Backward(), CommandStream("test cat"), BeforeVersion(0)},
+		expectedStream: "test cat:command",
+		expectedBefore: 0,
+		expectedBatch:  1000,
+	}, {
+		name:           "reads a category from the newest message",
+		opts:           []GetOption{Category("test cat"), Backward()},
+		expectedCat:    "test cat",
+		expectedBefore: -1,
+		expectedBatch:  1000,
+	}, {
+		name:           "reads a category before a position",
+		opts:           []GetOption{Category("test cat"), Backward(), BeforePosition(345), BatchSize(2)},
+		expectedCat:    "test cat",
+		expectedBefore: 345,
+		expectedBatch:  2,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mock_repository.NewMockRepository(ctrl)
+			ctx := context.Background()
+
+			// the repository hands back the newest message first
+			msgEnvs := []*repository.MessageEnvelope{
+				getSampleEventsAsEnvelopes()[1],
+				getSampleEventsAsEnvelopes()[0],
+			}
+			if test.expectedStream != "" {
+				mockRepo.
+					EXPECT().
+					GetMessagesInStreamBackward(ctx, test.expectedStream, test.expectedBefore, test.expectedBatch).
+					Return(msgEnvs, nil)
+			} else {
+				mockRepo.
+					EXPECT().
+					GetMessagesInCategoryBackward(ctx, test.expectedCat, test.expectedBefore, test.expectedBatch).
+					Return(msgEnvs, nil)
+			}
+
+			msgStore := NewMessageStoreFromRepository(mockRepo, logrus.New())
+			msgs, err := msgStore.Get(ctx, test.opts...)
+
+			if err != nil {
+				t.Errorf("An error has ocurred while getting messages from message store: %s", err)
+			}
+			if len(msgs) != 2 {
+				t.Fatal("Incorrect number of messages returned")
+			}
+			if msgs[0].Position() != msgEnvs[0].GlobalPosition || msgs[1].Position() != msgEnvs[1].GlobalPosition {
+				t.Error("Messages are not in the order the repository returned them")
+			}
+		})
+	}
+}
+
+func TestGetWithTimeRange(t *testing.T) {
+	since := time.Unix(1000, 0)
+	until := time.Unix(2000, 0)
+
+	tests := []struct {
+		name             string
+		opts             []GetOption
+		expectedStream   string
+		expectedCategory string
+		expectedStart    int64
+		expectedSince    time.Time
+		expectedUntil    time.Time
+		expectedBatch    int
+	}{{
+		name:           "reads a stream between two times",
+		opts:           []GetOption{EventStream("test cat", uuid9), Since(since), Until(until)},
+		expectedStream: "test cat-" + uuid9.String(),
+		expectedSince:  since,
+		expectedUntil:  until,
+		expectedBatch:  1000,
+	}, {
+		name:           "reads a stream since a version until a time",
+		opts:           []GetOption{CommandStream("test cat"), SinceVersion(7), Until(until), BatchSize(2)},
+		expectedStream: "test cat:command",
+		expectedStart:  7,
+		expectedUntil:  until,
+		expectedBatch:  2,
+	}, {
+		name:             "reads a category since a time",
+		opts:             []GetOption{Category("test cat"), Since(since)},
+		expectedCategory: "test cat",
+		expectedSince:    since,
+		expectedBatch:    1000,
+	}, {
+		name:             "reads a category since a position until a time",
+		opts:             []GetOption{Category("test cat"), SincePosition(345), Until(until)},
+		expectedCategory: "test cat",
+		expectedStart:    345,
+		expectedUntil:    until,
+		expectedBatch:    1000,
+	}, {
+		name:          "reads every stream between two times",
+		opts:          []GetOption{AllStreams(), Since(since), Until(until), BatchSize(1)},
+		expectedSince: since,
+		expectedUntil: until,
+		expectedBatch: 1,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mock_repository.NewMockRepository(ctrl)
+			ctx := context.Background()
+
+			msgEnvs := []*repository.MessageEnvelope{getSampleEventAsEnvelope()}
+			switch {
+			case test.expectedStream != "":
+				mockRepo.
+					EXPECT().
+					GetAllMessagesInStreamBetween(ctx, test.expectedStream, test.expectedStart, test.expectedSince, test.expectedUntil, test.expectedBatch).
+					Return(msgEnvs, nil)
+			case test.expectedCategory != "":
+				mockRepo.
+					EXPECT().
+					GetAllMessagesInCategoryBetween(ctx, test.expectedCategory, test.expectedStart, test.expectedSince, test.expectedUntil, test.expectedBatch).
+					Return(msgEnvs, nil)
+			default:
+				mockRepo.
+					EXPECT().
+					GetAllMessagesBetween(ctx, test.expectedStart, test.expectedSince, test.expectedUntil, test.expectedBatch).
+					Return(msgEnvs, nil)
+			}
+
+			msgStore := NewMessageStoreFromRepository(mockRepo, logrus.New())
+			msgs, err := msgStore.Get(ctx, test.opts...)
+
+			if err != nil {
+				t.Errorf("An error has ocurred while getting messages from message store: %s", err)
+			}
+			if len(msgs) != 1 {
+				t.Fatal("Incorrect number of messages returned")
+			}
+			assertMessageMatchesEvent(t, msgs[0], getSampleEvent())
+		})
+	}
+}
+
+func TestGetLastWithAllStreams(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockRepository(ctrl)
+	ctx := context.Background()
+
+	mockRepo.
+		EXPECT().
+		GetLastMessage(ctx).
+		Return(getSampleEventAsEnvelope(), nil)
+
+	msgStore := NewMessageStoreFromRepository(mockRepo, logrus.New())
+	msgs, err := msgStore.Get(ctx, AllStreams(), Last())
+
+	if err != nil {
+		t.Error("An error has ocurred while getting messages from message store")
+	}
+	if len(msgs) != 1 {
+		t.Fatal("Incorrect number of messages returned")
+	}
+	assertMessageMatchesEvent(t, msgs[0], getSampleEvent())
+}
+
+func TestGetWhere(t *testing.T) {
+	filter := repository.And(repository.TypeIn("Event Type"), repository.MetadataEquals("userId", "bob"))
+
+	tests := []struct {
+		name             string
+		opts             []GetOption
+		expectedStream   string
+		expectedCategory string
+		expectedStart    int64
+		expectedBatch    int
+	}{{
+		name:           "reads a stream with a filter",
+		opts:           []GetOption{EventStream("test cat", uuid9), Where(filter)},
+		expectedStream: "test cat-" + uuid9.String(),
+		expectedBatch:  1000,
+	}, {
+		name:           "reads a stream since a version with a filter",
+		opts:           []GetOption{Where(filter), CommandStream("test cat"), SinceVersion(7), BatchSize(2)},
+		expectedStream: "test cat:command",
+		expectedStart:  7,
+		expectedBatch:  2,
+	}, {
+		name:             "reads a category with a filter",
+		opts:             []GetOption{Category("test cat"), Where(filter)},
+		expectedCategory: "test cat",
+		expectedBatch:    1000,
+	}, {
+		name:             "reads a category since a position with a filter",
+		opts:             []GetOption{Category("test cat"), SincePosition(345), Where(filter)},
+		expectedCategory: "test cat",
+		expectedStart:    345,
+		expectedBatch:    1000,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mock_repository.NewMockRepository(ctrl)
+			ctx := context.Background()
+
+			msgEnvs := []*repository.MessageEnvelope{getSampleEventAsEnvelope()}
+			if test.expectedStream != "" {
+				mockRepo.
+					EXPECT().
+					GetAllMessagesInStreamWhere(ctx, test.expectedStream, test.expectedStart, filter, test.expectedBatch).
+					Return(msgEnvs, nil)
+			} else {
+				mockRepo.
+					EXPECT().
+					GetAllMessagesInCategoryWhere(ctx, test.expectedCategory, test.expectedStart, filter, test.expectedBatch).
+					Return(msgEnvs, nil)
+			}
+
+			msgStore := NewMessageStoreFromRepository(mockRepo, logrus.New())
+			msgs, err := msgStore.Get(ctx, test.opts...)
+
+			if err != nil {
+				t.Errorf("An error has ocurred while getting messages from message store: %s", err)
+			}
+			if len(msgs) != 1 {
+				t.Fatal("Incorrect number of messages returned")
+			}
+			assertMessageMatchesEvent(t, msgs[0], getSampleEvent())
+		})
+	}
+}
+
 func TestGetMessagesCannotUseBothStreamAndCategory(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -531,11 +1037,18 @@ func TestOptionErrors(t *testing.T) {
 			EventStream("blah", uuid1),
 		},
 	}, {
-		name:          "Event Stream and Position Stream are both set",
+		name:          "Command Stream and Entity Command Stream are both set",
 		expectedError: ErrInvalidOptionCombination,
 		opts: []GetOption{
-			EventStream("blah", uuid1),
-			PositionStream("blah"),
+			CommandStream("yayaya"),
+			EntityCommandStream("yayaya", uuid1),
+		},
+	}, {
+		name:          "Event Stream and Position Stream are both set",
+		expectedError: ErrInvalidOptionCombination,
+		opts: []GetOption{
+			EventStream("blah", uuid1),
+			PositionStream("blah"),
 		},
 	}, {
 		name:          "Command Stream and Position Stream are both set",
@@ -544,6 +1057,334 @@ func TestOptionErrors(t *testing.T) {
 			CommandStream("blah"),
 			PositionStream("blah"),
 		},
+	}, {
+		name:          "Category and Categories are both set",
+		expectedError: ErrInvalidOptionCombination,
+		opts: []GetOption{
+			Category("yayaya"),
+			Categories("blah", "blah2"),
+		},
+	}, {
+		name:          "Categories is set twice",
+		expectedError: ErrInvalidOptionCombination,
+		opts: []GetOption{
+			Categories("yayaya"),
+			Categories("blah", "blah2"),
+		},
+	}, {
+		name:          "Categories is empty",
+		expectedError: ErrInvalidOptionCombination,
+		opts: []GetOption{
+			Categories(),
+		},
+	}, {
+		name:          "SinceVersion and Categories are both set",
+		expectedError: ErrInvalidOptionCombination,
+		opts: []GetOption{
+			SinceVersion(5),
+			Categories("yayaya", "blah"),
+		},
+	}, {
+		name:          "Categories and a stream are both set",
+		expectedError: ErrGetMessagesCannotUseBothStreamAndCategory,
+		opts: []GetOption{
+			Categories("yayaya", "blah"),
+			CommandStream("blah"),
+		},
+	}, {
+		name:          "AllStreams and Category are both set",
+		expectedError: ErrInvalidOptionCombination,
+		opts: []GetOption{
+			AllStreams(),
+			Category("yayaya"),
+		},
+	}, {
+		name:          "AllStreams and Categories are both set",
+		expectedError: ErrInvalidOptionCombination,
+		opts: []GetOption{
+			Categories("yayaya", "blah"),
+			AllStreams(),
+		},
+	}, {
+		name:          "AllStreams and a stream are both set",
+		expectedError: ErrInvalidOptionCombination,
+		opts: []GetOption{
+			AllStreams(),
+			EventStream("blah", uuid1),
+		},
+	}, {
+		name:          "AllStreams is set twice",
+		expectedError: ErrInvalidOptionCombination,
+		opts: []GetOption{
+			AllStreams(),
+			AllStreams(),
+		},
+	}, {
+		name:          "SinceVersion and AllStreams are both set",
+		expectedError: ErrInvalidOptionCombination,
+		opts: []GetOption{
+			SinceVersion(5),
+			AllStreams(),
+		},
+	}, {
+		name:          "Last and Categories are both set",
+		expectedError: ErrGetLastRequiresStream,
+		opts: []GetOption{
+			Last(),
+			Categories("yayaya", "blah"),
+		},
+	}, {
+		name:          "Last, AllStreams and SincePosition are all set",
+		expectedError: ErrInvalidOptionCombination,
+		opts: []GetOption{
+			Last(),
+			AllStreams(),
+			SincePosition(5),
+		},
+	}, {
+		name:          "Since is set twice",
+		expectedError: ErrInvalidOptionCombination,
+		opts: []GetOption{
+			Since(time.Unix(5, 0)),
+			Since(time.Unix(6, 0)),
+			Category("yayaya"),
+		},
+	}, {
+		name:          "Until is set twice",
+		expectedError: ErrInvalidOptionCombination,
+		opts: []GetOption{
+			Until(time.Unix(5, 0)),
+			Until(time.Unix(6, 0)),
+			Category("yayaya"),
+		},
+	}, {
+		name:          "Since is a zero time",
+		expectedError: ErrInvalidTimeRange,
+		opts: []GetOption{
+			Since(time.Time{}),
+			Category("yayaya"),
+		},
+	}, {
+		name:          "Until is a zero time",
+		expectedError: ErrInvalidTimeRange,
+		opts: []GetOption{
+			Until(time.Time{}),
+			Category("yayaya"),
+		},
+	}, {
+		name:          "Until is before Since",
+		expectedError: ErrInvalidTimeRange,
+		opts: []GetOption{
+			Since(time.Unix(6, 0)),
+			Until(time.Unix(5, 0)),
+			Category("yayaya"),
+		},
+	}, {
+		name:          "Until is the same as Since",
+		expectedError: ErrInvalidTimeRange,
+		opts: []GetOption{
+			Since(time.Unix(5, 0)),
+			Until(time.Unix(5, 0)),
+			Category("yayaya"),
+		},
+	}, {
+		name:          "Since and SincePosition are both set",
+		expectedError: ErrInvalidOptionCombination,
+		opts: []GetOption{
+			Since(time.Unix(5, 0)),
+			SincePosition(5),
+			Category("yayaya"),
+		},
+	}, {
+		name:          "Since and SinceVersion are both set",
+		expectedError: ErrInvalidOptionCombination,
+		opts: []GetOption{
+			SinceVersion(5),
+			Since(time.Unix(5, 0)),
+			CommandStream("yayaya"),
+		},
+	}, {
+		name:          "Since and Last are both set",
+		expectedError: ErrInvalidOptionCombination,
+		opts: []GetOption{
+			Since(time.Unix(5, 0)),
+			Last(),
+			CommandStream("yayaya"),
+		},
+	}, {
+		name:          "Until and Backward are both set",
+		expectedError: ErrInvalidOptionCombination,
+		opts: []GetOption{
+			Until(time.Unix(5, 0)),
+			Backward(),
+			CommandStream("yayaya"),
+		},
+	}, {
+		name:          "Since and Categories are both set",
+		expectedError: ErrInvalidOptionCombination,
+		opts: []GetOption{
+			Since(time.Unix(5, 0)),
+			Categories("yayaya", "blah"),
+		},
+	}, {
+		name:          "Where is set twice",
+		expectedError: ErrInvalidOptionCombination,
+		opts: []GetOption{
+			Where(repository.TypeIn("blah")),
+			Where(repository.TypeIn("blah")),
+			Category("yayaya"),
+		},
+	}, {
+		name:          "Where has an invalid filter",
+		expectedError: repository.ErrInvalidFilter,
+		opts: []GetOption{
+			Where(repository.And()),
+			Category("yayaya"),
+		},
+	}, {
+		name:          "Where has an unsafe filter",
+		expectedError: repository.ErrUnsafeFilterValue,
+		opts: []GetOption{
+			Where(repository.MetadataEquals("blah", "\x00")),
+			Category("yayaya"),
+		},
+	}, {
+		name:          "Where and Last are both set",
+		expectedError: ErrInvalidOptionCombination,
+		opts: []GetOption{
+			Where(repository.TypeIn("blah")),
+			Last(),
+			CommandStream("yayaya"),
+		},
+	}, {
+		name:          "Where and Backward are both set",
+		expectedError: ErrInvalidOptionCombination,
+		opts: []GetOption{
+			Where(repository.TypeIn("blah")),
+			Backward(),
+			Category("yayaya"),
+		},
+	}, {
+		name:          "Where and Categories are both set",
+		expectedError: ErrInvalidOptionCombination,
+		opts: []GetOption{
+			Where(repository.TypeIn("blah")),
+			Categories("yayaya", "blah"),
+		},
+	}, {
+		name:          "Where and AllStreams are both set",
+		expectedError: ErrInvalidOptionCombination,
+		opts: []GetOption{
+			Where(repository.TypeIn("blah")),
+			AllStreams(),
+		},
+	}, {
+		name:          "Where and Since are both set",
+		expectedError: ErrInvalidOptionCombination,
+		opts: []GetOption{
+			Where(repository.TypeIn("blah")),
+			Since(time.Unix(5, 0)),
+			Category("yayaya"),
+		},
+	}, {
+		name:          "Where and Until are both set",
+		expectedError: ErrInvalidOptionCombination,
+		opts: []GetOption{
+			Until(time.Unix(5, 0)),
+			Where(repository.TypeIn("blah")),
+			CommandStream("yayaya"),
+		},
+	}, {
+		name:          "Backward is set twice",
+		expectedError: ErrInvalidOptionCombination,
+		opts: []GetOption{
+			Backward(),
+			Backward(),
+			Category("yayaya"),
+		},
+	}, {
+		name:          "Backward and SincePosition are both set",
+		expectedError: ErrInvalidOptionCombination,
+		opts: []GetOption{
+			Backward(),
+			SincePosition(5),
+			Category("yayaya"),
+		},
+	}, {
+		name:          "Backward and Last are both set",
+		expectedError: ErrInvalidOptionCombination,
+		opts: []GetOption{
+			Backward(),
+			Last(),
+			CommandStream("yayaya"),
+		},
+	}, {
+		name:          "Backward and Categories are both set",
+		expectedError: ErrInvalidOptionCombination,
+		opts: []GetOption{
+			Backward(),
+			Categories("yayaya", "blah"),
+		},
+	}, {
+		name:          "Backward and AllStreams are both set",
+		expectedError: ErrInvalidOptionCombination,
+		opts: []GetOption{
+			Backward(),
+			AllStreams(),
+		},
+	}, {
+		name:          "BeforeVersion is set without Backward",
+		expectedError: ErrInvalidOptionCombination,
+		opts: []GetOption{
+			BeforeVersion(5),
+			CommandStream("yayaya"),
+		},
+	}, {
+		name:          "BeforePosition is set without Backward",
+		expectedError: ErrInvalidOptionCombination,
+		opts: []GetOption{
+			BeforePosition(5),
+			Category("yayaya"),
+		},
+	}, {
+		name:          "BeforeVersion and BeforePosition are both set",
+		expectedError: ErrInvalidOptionCombination,
+		opts: []GetOption{
+			Backward(),
+			BeforeVersion(5),
+			BeforePosition(5),
+			Category("yayaya"),
+		},
+	}, {
+		name:          "BeforeVersion and Category are both set",
+		expectedError: ErrInvalidOptionCombination,
+		opts: []GetOption{
+			Backward(),
+			BeforeVersion(5),
+			Category("yayaya"),
+		},
+	}, {
+		name:          "BeforePosition and a stream are both set",
+		expectedError: ErrInvalidOptionCombination,
+		opts: []GetOption{
+			Backward(),
+			BeforePosition(5),
+			CommandStream("yayaya"),
+		},
+	}, {
+		name:          "BeforeVersion cannot be negative",
+		expectedError: ErrInvalidOptionCombination,
+		opts: []GetOption{
+			Backward(),
+			BeforeVersion(-1),
+			CommandStream("yayaya"),
+		},
+	}, {
+		name:          "Categories cannot contain a hyphen",
+		expectedError: ErrInvalidMessageCategory,
+		opts: []GetOption{
+			Categories("blah", "-"),
+		},
 	}, {
 		name:          "Category cannot contain a hyphen",
 		expectedError: ErrInvalidMessageCategory,
@@ -556,6 +1397,44 @@ func TestOptionErrors(t *testing.T) {
 		opts: []GetOption{
 			CommandStream("hyphen-hyphen"),
 		},
+	}, {
+		name:          "Entity Command Stream cannot contain a hyphen",
+		expectedError: ErrInvalidCommandStream,
+		opts: []GetOption{
+			EntityCommandStream("hyphen-hyphen", uuid1),
+		},
+	}, {
+		name:          "Stream cannot be blank",
+		expectedError: ErrMissingStreamName,
+		opts: []GetOption{
+			Stream(""),
+		},
+	}, {
+		name:          "Stream and Command Stream are both set",
+		expectedError: ErrInvalidOptionCombination,
+		opts: []GetOption{
+			Stream("blah:snapshot-123"),
+			CommandStream("blah"),
+		},
+	}, {
+		name:          "Event Stream ID cannot be blank",
+		expectedError: ErrMissingMessageCategoryID,
+		opts: []GetOption{
+			EventStreamID("blah", ""),
+		},
+	}, {
+		name:          "Entity Command Stream ID cannot be blank",
+		expectedError: ErrMissingMessageCategoryID,
+		opts: []GetOption{
+			EntityCommandStreamID("blah", ""),
+		},
+	}, {
+		name:          "Event Stream ID and Event Stream are both set",
+		expectedError: ErrInvalidOptionCombination,
+		opts: []GetOption{
+			EventStreamID("blah", "ORD123"),
+			EventStream("blah", uuid1),
+		},
 	}, {
 		name:          "Event Stream cannot contain a hyphen",
 		expectedError: ErrInvalidEventStream,
@@ -592,3 +1471,73 @@ func TestOptionErrors(t *testing.T) {
 		})
 	}
 }
+
+func TestGetWithExactNumbers(t *testing.T) {
+	data := []byte(`{"accountId":9007199254740993,"amountCents":1234567890123456789}`)
+	metadata := []byte(`{"sequence":18446744073709551615}`)
+	expectedData := map[string]interface{}{
+		"accountId":   json.Number("9007199254740993"),
+		"amountCents": json.Number("1234567890123456789"),
+	}
+	expectedMetadata := map[string]interface{}{"sequence": json.Number("18446744073709551615")}
+
+	tests := []struct {
+		name       string
+		streamName string
+		opts       []GetOption
+		storeOpts  []MessageStoreOption
+		dataOf     func(Message) (map[string]interface{}, map[string]interface{})
+	}{{
+		name:       "events",
+		streamName: "account-" + uuid8.String(),
+		opts:       []GetOption{EventStream("account", uuid8)},
+		dataOf: func(msg Message) (map[string]interface{}, map[string]interface{}) {
+			return msg.(*Event).Data, msg.(*Event).Metadata
+		},
+	}, {
+		name:       "commands",
+		streamName: "account:command",
+		opts:       []GetOption{CommandStream("account")},
+		dataOf: func(msg Message) (map[string]interface{}, map[string]interface{}) {
+			return msg.(*Command).Data, msg.(*Command).Metadata
+		},
+	}, {
+		name:       "stream messages",
+		streamName: "account:snapshot-" + uuid8.String(),
+		opts:       []GetOption{Stream("account:snapshot-" + uuid8.String())},
+		dataOf: func(msg Message) (map[string]interface{}, map[string]interface{}) {
+			return msg.(*StreamMessage).Data, msg.(*StreamMessage).Metadata
+		},
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mock_repository.NewMockRepository(ctrl)
+			ctx := context.Background()
+
+			mockRepo.
+				EXPECT().
+				GetAllMessagesInStream(ctx, test.streamName, 1000).
+				Return([]*repository.MessageEnvelope{{
+					ID:          uuid5,
+					MessageType: "Deposited",
+					StreamName:  test.streamName,
+					Data:        data,
+					Metadata:    metadata,
+				}}, nil)
+
+			msgStore := NewMessageStoreFromRepository(mockRepo, logrus.New(), WithExactNumbers())
+			msgs, err := msgStore.Get(ctx, test.opts...)
+
+			assert.NoError(t, err)
+			if assert.Len(t, msgs, 1) {
+				actualData, actualMetadata := test.dataOf(msgs[0])
+				assert.Equal(t, expectedData, actualData)
+				assert.Equal(t, expectedMetadata, actualMetadata)
+			}
+		})
+	}
+}
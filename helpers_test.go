@@ -68,6 +68,7 @@ func getSampleEvent() *Event {
 		ID:             uuid2,
 		MessageType:    "test type",
 		EntityID:       uuid8,
+		StreamID:       uuid8.String(),
 		MessageVersion: 9,
 		GlobalPosition: 7,
 		StreamCategory: "test cat",
@@ -77,6 +78,37 @@ func getSampleEvent() *Event {
 	}
 }
 
+func getSampleStreamMessage() *StreamMessage {
+	packed, err := Pack(dummyData{"a"})
+	panicIf(err)
+	packedMeta, err := Pack(dummyData{"b"})
+	panicIf(err)
+	return &StreamMessage{
+		ID:             uuid4,
+		StreamName:     "test cat:snapshot-" + uuid8.String(),
+		MessageType:    "test type",
+		MessageVersion: 3,
+		GlobalPosition: 11,
+		Data:           packed,
+		Metadata:       packedMeta,
+		Time:           time.Unix(1, 0),
+	}
+}
+
+func getSampleStreamMessageAsEnvelope() *repository.MessageEnvelope {
+	return &repository.MessageEnvelope{
+		ID:             uuid4,
+		Version:        3,
+		GlobalPosition: 11,
+		MessageType:    "test type",
+		StreamName:     "test cat:snapshot-" + uuid8.String(),
+		StreamCategory: "test cat:snapshot",
+		Data:           []byte(`{"Field1":"a"}`),
+		Metadata:       []byte(`{"Field1":"b"}`),
+		Time:           time.Unix(1, 0),
+	}
+}
+
 func getSampleOtherMessage() *otherMessage {
 	packed, err := Pack(dummyData{"a"})
 	panicIf(err)
@@ -140,6 +172,7 @@ func getSampleEvents() []*Event {
 			ID:             uuid5,
 			MessageType:    "Event MessageType 2",
 			EntityID:       uuid8,
+			StreamID:       uuid8.String(),
 			StreamCategory: "test cat",
 			MessageVersion: 4,
 			GlobalPosition: 345,
@@ -150,6 +183,7 @@ func getSampleEvents() []*Event {
 			ID:             uuid7,
 			MessageType:    "Event MessageType 1",
 			EntityID:       uuid8,
+			StreamID:       uuid8.String(),
 			MessageVersion: 8,
 			GlobalPosition: 349,
 			StreamCategory: "test cat",
@@ -170,6 +204,7 @@ func getLotsOfSampleEvents(amount, startingAt int) []*Event {
 			ID:             uuid.Must(uuid.Parse(fmt.Sprintf("10000000-0000-0000-0000-%012d", startingAt+index))),
 			MessageType:    fmt.Sprintf("Event MessageType %d", (startingAt+index)%2+1), // be a 1 or a 2
 			EntityID:       uuid8,
+			StreamID:       uuid8.String(),
 			StreamCategory: "test cat",
 			MessageVersion: int64(4 + startingAt + index),
 			GlobalPosition: int64(500 + startingAt + index),
@@ -274,6 +309,19 @@ func getSampleCommandAsEnvelope() *repository.MessageEnvelope {
 	return msgEnv
 }
 
+func getSampleEntityCommand() *Command {
+	cmd := getSampleCommand()
+	cmd.EntityID = uuid2
+	cmd.StreamID = uuid2.String()
+	return cmd
+}
+
+func getSampleEntityCommandAsEnvelope() *repository.MessageEnvelope {
+	msgEnv := getSampleCommandAsEnvelope()
+	msgEnv.StreamName = "test cat:command-" + uuid2.String()
+	return msgEnv
+}
+
 func getSampleCommandsAsEnvelopes() []*repository.MessageEnvelope {
 	return []*repository.MessageEnvelope{
 		&repository.MessageEnvelope{
@@ -308,6 +356,9 @@ func assertMessageMatchesCommand(t *testing.T, msgEnv Message, msg *Command) {
 		if command.MessageType != msg.MessageType {
 			t.Error("MessageType in message does not match")
 		}
+		if command.EntityID != msg.EntityID {
+			t.Error("EntityID in message does not match")
+		}
 		if command.StreamCategory != msg.StreamCategory {
 			t.Error("StreamCategory in message does not match")
 		}
@@ -442,6 +493,17 @@ func eventsToMessageSlice(events []*Event) []Message {
 	return newMsgs
 }
 
+// readOf is the ReadBatch of reading msgs when none of them are skipped
+func readOf(msgs []Message) ReadBatch {
+	read := ReadBatch{Count: len(msgs)}
+	if len(msgs) > 0 {
+		read.LastVersion = msgs[len(msgs)-1].Version()
+		read.LastPosition = msgs[len(msgs)-1].Position()
+	}
+
+	return read
+}
+
 // this is all just the same as Event
 type otherMessage struct {
 	ID             uuid.UUID
@@ -3,10 +3,12 @@ package inmem_repository
 import (
 	"context"
 	"errors"
-	"fmt"
-	"strings"
+	"sort"
+	"time"
 
 	. "github.com/blackhatbrigade/gomessagestore/repository"
+	"github.com/blackhatbrigade/gomessagestore/streamname"
+	"github.com/blackhatbrigade/gomessagestore/uuid"
 )
 
 type inmemrepo struct {
@@ -15,8 +17,15 @@ type inmemrepo struct {
 
 //NewInMemoryRepository creates a Repistory filled with messages
 func NewInMemoryRepository(msgs []MessageEnvelope) Repository {
+	// keep our own copy in global order, so reads across streams come back in the order the store would give them
+	sorted := make([]MessageEnvelope, len(msgs))
+	copy(sorted, msgs)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].GlobalPosition < sorted[j].GlobalPosition
+	})
+
 	return &inmemrepo{
-		msgs: msgs,
+		msgs: sorted,
 	}
 }
 
@@ -42,12 +51,34 @@ func (repo *inmemrepo) WriteMessage(ctx context.Context, message *MessageEnvelop
 func (repo *inmemrepo) WriteMessageWithExpectedPosition(ctx context.Context, message *MessageEnvelope, position int64) error {
 	version := repo.findLastVersionForStream(message.StreamName)
 	if version != position {
-		return errors.New(fmt.Sprintf("position incorrect. should be %d", version))
+		return ErrWrongStreamVersion
 	}
 
 	return repo.WriteMessage(ctx, message)
 }
 
+//WriteMessagesWithExpectedPosition writes messages to a stream one after another from a position, writing none of them if any can't be written
+func (repo *inmemrepo) WriteMessagesWithExpectedPosition(ctx context.Context, messages []*MessageEnvelope, position int64) error {
+	for _, message := range messages {
+		if message.StreamName != messages[0].StreamName {
+			return ErrMixedStreams
+		}
+	}
+	if len(messages) == 0 {
+		return nil
+	}
+
+	written := len(repo.msgs)
+	for i, message := range messages {
+		if err := repo.WriteMessageWithExpectedPosition(ctx, message, position+int64(i)); err != nil {
+			repo.msgs = repo.msgs[:written] // roll back the messages already written
+			return err
+		}
+	}
+
+	return nil
+}
+
 //GetAllMessagesInStream gets all messages in a stream
 func (repo *inmemrepo) GetAllMessagesInStream(ctx context.Context, streamName string, batchSize int) ([]*MessageEnvelope, error) {
 	msgs := make([]*MessageEnvelope, 0, batchSize)
@@ -101,6 +132,23 @@ func (repo *inmemrepo) GetLastMessageInStream(ctx context.Context, streamName st
 	return
 }
 
+//GetMessagesInStreamBackward gets messages in a stream before a version, newest first
+func (repo *inmemrepo) GetMessagesInStreamBackward(ctx context.Context, streamName string, beforeVersion int64, batchSize int) ([]*MessageEnvelope, error) {
+	msgs := make([]*MessageEnvelope, 0, batchSize)
+
+	for i := len(repo.msgs) - 1; i >= 0 && len(msgs) < batchSize; i-- {
+		msg := repo.msgs[i]
+		if msg.StreamName != streamName || (beforeVersion >= 0 && msg.Version >= beforeVersion) {
+			continue
+		}
+
+		newMessage := msg // make a copy so we don't have strangeness with slices of pointers
+		msgs = append(msgs, &newMessage)
+	}
+
+	return msgs, nil
+}
+
 //GetAllMessagesInCategory gets all messages in a category
 func (repo *inmemrepo) GetAllMessagesInCategory(ctx context.Context, category string, batchSize int) ([]*MessageEnvelope, error) {
 	msgs := make([]*MessageEnvelope, 0, batchSize)
@@ -142,6 +190,160 @@ func (repo *inmemrepo) GetAllMessagesInCategorySince(ctx context.Context, catego
 	return msgs, nil
 }
 
+//GetMessagesInCategoryBackward gets messages in a category before a position, newest first
+func (repo *inmemrepo) GetMessagesInCategoryBackward(ctx context.Context, category string, beforePosition int64, batchSize int) ([]*MessageEnvelope, error) {
+	msgs := make([]*MessageEnvelope, 0, batchSize)
+
+	for i := len(repo.msgs) - 1; i >= 0 && len(msgs) < batchSize; i-- {
+		msg := repo.msgs[i]
+		if !categoryMatches(msg.StreamName, category) || (beforePosition >= 0 && msg.GlobalPosition >= beforePosition) {
+			continue
+		}
+
+		newMessage := msg // make a copy so we don't just reassign based on the next item in the loop
+		msgs = append(msgs, &newMessage)
+	}
+
+	return msgs, nil
+}
+
+//GetAllMessagesInCategoriesSince gets all messages in any of the categories since a position, in global order
+func (repo *inmemrepo) GetAllMessagesInCategoriesSince(ctx context.Context, categories []string, globalPosition int64, batchSize int) ([]*MessageEnvelope, error) {
+	msgs := make([]*MessageEnvelope, 0, batchSize)
+
+	for _, msg := range repo.msgs {
+		if msg.GlobalPosition < globalPosition {
+			continue
+		}
+
+		for _, category := range categories {
+			if categoryMatches(msg.StreamName, category) {
+				newMessage := msg // make a copy so we don't just reassign based on the next item in the loop
+				msgs = append(msgs, &newMessage)
+				break
+			}
+		}
+		if len(msgs) == batchSize {
+			return msgs, nil
+		}
+	}
+
+	return msgs, nil
+}
+
+//GetMessageByID gets a single message by its ID, or nil when there is no such message
+func (repo *inmemrepo) GetMessageByID(ctx context.Context, id uuid.UUID) (*MessageEnvelope, error) {
+	if id == uuid.Nil {
+		return nil, ErrBlankMessageID
+	}
+
+	for _, msg := range repo.msgs {
+		if msg.ID == id {
+			foundMsg := msg // make a copy so callers can't change what is stored
+			return &foundMsg, nil
+		}
+	}
+
+	return nil, nil
+}
+
+//GetAllMessagesSince gets all messages in every stream since a position, in global order
+func (repo *inmemrepo) GetAllMessagesSince(ctx context.Context, globalPosition int64, batchSize int) ([]*MessageEnvelope, error) {
+	msgs := make([]*MessageEnvelope, 0, batchSize)
+
+	for _, msg := range repo.msgs {
+		if msg.GlobalPosition < globalPosition || streamname.IsPositionStream(msg.StreamName) {
+			continue
+		}
+
+		newMessage := msg // make a copy so we don't just reassign based on the next item in the loop
+		msgs = append(msgs, &newMessage)
+		if len(msgs) == batchSize {
+			return msgs, nil
+		}
+	}
+
+	return msgs, nil
+}
+
+//GetAllMessagesInStreamBetween gets all messages in a stream since a version that were written in a time range
+func (repo *inmemrepo) GetAllMessagesInStreamBetween(ctx context.Context, streamName string, version int64, since, until time.Time, batchSize int) ([]*MessageEnvelope, error) {
+	return repo.getBetween(since, until, batchSize, func(msg MessageEnvelope) bool {
+		return msg.StreamName == streamName && msg.Version >= version
+	}), nil
+}
+
+//GetAllMessagesInCategoryBetween gets all messages in a category since a position that were written in a time range
+func (repo *inmemrepo) GetAllMessagesInCategoryBetween(ctx context.Context, category string, globalPosition int64, since, until time.Time, batchSize int) ([]*MessageEnvelope, error) {
+	return repo.getBetween(since, until, batchSize, func(msg MessageEnvelope) bool {
+		return categoryMatches(msg.StreamName, category) && msg.GlobalPosition >= globalPosition
+	}), nil
+}
+
+//GetAllMessagesBetween gets all messages in every stream since a position that were written in a time range
+func (repo *inmemrepo) GetAllMessagesBetween(ctx context.Context, globalPosition int64, since, until time.Time, batchSize int) ([]*MessageEnvelope, error) {
+	return repo.getBetween(since, until, batchSize, func(msg MessageEnvelope) bool {
+		return msg.GlobalPosition >= globalPosition && !streamname.IsPositionStream(msg.StreamName)
+	}), nil
+}
+
+//GetAllMessagesInStreamWhere gets all messages in a stream since a version that match the filter
+func (repo *inmemrepo) GetAllMessagesInStreamWhere(ctx context.Context, streamName string, version int64, filter Filter, batchSize int) ([]*MessageEnvelope, error) {
+	if _, err := filter.Condition(); err != nil { // reject the same filters postgres would
+		return nil, err
+	}
+
+	return repo.getBetween(time.Time{}, time.Time{}, batchSize, func(msg MessageEnvelope) bool {
+		return msg.StreamName == streamName && msg.Version >= version && filter.Matches(&msg)
+	}), nil
+}
+
+//GetAllMessagesInCategoryWhere gets all messages in a category since a position that match the filter
+func (repo *inmemrepo) GetAllMessagesInCategoryWhere(ctx context.Context, category string, globalPosition int64, filter Filter, batchSize int) ([]*MessageEnvelope, error) {
+	if _, err := filter.Condition(); err != nil {
+		return nil, err
+	}
+
+	return repo.getBetween(time.Time{}, time.Time{}, batchSize, func(msg MessageEnvelope) bool {
+		return categoryMatches(msg.StreamName, category) && msg.GlobalPosition >= globalPosition && filter.Matches(&msg)
+	}), nil
+}
+
+//GetLastMessage gets the last message in the store, outside of position streams
+func (repo *inmemrepo) GetLastMessage(ctx context.Context) (*MessageEnvelope, error) {
+	for i := len(repo.msgs) - 1; i >= 0; i-- {
+		if !streamname.IsPositionStream(repo.msgs[i].StreamName) {
+			lastMsg := repo.msgs[i] // make a copy so callers can't change what is stored
+			return &lastMsg, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// getBetween gets the messages that match and were written at or after since and before until; a zero time leaves that end open
+func (repo *inmemrepo) getBetween(since, until time.Time, batchSize int, matches func(MessageEnvelope) bool) []*MessageEnvelope {
+	msgs := make([]*MessageEnvelope, 0, batchSize)
+
+	for _, msg := range repo.msgs {
+		if len(msgs) == batchSize {
+			break
+		}
+		if !since.IsZero() && msg.Time.Before(since) {
+			continue
+		}
+		if !until.IsZero() && !msg.Time.Before(until) {
+			continue
+		}
+		if matches(msg) {
+			newMessage := msg // make a copy so we don't just reassign based on the next item in the loop
+			msgs = append(msgs, &newMessage)
+		}
+	}
+
+	return msgs
+}
+
 func (repo *inmemrepo) findLastVersionForStream(stream string) int64 {
 	var version int64
 	version = -1
@@ -163,10 +365,5 @@ func (repo *inmemrepo) findLastPosition() int64 {
 }
 
 func categoryMatches(streamName string, category string) bool {
-	streamPieces := strings.Split(streamName, "-")
-	if len(streamPieces) == 0 {
-		return false
-	}
-
-	return streamPieces[0] == category
+	return streamname.Category(streamName) == category
 }
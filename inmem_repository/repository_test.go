@@ -2,7 +2,9 @@ package inmem_repository_test
 
 import (
 	"context"
+	"fmt"
 	"testing"
+	"time"
 
 	. "github.com/blackhatbrigade/gomessagestore/inmem_repository"
 	. "github.com/blackhatbrigade/gomessagestore/repository"
@@ -176,6 +178,69 @@ func TestInMemRepository(t *testing.T) {
 	assert.Equal(catMsgs, msgs)
 	assert.Nil(err)
 
+	//get some from several categories, in global order
+	msgs, err = repo.GetAllMessagesInCategoriesSince(ctx, []string{"A", "C"}, 0, 4)
+	assert.Equal([]*MessageEnvelope{streamA[0], catMsgs[0], streamA[1], catMsgs[1]}, msgs)
+	assert.Nil(err)
+
+	//categories that were not asked for are left out
+	msgs, err = repo.GetAllMessagesInCategoriesSince(ctx, []string{"C", "Z"}, 103, 2)
+	assert.Equal([]*MessageEnvelope{catMsgs[0], catMsgs[1]}, msgs)
+	assert.Nil(err)
+
+	//get some more from several categories, in global order
+	msgs, err = repo.GetAllMessagesInCategoriesSince(ctx, []string{"A", "C"}, 106, 2)
+	assert.Equal([]*MessageEnvelope{catMsgs[2], catMsgs[3]}, msgs)
+	assert.Nil(err)
+
+	//get the newest from stream b, newest first
+	msgs, err = repo.GetMessagesInStreamBackward(ctx, "B-123", -1, 2)
+	assert.Equal([]*MessageEnvelope{streamB[2], streamB[1]}, msgs)
+	assert.Nil(err)
+
+	//get older from stream b, newest first
+	msgs, err = repo.GetMessagesInStreamBackward(ctx, "B-123", streamB[1].Version, 100)
+	assert.Equal([]*MessageEnvelope{streamB[0]}, msgs)
+	assert.Nil(err)
+
+	//get the newest from category, newest first
+	msgs, err = repo.GetMessagesInCategoryBackward(ctx, "C", -1, 3)
+	assert.Equal([]*MessageEnvelope{catMsgs[3], catMsgs[2], catMsgs[1]}, msgs)
+	assert.Nil(err)
+
+	//get older from category, newest first
+	msgs, err = repo.GetMessagesInCategoryBackward(ctx, "C", 106, 100)
+	assert.Equal([]*MessageEnvelope{catMsgs[1], catMsgs[0]}, msgs)
+	assert.Nil(err)
+
+	//get a message by its ID
+	msg, err = repo.GetMessageByID(ctx, catMsgs[2].ID)
+	assert.Equal(catMsgs[2], msg)
+	assert.Nil(err)
+
+	//get a message that isn't there by its ID
+	msg, err = repo.GetMessageByID(ctx, uuid.NewRandom())
+	assert.Nil(msg)
+	assert.Nil(err)
+
+	//get a message without an ID
+	msg, err = repo.GetMessageByID(ctx, uuid.Nil)
+	assert.Nil(msg)
+	assert.Equal(ErrBlankMessageID, err)
+
+	//get some from every stream, in global order
+	msgs, err = repo.GetAllMessagesSince(ctx, 102, 3)
+	assert.Equal([]*MessageEnvelope{streamB[1], catMsgs[0], streamA[1]}, msgs)
+	assert.Nil(err)
+
+	//get the rest from every stream, including what was written since
+	msgs, err = repo.GetAllMessagesSince(ctx, 107, 100)
+	assert.Len(msgs, 3)
+	assert.Equal(streamB[2], msgs[0])
+	assert.Equal(catMsgs[3], msgs[1])
+	assert.Equal(int64(109), msgs[2].GlobalPosition)
+	assert.Nil(err)
+
 	//write an event to a new stream in the same category
 	newID = uuid.NewRandom()
 	msg = copyMessageWithNewID(catMsgs[0], newID)
@@ -236,3 +301,195 @@ func TestInMemRepositoryWriteFirstMessageAtPosition(t *testing.T) {
 	err := repo.WriteMessageWithExpectedPosition(ctx, cmd, -1)
 	assert.Nil(err)
 }
+
+func TestInMemRepositoryWriteMessagesAtPosition(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	repo := NewInMemoryRepository([]MessageEnvelope{*streamA[0], *streamA[1]})
+
+	//a stream that isn't at the expected version gets none of the messages
+	err := repo.WriteMessagesWithExpectedPosition(ctx, []*MessageEnvelope{
+		copyMessageWithNewID(streamA[0], uuid.NewRandom()),
+		copyMessageWithNewID(streamA[0], uuid.NewRandom()),
+	}, 5)
+	assert.Equal(ErrWrongStreamVersion, err)
+
+	//messages in different streams are not written together
+	err = repo.WriteMessagesWithExpectedPosition(ctx, []*MessageEnvelope{
+		copyMessageWithNewID(streamA[0], uuid.NewRandom()),
+		copyMessageWithNewID(streamB[0], uuid.NewRandom()),
+	}, 6)
+	assert.Equal(ErrMixedStreams, err)
+
+	//a duplicate ID part way through rolls back the messages before it
+	err = repo.WriteMessagesWithExpectedPosition(ctx, []*MessageEnvelope{
+		copyMessageWithNewID(streamA[0], uuid.NewRandom()),
+		streamA[0],
+	}, 6)
+	assert.NotNil(err)
+
+	msgs, err := repo.GetAllMessagesInStream(ctx, "A-123", 100)
+	assert.Len(msgs, 2)
+	assert.Nil(err)
+
+	//the messages are written one after another from the position
+	err = repo.WriteMessagesWithExpectedPosition(ctx, []*MessageEnvelope{
+		copyMessageWithNewID(streamA[0], uuid.NewRandom()),
+		copyMessageWithNewID(streamA[0], uuid.NewRandom()),
+	}, 6)
+	assert.Nil(err)
+
+	msg, err := repo.GetLastMessageInStream(ctx, "A-123")
+	assert.Equal(int64(8), msg.Version)
+	assert.Nil(err)
+}
+
+func TestInMemRepositoryKeepsGlobalOrder(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	//init with messages that are out of global order
+	repo := NewInMemoryRepository([]MessageEnvelope{
+		*catMsgs[1],
+		*streamA[0],
+		*streamB[0],
+		*catMsgs[0],
+	})
+
+	//reading every stream gives them back in global order
+	msgs, err := repo.GetAllMessagesSince(ctx, 0, 100)
+	assert.Equal([]*MessageEnvelope{streamA[0], streamB[0], catMsgs[0], catMsgs[1]}, msgs)
+	assert.Nil(err)
+
+	//new messages go after the highest global position
+	newID := uuid.NewRandom()
+	err = repo.WriteMessage(ctx, copyMessageWithNewID(streamA[0], newID))
+	assert.Nil(err)
+
+	msg, err := repo.GetLastMessageInStream(ctx, "A-123")
+	assert.Equal(int64(106), msg.GlobalPosition)
+	assert.Nil(err)
+}
+
+func TestInMemRepositoryLeavesPositionStreamsOutOfReadsAcrossStreams(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	position := *copyMessageWithNewID(streamA[1], uuid.NewRandom())
+	position.StreamName = "someSubscriber+position"
+	position.GlobalPosition = 200
+	repo := NewInMemoryRepository([]MessageEnvelope{*streamA[0], *streamA[1], position})
+
+	msgs, err := repo.GetAllMessagesSince(ctx, 0, 100)
+	assert.Equal([]*MessageEnvelope{streamA[0], streamA[1]}, msgs)
+	assert.Nil(err)
+
+	msgs, err = repo.GetAllMessagesBetween(ctx, 0, time.Time{}, time.Now(), 100)
+	assert.Equal([]*MessageEnvelope{streamA[0], streamA[1]}, msgs)
+	assert.Nil(err)
+
+	msg, err := repo.GetLastMessage(ctx)
+	assert.Equal(streamA[1], msg)
+	assert.Nil(err)
+
+	//the position stream can still be read by name
+	msgs, err = repo.GetAllMessagesInStream(ctx, "someSubscriber+position", 100)
+	assert.Len(msgs, 1)
+	assert.Nil(err)
+}
+
+func TestInMemRepositoryTimeRange(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	//init with messages written a second apart
+	msgs := make([]MessageEnvelope, len(startingMessages))
+	timed := make([]*MessageEnvelope, len(startingMessages))
+	for i, msg := range startingMessages {
+		msg.Time = time.Unix(1000+msg.GlobalPosition, 0)
+		msgs[i] = msg
+		timed[i] = &msgs[i]
+	}
+	repo := NewInMemoryRepository(msgs)
+
+	//get from a stream between two times
+	found, err := repo.GetAllMessagesInStreamBetween(ctx, "B-123", 0, time.Unix(1102, 0), time.Unix(1107, 0), 100)
+	assert.Equal([]*MessageEnvelope{timed[2]}, found)
+	assert.Nil(err)
+
+	//get from a stream since a version until a time
+	found, err = repo.GetAllMessagesInStreamBetween(ctx, "B-123", 9, time.Time{}, time.Unix(1108, 0), 100)
+	assert.Equal([]*MessageEnvelope{timed[2], timed[7]}, found)
+	assert.Nil(err)
+
+	//get from a category since a time
+	found, err = repo.GetAllMessagesInCategoryBetween(ctx, "C", 0, time.Unix(1105, 0), time.Time{}, 2)
+	assert.Equal([]*MessageEnvelope{timed[5], timed[6]}, found)
+	assert.Nil(err)
+
+	//get from every stream between two times
+	found, err = repo.GetAllMessagesBetween(ctx, 0, time.Unix(1103, 0), time.Unix(1105, 0), 100)
+	assert.Equal([]*MessageEnvelope{timed[3], timed[4]}, found)
+	assert.Nil(err)
+
+	//get from every stream since a position until a time
+	found, err = repo.GetAllMessagesBetween(ctx, 107, time.Time{}, time.Unix(2000, 0), 100)
+	assert.Equal([]*MessageEnvelope{timed[7], timed[8]}, found)
+	assert.Nil(err)
+
+	//get the last message in the store
+	last, err := repo.GetLastMessage(ctx)
+	assert.Equal(timed[8], last)
+	assert.Nil(err)
+
+	//get the last message in an empty store
+	last, err = NewInMemoryRepository([]MessageEnvelope{}).GetLastMessage(ctx)
+	assert.Nil(last)
+	assert.Nil(err)
+}
+
+func TestInMemRepositoryWhere(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	//init with messages from two users, the odd ones typed differently
+	msgs := make([]MessageEnvelope, len(startingMessages))
+	tagged := make([]*MessageEnvelope, len(startingMessages))
+	for i, msg := range startingMessages {
+		msg.Metadata = []byte(`{"userId":"bob"}`)
+		if msg.GlobalPosition%2 == 1 {
+			msg.Metadata = []byte(`{"userId":"alice"}`)
+			msg.MessageType = "er"
+		}
+		msg.Data = []byte(fmt.Sprintf(`{"amount":{"value":%d}}`, msg.GlobalPosition))
+		msgs[i] = msg
+		tagged[i] = &msgs[i]
+	}
+	repo := NewInMemoryRepository(msgs)
+
+	//get from a stream by metadata
+	found, err := repo.GetAllMessagesInStreamWhere(ctx, "B-123", 0, MetadataEquals("userId", "alice"), 100)
+	assert.Equal([]*MessageEnvelope{tagged[1], tagged[7]}, found)
+	assert.Nil(err)
+
+	//get from a stream since a version by type
+	found, err = repo.GetAllMessagesInStreamWhere(ctx, "B-123", 10, TypeIn("er"), 100)
+	assert.Equal([]*MessageEnvelope{tagged[7]}, found)
+	assert.Nil(err)
+
+	//get from a category by data
+	found, err = repo.GetAllMessagesInCategoryWhere(ctx, "C", 0, DataEquals("106", "amount", "value"), 100)
+	assert.Equal([]*MessageEnvelope{tagged[6]}, found)
+	assert.Nil(err)
+
+	//get from a category since a position with several filters
+	found, err = repo.GetAllMessagesInCategoryWhere(ctx, "C", 104, And(TypeIn("uh", "er"), MetadataEquals("userId", "bob")), 1)
+	assert.Equal([]*MessageEnvelope{tagged[6]}, found)
+	assert.Nil(err)
+
+	//get with an invalid filter
+	found, err = repo.GetAllMessagesInCategoryWhere(ctx, "C", 0, TypeIn(), 100)
+	assert.Nil(found)
+	assert.Equal(ErrInvalidFilter, err)
+}
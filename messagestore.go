@@ -6,6 +6,7 @@ import (
 
 	"github.com/blackhatbrigade/gomessagestore/inmem_repository"
 	"github.com/blackhatbrigade/gomessagestore/repository"
+	"github.com/blackhatbrigade/gomessagestore/uuid"
 	"github.com/sirupsen/logrus"
 )
 
@@ -15,22 +16,74 @@ import (
 type MessageStore interface {
 	Write(ctx context.Context, message Message, opts ...WriteOption) error                                         // writes a message to the message store
 	Get(ctx context.Context, opts ...GetOption) ([]Message, error)                                                 // retrieves messages from the message store
+	GetByID(ctx context.Context, id uuid.UUID, converters ...MessageConverter) (Message, error)                    // retrieves a single message from the message store by its ID
 	CreateProjector(opts ...ProjectorOption) (Projector, error)                                                    // creates a new projector
+	CreateAggregate(projectorOpts []ProjectorOption, opts ...AggregateOption) (Aggregate, error)                   // creates a new aggregate for handling commands
 	CreateSubscriber(subscriberID string, handlers []MessageHandler, opts ...SubscriberOption) (Subscriber, error) // creates a new subscriber
 	GetLogger() (logger logrus.FieldLogger)                                                                        // gets the logger
+	Request(ctx context.Context, cmd Message, replyCategory string, opts ...RequestOption) (Message, error)        // writes a command and waits for its reply
 }
 
 type msgStore struct {
-	repo repository.Repository
-	log  logrus.FieldLogger
+	repo         repository.Repository
+	log          logrus.FieldLogger
+	registry     *Registry // when set, registered message types are read and written as TypedMessages
+	codecs       *codecs
+	exactNumbers bool // when set, numbers in message data and metadata are read as json.Number
+}
+
+// MessageStoreOption is used for creating message stores with optional settings
+type MessageStoreOption func(ms *msgStore)
+
+// WithRegistry has Get, GetByID, subscribers and projectors decode registered message types into TypedMessages, and Write fill in their MessageType
+func WithRegistry(registry *Registry) MessageStoreOption {
+	return func(ms *msgStore) {
+		ms.registry = registry
+	}
+}
+
+// WithExactNumbers has Get, GetByID, subscribers and projectors read numbers in Data and Metadata maps as json.Number instead of float64
+// Use it when messages carry int64 IDs, amounts in minor units or counters that can go above 2^53, which float64 would round.
+func WithExactNumbers() MessageStoreOption {
+	return func(ms *msgStore) {
+		ms.exactNumbers = true
+	}
+}
+
+// WithCodec has the message store write the data of every message type without its own codec with codec, instead of JSON
+// Messages are always read with the codec recorded in their metadata, so a store can still read data written with other codecs it knows.
+func WithCodec(codec Codec) MessageStoreOption {
+	return func(ms *msgStore) {
+		if codec == nil {
+			return
+		}
+		ms.codecs.byName[codec.Name()] = codec
+		ms.codecs.defaultCodec = codec
+	}
+}
+
+// WithMessageTypeCodec has the message store write the data of messageType with codec, such as MessagePackCodec for high volume telemetry
+func WithMessageTypeCodec(messageType string, codec Codec) MessageStoreOption {
+	return func(ms *msgStore) {
+		if codec == nil {
+			return
+		}
+		ms.codecs.byName[codec.Name()] = codec
+		ms.codecs.byMessageType[messageType] = codec
+	}
 }
 
 // NewMessageStore creates a new MessageStore instance using an injected DB.
-func NewMessageStore(injectedDB *sql.DB, logger logrus.FieldLogger) MessageStore {
+func NewMessageStore(injectedDB *sql.DB, logger logrus.FieldLogger, opts ...MessageStoreOption) MessageStore {
 	pgRepo := repository.NewPostgresRepository(injectedDB, logger)
 	msgstr := &msgStore{
-		repo: pgRepo,
-		log:  logger,
+		repo:   pgRepo,
+		log:    logger,
+		codecs: newCodecs(),
+	}
+
+	for _, option := range opts {
+		option(msgstr)
 	}
 
 	return msgstr
@@ -38,15 +91,29 @@ func NewMessageStore(injectedDB *sql.DB, logger logrus.FieldLogger) MessageStore
 
 // NewMessageStoreFromRepository creates a new MessageStore instance using an injected repository.
 // FOR TESTING ONLY
-func NewMessageStoreFromRepository(injectedRepo repository.Repository, logger logrus.FieldLogger) MessageStore {
+func NewMessageStoreFromRepository(injectedRepo repository.Repository, logger logrus.FieldLogger, opts ...MessageStoreOption) MessageStore {
 	msgstr := &msgStore{
-		repo: injectedRepo,
-		log:  logger,
+		repo:   injectedRepo,
+		log:    logger,
+		codecs: newCodecs(),
+	}
+
+	for _, option := range opts {
+		option(msgstr)
 	}
 
 	return msgstr
 }
 
+// converters adds the registry, when there is one, after the supplied converters
+func (ms *msgStore) converters(converters []MessageConverter) []MessageConverter {
+	if ms.registry == nil {
+		return converters
+	}
+
+	return append(converters[:len(converters):len(converters)], withExactNumbers(ms.registry.convert, ms.exactNumbers))
+}
+
 // NewMockMessageStoreWithMessages is used for testing purposes
 func NewMockMessageStoreWithMessages(msgs []Message) MessageStore {
 	msgEnvs := make([]repository.MessageEnvelope, len(msgs))
@@ -0,0 +1,212 @@
+package gomessagestore
+
+import (
+	"encoding/json"
+
+	"github.com/blackhatbrigade/gomessagestore/repository"
+	"github.com/blackhatbrigade/gomessagestore/uuid"
+)
+
+// Metadata keys used by Eventide, which Metadata reads and writes, plus causationMessageId
+const (
+	CausationMessageIDKey             = "causationMessageId"
+	CausationMessageStreamNameKey     = "causationMessageStreamName"
+	CausationMessagePositionKey       = "causationMessagePosition"
+	CausationMessageGlobalPositionKey = "causationMessageGlobalPosition"
+	CorrelationStreamNameKey          = "correlationStreamName"
+	ReplyStreamNameKey                = "replyStreamName"
+)
+
+// Metadata is the typed form of the Metadata map on messages, following Eventide's conventions
+// Build the map for a new message with ToMap(), and read it back from any message with MetadataOf().
+type Metadata struct {
+	CausationMessageID             uuid.UUID              // the ID of the message that caused this one
+	CausationMessageStreamName     string                 // the stream of the message that caused this one
+	CausationMessagePosition       int64                  // the position of the causing message in its stream
+	CausationMessageGlobalPosition int64                  // the global position of the causing message
+	CorrelationStreamName          string                 // the stream that started the chain of messages, such as a process's stream
+	ReplyStreamName                string                 // the stream the receiver should reply to
+	SchemaVersion                  int                    // the schema version of the message's data; left blank, Write fills it in when the store has a Registry
+	Properties                     map[string]interface{} // any other metadata, stored alongside the keys above
+}
+
+// metadataFields are the Metadata fields stored under their own keys
+type metadataFields struct {
+	CausationMessageID             uuid.UUID `json:"causationMessageId"`
+	CausationMessageStreamName     string    `json:"causationMessageStreamName,omitempty"`
+	CausationMessagePosition       int64     `json:"causationMessagePosition"`
+	CausationMessageGlobalPosition int64     `json:"causationMessageGlobalPosition"`
+	CorrelationStreamName          string    `json:"correlationStreamName,omitempty"`
+	ReplyStreamName                string    `json:"replyStreamName,omitempty"`
+	SchemaVersion                  int       `json:"schemaVersion,omitempty"`
+}
+
+var metadataKeys = []string{
+	CausationMessageIDKey,
+	CausationMessageStreamNameKey,
+	CausationMessagePositionKey,
+	CausationMessageGlobalPositionKey,
+	CorrelationStreamNameKey,
+	ReplyStreamNameKey,
+	SchemaVersionKey,
+}
+
+// ToMap converts the metadata to the map used by the Metadata field of Event, Command, StreamMessage and TypedMessage; fields left blank are left out
+// The causation positions are always written alongside a causation stream name, as 0 is the position of the first message in a stream.
+func (metadata Metadata) ToMap() map[string]interface{} {
+	values := make(map[string]interface{}, len(metadata.Properties)+len(metadataKeys))
+	for key, value := range metadata.Properties {
+		values[key] = value
+	}
+
+	if metadata.CausationMessageID != NilUUID {
+		values[CausationMessageIDKey] = metadata.CausationMessageID.String()
+	}
+	if metadata.CausationMessageStreamName != "" {
+		values[CausationMessageStreamNameKey] = metadata.CausationMessageStreamName
+	}
+	if metadata.CausationMessageStreamName != "" || metadata.CausationMessagePosition != 0 {
+		values[CausationMessagePositionKey] = metadata.CausationMessagePosition
+	}
+	if metadata.CausationMessageStreamName != "" || metadata.CausationMessageGlobalPosition != 0 {
+		values[CausationMessageGlobalPositionKey] = metadata.CausationMessageGlobalPosition
+	}
+	if metadata.CorrelationStreamName != "" {
+		values[CorrelationStreamNameKey] = metadata.CorrelationStreamName
+	}
+	if metadata.ReplyStreamName != "" {
+		values[ReplyStreamNameKey] = metadata.ReplyStreamName
+	}
+	if metadata.SchemaVersion != 0 {
+		values[SchemaVersionKey] = metadata.SchemaVersion
+	}
+
+	return values
+}
+
+// MarshalJSON writes the metadata as a single JSON object, with Properties next to the Eventide keys
+func (metadata Metadata) MarshalJSON() ([]byte, error) {
+	return json.Marshal(metadata.ToMap())
+}
+
+// UnmarshalJSON reads the Eventide keys into their fields and everything else into Properties
+func (metadata *Metadata) UnmarshalJSON(data []byte) error {
+	fields := metadataFields{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	properties := make(map[string]interface{})
+	if err := json.Unmarshal(data, &properties); err != nil {
+		return err
+	}
+	for _, key := range metadataKeys {
+		delete(properties, key)
+	}
+	if len(properties) == 0 {
+		properties = nil
+	}
+
+	*metadata = Metadata{
+		CausationMessageID:             fields.CausationMessageID,
+		CausationMessageStreamName:     fields.CausationMessageStreamName,
+		CausationMessagePosition:       fields.CausationMessagePosition,
+		CausationMessageGlobalPosition: fields.CausationMessageGlobalPosition,
+		CorrelationStreamName:          fields.CorrelationStreamName,
+		ReplyStreamName:                fields.ReplyStreamName,
+		SchemaVersion:                  fields.SchemaVersion,
+		Properties:                     properties,
+	}
+	return nil
+}
+
+// MetadataOf reads the typed metadata of any message
+func MetadataOf(message Message) (Metadata, error) {
+	envelope, err := message.ToEnvelope()
+	if err != nil {
+		return Metadata{}, err
+	}
+
+	return metadataFromEnvelope(envelope)
+}
+
+// metadataFromEnvelope reads the typed metadata out of an envelope
+func metadataFromEnvelope(messageEnvelope *repository.MessageEnvelope) (Metadata, error) {
+	metadata := Metadata{}
+	if len(messageEnvelope.Metadata) == 0 {
+		return metadata, nil
+	}
+
+	err := json.Unmarshal(messageEnvelope.Metadata, &metadata)
+	return metadata, err
+}
+
+// Follow returns a copy of next that follows previous, with the metadata FollowMetadata builds added to it
+// next must be a Command, Event, StreamMessage or TypedMessage.
+func Follow(previous, next Message) (Message, error) {
+	metadata, err := FollowMetadata(previous)
+	if err != nil {
+		return nil, err
+	}
+
+	return WithMetadata(next, metadata)
+}
+
+// FollowMetadata builds the metadata for a message caused by previous: previous becomes its causation message, and its correlation and reply streams carry over
+// Properties and the schema version are not carried over, as they belong to previous.
+func FollowMetadata(previous Message) (Metadata, error) {
+	envelope, err := previous.ToEnvelope()
+	if err != nil {
+		return Metadata{}, err
+	}
+	previousMetadata, err := metadataFromEnvelope(envelope)
+	if err != nil {
+		return Metadata{}, err
+	}
+
+	metadata := Metadata{
+		CausationMessageID:             envelope.ID,
+		CausationMessageStreamName:     envelope.StreamName,
+		CausationMessagePosition:       envelope.Version,
+		CausationMessageGlobalPosition: envelope.GlobalPosition,
+		CorrelationStreamName:          previousMetadata.CorrelationStreamName,
+		ReplyStreamName:                previousMetadata.ReplyStreamName,
+	}
+
+	return metadata, nil
+}
+
+// WithMetadata returns a copy of message with the fields set in metadata added to its Metadata map, such as the metadata FollowMetadata builds
+// message must be a Command, Event, StreamMessage or TypedMessage.
+func WithMetadata(message Message, metadata Metadata) (Message, error) {
+	merge := func(existing map[string]interface{}) map[string]interface{} {
+		merged := make(map[string]interface{}, len(existing))
+		for key, value := range existing {
+			merged[key] = value
+		}
+		for key, value := range metadata.ToMap() {
+			merged[key] = value
+		}
+		return merged
+	}
+
+	switch msg := message.(type) {
+	case *Command:
+		withMetadata := *msg
+		withMetadata.Metadata = merge(msg.Metadata)
+		return &withMetadata, nil
+	case *Event:
+		withMetadata := *msg
+		withMetadata.Metadata = merge(msg.Metadata)
+		return &withMetadata, nil
+	case *StreamMessage:
+		withMetadata := *msg
+		withMetadata.Metadata = merge(msg.Metadata)
+		return &withMetadata, nil
+	case *TypedMessage:
+		withMetadata := *msg
+		withMetadata.Metadata = merge(msg.Metadata)
+		return &withMetadata, nil
+	}
+
+	return nil, ErrMetadataUnsupported
+}
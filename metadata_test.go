@@ -0,0 +1,317 @@
+package gomessagestore_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	. "github.com/blackhatbrigade/gomessagestore"
+	"github.com/blackhatbrigade/gomessagestore/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func getSampleMetadata() Metadata {
+	return Metadata{
+		CausationMessageID:             uuid7,
+		CausationMessageStreamName:     "account:command-" + uuid8.String(),
+		CausationMessagePosition:       4,
+		CausationMessageGlobalPosition: 9007199254740,
+		CorrelationStreamName:          "transfer-" + uuid9.String(),
+		ReplyStreamName:                "transfer:reply-" + uuid9.String(),
+		SchemaVersion:                  2,
+		Properties:                     map[string]interface{}{"userId": "bob", "retries": float64(3)},
+	}
+}
+
+func TestMetadataJSON(t *testing.T) {
+	tests := []struct {
+		name     string
+		metadata Metadata
+		json     string
+	}{{
+		name:     "writes Eventide keys next to the properties",
+		metadata: getSampleMetadata(),
+		json: `{
+			"causationMessageId": "` + uuid7.String() + `",
+			"causationMessageStreamName": "account:command-` + uuid8.String() + `",
+			"causationMessagePosition": 4,
+			"causationMessageGlobalPosition": 9007199254740,
+			"correlationStreamName": "transfer-` + uuid9.String() + `",
+			"replyStreamName": "transfer:reply-` + uuid9.String() + `",
+			"schemaVersion": 2,
+			"userId": "bob",
+			"retries": 3
+		}`,
+	}, {
+		name:     "leaves out blank fields",
+		metadata: Metadata{CorrelationStreamName: "transfer-123"},
+		json:     `{"correlationStreamName": "transfer-123"}`,
+	}, {
+		name:     "writes the positions of a causation message at the start of its stream",
+		metadata: Metadata{CausationMessageStreamName: "account:command-123"},
+		json: `{
+			"causationMessageStreamName": "account:command-123",
+			"causationMessagePosition": 0,
+			"causationMessageGlobalPosition": 0
+		}`,
+	}, {
+		name:     "writes an empty object for blank metadata",
+		metadata: Metadata{},
+		json:     `{}`,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			written, err := json.Marshal(test.metadata)
+			assert.NoError(t, err)
+			assert.JSONEq(t, test.json, string(written))
+
+			read := Metadata{}
+			assert.NoError(t, json.Unmarshal(written, &read))
+			assert.Equal(t, test.metadata, read)
+		})
+	}
+}
+
+func TestMetadataRoundTripsThroughEnvelopes(t *testing.T) {
+	tests := []struct {
+		name    string
+		message func(metadata map[string]interface{}) Message
+	}{{
+		name: "events",
+		message: func(metadata map[string]interface{}) Message {
+			event := getSampleEvent()
+			event.Metadata = metadata
+			return event
+		},
+	}, {
+		name: "commands",
+		message: func(metadata map[string]interface{}) Message {
+			command := getSampleCommand()
+			command.Metadata = metadata
+			return command
+		},
+	}, {
+		name: "stream messages",
+		message: func(metadata map[string]interface{}) Message {
+			msg := getSampleStreamMessage()
+			msg.Metadata = metadata
+			return msg
+		},
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			metadata := getSampleMetadata()
+
+			envelope, err := test.message(metadata.ToMap()).ToEnvelope()
+			assert.NoError(t, err)
+
+			msgs := MsgEnvelopesToMessages([]*repository.MessageEnvelope{envelope}, ConvertEnvelopeToStreamMessage)
+			if !assert.Len(t, msgs, 1) {
+				return
+			}
+			read, err := MetadataOf(msgs[0])
+
+			assert.NoError(t, err)
+			assert.Equal(t, metadata, read)
+		})
+	}
+}
+
+func TestFollowMetadata(t *testing.T) {
+	previous := getSampleCommand()
+	previous.MessageVersion = 7
+	previous.GlobalPosition = 52
+	previous.Metadata = getSampleMetadata().ToMap()
+
+	metadata, err := FollowMetadata(previous)
+
+	assert.NoError(t, err)
+	assert.Equal(t, Metadata{
+		CausationMessageID:             previous.ID,
+		CausationMessageStreamName:     "test cat:command",
+		CausationMessagePosition:       7,
+		CausationMessageGlobalPosition: 52,
+		CorrelationStreamName:          "transfer-" + uuid9.String(),
+		ReplyStreamName:                "transfer:reply-" + uuid9.String(),
+	}, metadata)
+}
+
+func TestFollowMetadataKeepsTheStartOfAStream(t *testing.T) {
+	previous := getSampleCommand()
+	previous.MessageVersion = 0
+	previous.GlobalPosition = 0
+
+	metadata, err := FollowMetadata(previous)
+	assert.NoError(t, err)
+	values := metadata.ToMap()
+
+	assert.Equal(t, int64(0), values[CausationMessagePositionKey])
+	assert.Equal(t, int64(0), values[CausationMessageGlobalPositionKey])
+}
+
+func TestFollowMetadataChainsMessages(t *testing.T) {
+	command := getSampleCommand()
+	command.GlobalPosition = 10
+	command.Metadata = Metadata{CorrelationStreamName: "transfer-123"}.ToMap()
+
+	first, err := FollowMetadata(command)
+	assert.NoError(t, err)
+	event := getSampleEvent()
+	event.GlobalPosition = 11
+	event.Metadata = first.ToMap()
+
+	second, err := FollowMetadata(event)
+	assert.NoError(t, err)
+
+	assert.Equal(t, command.ID, first.CausationMessageID)
+	assert.Equal(t, "test cat:command", first.CausationMessageStreamName)
+	assert.Equal(t, event.ID, second.CausationMessageID)
+	assert.Equal(t, "test cat-"+uuid8.String(), second.CausationMessageStreamName)
+	assert.Equal(t, int64(11), second.CausationMessageGlobalPosition)
+	assert.Equal(t, "transfer-123", second.CorrelationStreamName, "the correlation stream carries through the whole chain")
+}
+
+func TestFollowMetadataFailsForInvalidMessages(t *testing.T) {
+	previous := getSampleEvent()
+	previous.MessageType = ""
+
+	_, err := FollowMetadata(previous)
+
+	assert.Equal(t, ErrMissingMessageType, err)
+}
+
+func TestFollow(t *testing.T) {
+	previous := getSampleCommand()
+	previous.MessageVersion = 7
+	previous.GlobalPosition = 52
+	previous.Metadata = getSampleMetadata().ToMap()
+	next := getSampleEvent()
+	next.Metadata = map[string]interface{}{"userId": "alice"}
+
+	message, err := Follow(previous, next)
+	if !assert.NoError(t, err) {
+		return
+	}
+	event, ok := message.(*Event)
+	if !assert.True(t, ok, "expected an Event, got %T", message) {
+		return
+	}
+	metadata, err := MetadataOf(event)
+
+	assert.NoError(t, err)
+	assert.Equal(t, next.ID, event.ID)
+	assert.Equal(t, Metadata{
+		CausationMessageID:             previous.ID,
+		CausationMessageStreamName:     "test cat:command",
+		CausationMessagePosition:       7,
+		CausationMessageGlobalPosition: 52,
+		CorrelationStreamName:          "transfer-" + uuid9.String(),
+		ReplyStreamName:                "transfer:reply-" + uuid9.String(),
+		Properties:                     map[string]interface{}{"userId": "alice"},
+	}, metadata)
+	assert.Equal(t, map[string]interface{}{"userId": "alice"}, next.Metadata, "the message passed in should not be changed")
+}
+
+func TestFollowFails(t *testing.T) {
+	invalid := getSampleEvent()
+	invalid.MessageType = ""
+
+	tests := []struct {
+		name     string
+		previous Message
+		next     Message
+		err      error
+	}{{
+		name:     "when the previous message is invalid",
+		previous: invalid,
+		next:     getSampleEvent(),
+		err:      ErrMissingMessageType,
+	}, {
+		name:     "when the next message can't hold metadata",
+		previous: getSampleCommand(),
+		next:     &otherMessage{},
+		err:      ErrMetadataUnsupported,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			message, err := Follow(test.previous, test.next)
+
+			assert.Equal(t, test.err, err)
+			assert.Nil(t, message)
+		})
+	}
+}
+
+func TestWithMetadata(t *testing.T) {
+	previous := getSampleCommand()
+	previous.MessageVersion = 7
+	previous.GlobalPosition = 52
+	followed, err := FollowMetadata(previous)
+	if !assert.NoError(t, err) {
+		return
+	}
+	followed.Properties = map[string]interface{}{"userId": "bob"}
+
+	tests := []struct {
+		name    string
+		message func(metadata map[string]interface{}) Message
+	}{{
+		name: "events",
+		message: func(metadata map[string]interface{}) Message {
+			event := getSampleEvent()
+			event.Metadata = metadata
+			return event
+		},
+	}, {
+		name: "commands",
+		message: func(metadata map[string]interface{}) Message {
+			command := getSampleCommand()
+			command.Metadata = metadata
+			return command
+		},
+	}, {
+		name: "stream messages",
+		message: func(metadata map[string]interface{}) Message {
+			msg := getSampleStreamMessage()
+			msg.Metadata = metadata
+			return msg
+		},
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			original := test.message(map[string]interface{}{"userId": "alice", "tenant": "acme"})
+
+			message, err := WithMetadata(original, followed)
+			assert.NoError(t, err)
+			envelope, err := message.ToEnvelope()
+			assert.NoError(t, err)
+			msgs := MsgEnvelopesToMessages([]*repository.MessageEnvelope{envelope}, ConvertEnvelopeToStreamMessage)
+			if !assert.Len(t, msgs, 1) {
+				return
+			}
+			read, err := MetadataOf(msgs[0])
+
+			assert.NoError(t, err)
+			assert.Equal(t, Metadata{
+				CausationMessageID:             previous.ID,
+				CausationMessageStreamName:     "test cat:command",
+				CausationMessagePosition:       7,
+				CausationMessageGlobalPosition: 52,
+				Properties:                     map[string]interface{}{"userId": "bob", "tenant": "acme"},
+			}, read)
+			unchanged, err := MetadataOf(original)
+			assert.NoError(t, err)
+			assert.Equal(t, map[string]interface{}{"userId": "alice", "tenant": "acme"}, unchanged.Properties, "the message passed in should not be changed")
+		})
+	}
+}
+
+func TestWithMetadataFailsForOtherMessages(t *testing.T) {
+	message, err := WithMetadata(&otherMessage{}, Metadata{CorrelationStreamName: "transfer-123"})
+
+	assert.Equal(t, ErrMetadataUnsupported, err)
+	assert.Nil(t, message)
+}
@@ -7,6 +7,7 @@ package mock_gomessagestore
 import (
 	context "context"
 	gomessagestore "github.com/blackhatbrigade/gomessagestore"
+	uuid "github.com/blackhatbrigade/gomessagestore/uuid"
 	gomock "github.com/golang/mock/gomock"
 	logrus "github.com/sirupsen/logrus"
 	reflect "reflect"
@@ -35,6 +36,26 @@ func (m *MockMessageStore) EXPECT() *MockMessageStoreMockRecorder {
 	return m.recorder
 }
 
+// CreateAggregate mocks base method
+func (m *MockMessageStore) CreateAggregate(arg0 []gomessagestore.ProjectorOption, arg1 ...gomessagestore.AggregateOption) (gomessagestore.Aggregate, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0}
+	for _, a := range arg1 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "CreateAggregate", varargs...)
+	ret0, _ := ret[0].(gomessagestore.Aggregate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAggregate indicates an expected call of CreateAggregate
+func (mr *MockMessageStoreMockRecorder) CreateAggregate(arg0 interface{}, arg1 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0}, arg1...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAggregate", reflect.TypeOf((*MockMessageStore)(nil).CreateAggregate), varargs...)
+}
+
 // CreateProjector mocks base method
 func (m *MockMessageStore) CreateProjector(arg0 ...gomessagestore.ProjectorOption) (gomessagestore.Projector, error) {
 	m.ctrl.T.Helper()
@@ -94,6 +115,26 @@ func (mr *MockMessageStoreMockRecorder) Get(arg0 interface{}, arg1 ...interface{
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockMessageStore)(nil).Get), varargs...)
 }
 
+// GetByID mocks base method
+func (m *MockMessageStore) GetByID(arg0 context.Context, arg1 uuid.UUID, arg2 ...gomessagestore.MessageConverter) (gomessagestore.Message, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetByID", varargs...)
+	ret0, _ := ret[0].(gomessagestore.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID
+func (mr *MockMessageStoreMockRecorder) GetByID(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockMessageStore)(nil).GetByID), varargs...)
+}
+
 // GetLogger mocks base method
 func (m *MockMessageStore) GetLogger() logrus.FieldLogger {
 	m.ctrl.T.Helper()
@@ -108,6 +149,26 @@ func (mr *MockMessageStoreMockRecorder) GetLogger() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLogger", reflect.TypeOf((*MockMessageStore)(nil).GetLogger))
 }
 
+// Request mocks base method
+func (m *MockMessageStore) Request(arg0 context.Context, arg1 gomessagestore.Message, arg2 string, arg3 ...gomessagestore.RequestOption) (gomessagestore.Message, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1, arg2}
+	for _, a := range arg3 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Request", varargs...)
+	ret0, _ := ret[0].(gomessagestore.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Request indicates an expected call of Request
+func (mr *MockMessageStoreMockRecorder) Request(arg0, arg1, arg2 interface{}, arg3 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1, arg2}, arg3...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Request", reflect.TypeOf((*MockMessageStore)(nil).Request), varargs...)
+}
+
 // Write mocks base method
 func (m *MockMessageStore) Write(arg0 context.Context, arg1 gomessagestore.Message, arg2 ...gomessagestore.WriteOption) error {
 	m.ctrl.T.Helper()
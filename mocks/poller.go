@@ -33,6 +33,35 @@ func (m *MockPoller) EXPECT() *MockPollerMockRecorder {
 	return m.recorder
 }
 
+// CatchingUp mocks base method
+func (m *MockPoller) CatchingUp() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CatchingUp")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// CatchingUp indicates an expected call of CatchingUp
+func (mr *MockPollerMockRecorder) CatchingUp() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CatchingUp", reflect.TypeOf((*MockPoller)(nil).CatchingUp))
+}
+
+// LastBatch mocks base method
+func (m *MockPoller) LastBatch() (int, int) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LastBatch")
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(int)
+	return ret0, ret1
+}
+
+// LastBatch indicates an expected call of LastBatch
+func (mr *MockPollerMockRecorder) LastBatch() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LastBatch", reflect.TypeOf((*MockPoller)(nil).LastBatch))
+}
+
 // Poll mocks base method
 func (m *MockPoller) Poll(arg0 context.Context) error {
 	m.ctrl.T.Helper()
@@ -46,3 +75,17 @@ func (mr *MockPollerMockRecorder) Poll(arg0 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Poll", reflect.TypeOf((*MockPoller)(nil).Poll), arg0)
 }
+
+// ResetPosition mocks base method
+func (m *MockPoller) ResetPosition(arg0 context.Context, arg1 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetPosition", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResetPosition indicates an expected call of ResetPosition
+func (mr *MockPollerMockRecorder) ResetPosition(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetPosition", reflect.TypeOf((*MockPoller)(nil).ResetPosition), arg0, arg1)
+}
@@ -50,6 +50,81 @@ func (mr *MockProjectorMockRecorder) Run(arg0, arg1, arg2 interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Run", reflect.TypeOf((*MockProjector)(nil).Run), arg0, arg1, arg2)
 }
 
+// RunStreamID mocks base method
+func (m *MockProjector) RunStreamID(arg0 context.Context, arg1, arg2 string) (interface{}, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RunStreamID", arg0, arg1, arg2)
+	ret0, _ := ret[0].(interface{})
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RunStreamID indicates an expected call of RunStreamID
+func (mr *MockProjectorMockRecorder) RunStreamID(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunStreamID", reflect.TypeOf((*MockProjector)(nil).RunStreamID), arg0, arg1, arg2)
+}
+
+// RunStreamIDUntil mocks base method
+func (m *MockProjector) RunStreamIDUntil(arg0 context.Context, arg1, arg2 string, arg3 gomessagestore.RunBoundary) (gomessagestore.Projection, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RunStreamIDUntil", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(gomessagestore.Projection)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RunStreamIDUntil indicates an expected call of RunStreamIDUntil
+func (mr *MockProjectorMockRecorder) RunStreamIDUntil(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunStreamIDUntil", reflect.TypeOf((*MockProjector)(nil).RunStreamIDUntil), arg0, arg1, arg2, arg3)
+}
+
+// RunStreamIDWithVersion mocks base method
+func (m *MockProjector) RunStreamIDWithVersion(arg0 context.Context, arg1, arg2 string) (gomessagestore.Projection, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RunStreamIDWithVersion", arg0, arg1, arg2)
+	ret0, _ := ret[0].(gomessagestore.Projection)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RunStreamIDWithVersion indicates an expected call of RunStreamIDWithVersion
+func (mr *MockProjectorMockRecorder) RunStreamIDWithVersion(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunStreamIDWithVersion", reflect.TypeOf((*MockProjector)(nil).RunStreamIDWithVersion), arg0, arg1, arg2)
+}
+
+// RunUntil mocks base method
+func (m *MockProjector) RunUntil(arg0 context.Context, arg1 string, arg2 uuid.UUID, arg3 gomessagestore.RunBoundary) (gomessagestore.Projection, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RunUntil", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(gomessagestore.Projection)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RunUntil indicates an expected call of RunUntil
+func (mr *MockProjectorMockRecorder) RunUntil(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunUntil", reflect.TypeOf((*MockProjector)(nil).RunUntil), arg0, arg1, arg2, arg3)
+}
+
+// RunWithVersion mocks base method
+func (m *MockProjector) RunWithVersion(arg0 context.Context, arg1 string, arg2 uuid.UUID) (gomessagestore.Projection, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RunWithVersion", arg0, arg1, arg2)
+	ret0, _ := ret[0].(gomessagestore.Projection)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RunWithVersion indicates an expected call of RunWithVersion
+func (mr *MockProjectorMockRecorder) RunWithVersion(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunWithVersion", reflect.TypeOf((*MockProjector)(nil).RunWithVersion), arg0, arg1, arg2)
+}
+
 // Step mocks base method
 func (m *MockProjector) Step(arg0 gomessagestore.Message, arg1 interface{}) (interface{}, bool) {
 	m.ctrl.T.Helper()
@@ -33,6 +33,44 @@ func (m *MockSubscriber) EXPECT() *MockSubscriberMockRecorder {
 	return m.recorder
 }
 
+// Pause mocks base method
+func (m *MockSubscriber) Pause() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Pause")
+}
+
+// Pause indicates an expected call of Pause
+func (mr *MockSubscriberMockRecorder) Pause() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Pause", reflect.TypeOf((*MockSubscriber)(nil).Pause))
+}
+
+// ResetPosition mocks base method
+func (m *MockSubscriber) ResetPosition(arg0 context.Context, arg1 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetPosition", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResetPosition indicates an expected call of ResetPosition
+func (mr *MockSubscriberMockRecorder) ResetPosition(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetPosition", reflect.TypeOf((*MockSubscriber)(nil).ResetPosition), arg0, arg1)
+}
+
+// Resume mocks base method
+func (m *MockSubscriber) Resume() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Resume")
+}
+
+// Resume indicates an expected call of Resume
+func (mr *MockSubscriberMockRecorder) Resume() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Resume", reflect.TypeOf((*MockSubscriber)(nil).Resume))
+}
+
 // Start mocks base method
 func (m *MockSubscriber) Start(arg0 context.Context) error {
 	m.ctrl.T.Helper()
@@ -35,18 +35,19 @@ func (m *MockSubscriptionWorker) EXPECT() *MockSubscriptionWorkerMockRecorder {
 }
 
 // GetMessages mocks base method
-func (m *MockSubscriptionWorker) GetMessages(arg0 context.Context, arg1 int64) ([]gomessagestore.Message, error) {
+func (m *MockSubscriptionWorker) GetMessages(arg0 context.Context, arg1 int64, arg2 int) ([]gomessagestore.Message, gomessagestore.ReadBatch, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetMessages", arg0, arg1)
+	ret := m.ctrl.Call(m, "GetMessages", arg0, arg1, arg2)
 	ret0, _ := ret[0].([]gomessagestore.Message)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret1, _ := ret[1].(gomessagestore.ReadBatch)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
 }
 
 // GetMessages indicates an expected call of GetMessages
-func (mr *MockSubscriptionWorkerMockRecorder) GetMessages(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockSubscriptionWorkerMockRecorder) GetMessages(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMessages", reflect.TypeOf((*MockSubscriptionWorker)(nil).GetMessages), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMessages", reflect.TypeOf((*MockSubscriptionWorker)(nil).GetMessages), arg0, arg1, arg2)
 }
 
 // GetPosition mocks base method
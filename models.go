@@ -2,11 +2,10 @@ package gomessagestore
 
 import (
 	"encoding/json"
-	"fmt"
-	"strings"
 	"time"
 
 	"github.com/blackhatbrigade/gomessagestore/repository"
+	"github.com/blackhatbrigade/gomessagestore/streamname"
 	"github.com/blackhatbrigade/gomessagestore/uuid"
 )
 
@@ -21,6 +20,8 @@ func NewID() uuid.UUID {
 // Command implements the Message interface; returned by get function
 type Command struct {
 	ID             uuid.UUID // ID for the command
+	EntityID       uuid.UUID // ID of the entity the command is for; when not set, the command is written to the command stream shared by the whole category
+	StreamID       string    // ID of the entity the command is for, as it appears in the stream name; use instead of EntityID for IDs that are not UUIDs
 	StreamCategory string    // Name of the stream category
 	MessageType    string    // Name of the message type
 	MessageVersion int64     // version number of the message
@@ -56,7 +57,7 @@ func (cmd *Command) ToEnvelope() (*repository.MessageEnvelope, error) {
 		return nil, ErrMissingMessageCategory
 	}
 
-	if strings.Contains(cmd.StreamCategory, "-") {
+	if !streamname.IsCategory(cmd.StreamCategory) {
 		return nil, ErrInvalidMessageCategory
 	}
 
@@ -74,11 +75,21 @@ func (cmd *Command) ToEnvelope() (*repository.MessageEnvelope, error) {
 		return nil, ErrUnserializableData
 	}
 
+	streamID, err := resolveStreamID(cmd.EntityID, cmd.StreamID)
+	if err != nil {
+		return nil, err
+	}
+
+	stream := streamname.AddTypes(cmd.StreamCategory, streamname.CommandType)
+	if streamID != "" {
+		stream = streamname.Compose(stream, streamID)
+	}
+
 	// create a new MessageEnvelope based on the command
 	msgEnv := &repository.MessageEnvelope{
 		ID:             cmd.ID,
 		MessageType:    cmd.MessageType,
-		StreamName:     fmt.Sprintf("%s:command", cmd.StreamCategory),
+		StreamName:     stream,
 		StreamCategory: cmd.StreamCategory,
 		Data:           data,
 		Metadata:       metadata,
@@ -93,6 +104,7 @@ func (cmd *Command) ToEnvelope() (*repository.MessageEnvelope, error) {
 type Event struct {
 	ID             uuid.UUID // ID of the event
 	EntityID       uuid.UUID // ID of the entity the event is associated with
+	StreamID       string    // ID of the entity the event is associated with, as it appears in the stream name; use instead of EntityID for IDs that are not UUIDs
 	StreamCategory string    // the name of the category of the stream
 	MessageType    string    // the message type of the event
 	MessageVersion int64     // the version number of the message
@@ -124,7 +136,7 @@ func (event *Event) ToEnvelope() (*repository.MessageEnvelope, error) {
 		return nil, ErrMissingMessageType
 	}
 
-	if strings.Contains(event.StreamCategory, "-") {
+	if !streamname.IsCategory(event.StreamCategory) {
 		return nil, ErrInvalidMessageCategory
 	}
 
@@ -136,7 +148,11 @@ func (event *Event) ToEnvelope() (*repository.MessageEnvelope, error) {
 		return nil, ErrMessageNoID
 	}
 
-	if event.EntityID == NilUUID {
+	streamID, err := resolveStreamID(event.EntityID, event.StreamID)
+	if err != nil {
+		return nil, err
+	}
+	if streamID == "" {
 		return nil, ErrMissingMessageCategoryID
 	}
 
@@ -154,7 +170,7 @@ func (event *Event) ToEnvelope() (*repository.MessageEnvelope, error) {
 	msgEnv := &repository.MessageEnvelope{
 		ID:             event.ID,
 		MessageType:    event.MessageType,
-		StreamName:     fmt.Sprintf("%s-%s", event.StreamCategory, event.EntityID),
+		StreamName:     streamname.Compose(event.StreamCategory, streamID),
 		StreamCategory: event.StreamCategory,
 		Data:           data,
 		Metadata:       metadata,
@@ -165,3 +181,89 @@ func (event *Event) ToEnvelope() (*repository.MessageEnvelope, error) {
 
 	return msgEnv, nil
 }
+
+// StreamMessage implements the Message interface for a message in any stream, named exactly; returned by get function when using the Stream option
+type StreamMessage struct {
+	ID             uuid.UUID // ID of the message
+	StreamName     string    // the full name of the stream the message is in, such as account:snapshot-123
+	MessageType    string    // the message type of the message
+	MessageVersion int64     // the version number of the message
+	GlobalPosition int64     // the global position of the message
+	Data           map[string]interface{}
+	Metadata       map[string]interface{}
+	Time           time.Time
+}
+
+// Type returns the type of the message
+func (msg *StreamMessage) Type() string {
+	return msg.MessageType
+}
+
+// Version returns the version of the message
+func (msg *StreamMessage) Version() int64 {
+	return msg.MessageVersion
+}
+
+// Position returns the global position of the message
+func (msg *StreamMessage) Position() int64 {
+	return msg.GlobalPosition
+}
+
+// ToEnvelope converts the message to a MessageEnvelope which is then returned
+func (msg *StreamMessage) ToEnvelope() (*repository.MessageEnvelope, error) {
+	// check to ensure that all required fields of the message are valid
+	if msg.MessageType == "" {
+		return nil, ErrMissingMessageType
+	}
+
+	if msg.StreamName == "" {
+		return nil, ErrMissingStreamName
+	}
+
+	if msg.Data == nil {
+		return nil, ErrMissingMessageData
+	}
+
+	if msg.ID == NilUUID {
+		return nil, ErrMessageNoID
+	}
+
+	data, err := json.Marshal(msg.Data)
+	metadata, errm := json.Marshal(msg.Metadata)
+	if err != nil || errm != nil {
+		return nil, ErrUnserializableData
+	}
+
+	// create a new MessageEnvelope based on the message
+	msgEnv := &repository.MessageEnvelope{
+		ID:             msg.ID,
+		MessageType:    msg.MessageType,
+		StreamName:     msg.StreamName,
+		StreamCategory: streamname.Category(msg.StreamName),
+		Data:           data,
+		Metadata:       metadata,
+		Time:           msg.Time,
+		Version:        msg.MessageVersion,
+		GlobalPosition: msg.GlobalPosition,
+	}
+
+	return msgEnv, nil
+}
+
+// resolveStreamID picks the ID that goes in the stream name from a message's EntityID and StreamID; when both are set they must name the same entity
+func resolveStreamID(entityID uuid.UUID, streamID string) (string, error) {
+	if streamID == "" {
+		if entityID == NilUUID {
+			return "", nil
+		}
+		return entityID.String(), nil
+	}
+
+	if entityID != NilUUID {
+		if parsed, err := uuid.Parse(streamID); err != nil || parsed != entityID {
+			return "", ErrEntityIDMismatch
+		}
+	}
+
+	return streamID, nil
+}
@@ -18,6 +18,7 @@ func getSampleEventMissing(key string) *Event {
 		event.MessageType = ""
 	case "EntityID":
 		event.EntityID = NilUUID
+		event.StreamID = ""
 	case "StreamCategory":
 		event.StreamCategory = ""
 	case "Data":
@@ -33,6 +34,8 @@ func getSampleEventMalformed(key string) *Event {
 	switch key {
 	case "CategoryHyphen":
 		event.StreamCategory = "something-bad"
+	case "EntityIDMismatch":
+		event.StreamID = uuid9.String()
 	}
 
 	return event
@@ -138,6 +141,11 @@ func TestCommandToEnvelope(t *testing.T) {
 		inputCommand:     getSampleCommand(),
 		failEnvMessage:   "Did not get a valid MessageEnvelope back from ToEnvelope",
 		expectedEnvelope: getSampleCommandAsEnvelope(),
+	}, {
+		name:             "Returns message envelope in the entity's command stream when EntityID is set",
+		inputCommand:     getSampleEntityCommand(),
+		failEnvMessage:   "Did not get a valid MessageEnvelope back from ToEnvelope",
+		expectedEnvelope: getSampleEntityCommandAsEnvelope(),
 	}, {
 		name:           "Errors if no MessageType",
 		inputCommand:   getSampleCommandMissing("MessageType"),
@@ -194,6 +202,25 @@ func TestEventToEnvelope(t *testing.T) {
 		inputEvent:     getSampleEventMissing("EntityID"),
 		expectedError:  ErrMissingMessageCategoryID,
 		failErrMessage: "Expected a NEW ID for Event",
+	}, {
+		name: "Returns message envelope when only StreamID is set",
+		inputEvent: func() *Event {
+			event := getSampleEvent()
+			event.EntityID = NilUUID
+			event.StreamID = "ORD123"
+			return event
+		}(),
+		failEnvMessage: "Didn't render the MessageEnvelope correctly",
+		expectedEnvelope: func() *repository.MessageEnvelope {
+			msgEnv := getSampleEventAsEnvelope()
+			msgEnv.StreamName = "test cat-ORD123"
+			return msgEnv
+		}(),
+	}, {
+		name:           "Errors if EntityID and StreamID name different entities",
+		inputEvent:     getSampleEventMalformed("EntityIDMismatch"),
+		expectedError:  ErrEntityIDMismatch,
+		failErrMessage: "EntityID and StreamID must match",
 	}, {
 		name:           "Errors if a hyphen is present in the StreamCategory name",
 		inputEvent:     getSampleEventMalformed("CategoryHyphen"),
@@ -230,3 +257,50 @@ func TestEventToEnvelope(t *testing.T) {
 		})
 	}
 }
+
+//TestStreamMessageToEnvelope tests streamMessage.ToEnvelope
+func TestStreamMessageToEnvelope(t *testing.T) {
+	tests := []struct {
+		name             string
+		change           func(msg *StreamMessage)
+		expectedEnvelope *repository.MessageEnvelope
+		expectedError    error
+	}{{
+		name:             "Returns message envelope with the exact stream name",
+		change:           func(msg *StreamMessage) {},
+		expectedEnvelope: getSampleStreamMessageAsEnvelope(),
+	}, {
+		name:          "Errors if no ID",
+		change:        func(msg *StreamMessage) { msg.ID = NilUUID },
+		expectedError: ErrMessageNoID,
+	}, {
+		name:          "Errors if the stream name is left blank",
+		change:        func(msg *StreamMessage) { msg.StreamName = "" },
+		expectedError: ErrMissingStreamName,
+	}, {
+		name:          "Errors if data is nil",
+		change:        func(msg *StreamMessage) { msg.Data = nil },
+		expectedError: ErrMissingMessageData,
+	}, {
+		name:          "Errors if MessageType is left blank",
+		change:        func(msg *StreamMessage) { msg.MessageType = "" },
+		expectedError: ErrMissingMessageType,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			msg := getSampleStreamMessage()
+			test.change(msg)
+
+			msgEnv, err := msg.ToEnvelope()
+
+			if err != test.expectedError {
+				t.Errorf("Expected: %v\nActual: %v\n", test.expectedError, err)
+			}
+
+			if !reflect.DeepEqual(msgEnv, test.expectedEnvelope) {
+				t.Errorf("Expected: %v\nActual: %v\n", test.expectedEnvelope, msgEnv)
+			}
+		})
+	}
+}
@@ -1,43 +1,92 @@
 package gomessagestore
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
-	"strings"
 
 	"github.com/blackhatbrigade/gomessagestore/repository"
+	"github.com/blackhatbrigade/gomessagestore/streamname"
 	"github.com/blackhatbrigade/gomessagestore/uuid"
 	"github.com/sirupsen/logrus"
 )
 
+type packOpts struct {
+	exactNumbers bool
+}
+
+// PackOption provides optional arguments to Pack and Unpack
+type PackOption func(opts *packOpts)
+
+// ExactNumbers keeps numbers as json.Number instead of float64, so int64 IDs and large amounts aren't rounded above 2^53
+// With Pack, the numbers in the returned map are json.Number; with Unpack, it applies to numbers decoded into interface{} values in dest.
+func ExactNumbers() PackOption {
+	return func(opts *packOpts) {
+		opts.exactNumbers = true
+	}
+}
+
+func checkPackOptions(opts ...PackOption) *packOpts {
+	packOptions := &packOpts{}
+	for _, option := range opts {
+		option(packOptions)
+	}
+	return packOptions
+}
+
 //Unpack unpacks JSON-esque objects used in the Command and Event objects into GO objects
-func Unpack(source map[string]interface{}, dest interface{}) error {
+func Unpack(source map[string]interface{}, dest interface{}, opts ...PackOption) error {
 	inbetween, err := json.Marshal(source)
 	if err != nil {
 		return err
 	}
 
-	return json.Unmarshal(inbetween, dest)
+	return decodeJSON(inbetween, dest, checkPackOptions(opts...).exactNumbers)
 }
 
 //Pack packs a GO object into JSON-esque objects used in the Command and Event objects
-func Pack(source interface{}) (map[string]interface{}, error) {
+func Pack(source interface{}, opts ...PackOption) (map[string]interface{}, error) {
 	dest := make(map[string]interface{})
 	inbetween, err := json.Marshal(source)
 	if err != nil {
 		return nil, err
 	}
 
-	err = json.Unmarshal(inbetween, &dest)
+	err = decodeJSON(inbetween, &dest, checkPackOptions(opts...).exactNumbers)
 	return dest, err
 }
 
+// decodeJSON unmarshals data into v, keeping numbers as json.Number when exactNumbers is set
+func decodeJSON(data []byte, v interface{}, exactNumbers bool) error {
+	if !exactNumbers {
+		return json.Unmarshal(data, v)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(v); err != nil {
+		return err
+	}
+	if decoder.More() {
+		return errors.New("unexpected data after top-level JSON value")
+	}
+	return nil
+}
+
 // MessageConverter is a function that takes in a MessageEnvelope and returns a Message; can be used to create custom messages
 type MessageConverter func(*repository.MessageEnvelope) (Message, error)
 
 // MsgEnvelopesToMessages converts envelopes to any number of different structs that impliment the Message interface
+// Data written with the codecs shipped in this package is decoded; each envelope that can't be decoded is logged and skipped.
 func MsgEnvelopesToMessages(msgEnvelopes []*repository.MessageEnvelope, converters ...MessageConverter) []Message {
-	myConverters := append(converters, defaultConverters()...)
+	messages, _ := msgEnvelopesToMessages(msgEnvelopes, newCodecs(), nil, false, false, converters...)
+	return messages
+}
+
+// msgEnvelopesToMessages decodes each envelope's data back to JSON and upcasts it to the current schema version of its message type, when there is a registry, before converting it
+// With exactNumbers, the upcasters and the default converters keep numbers as json.Number. Envelopes that can't be decoded or upcast are logged and skipped, so one bad message can't stop every reader of its stream, unless strict asks for the error instead.
+func msgEnvelopesToMessages(msgEnvelopes []*repository.MessageEnvelope, codecs *codecs, registry *Registry, exactNumbers bool, strict bool, converters ...MessageConverter) ([]Message, error) {
+	myConverters := append(converters[:len(converters):len(converters)], defaultConverters(exactNumbers)...)
 
 	messages := make([]Message, 0, len(msgEnvelopes))
 	for _, messageEnvelope := range msgEnvelopes {
@@ -46,34 +95,73 @@ func MsgEnvelopesToMessages(msgEnvelopes []*repository.MessageEnvelope, converte
 			continue
 		}
 
-		for _, converter := range myConverters {
-			message, err := converter(messageEnvelope)
-			if message != nil && err == nil {
-				messages = append(messages, message)
-				break // only one successful conversion per envelope
-			}
-
+		message, err := msgEnvelopeToMessage(messageEnvelope, codecs, registry, exactNumbers, myConverters)
+		if err != nil && strict {
+			return nil, err
+		}
+		if err != nil {
+			logrus.WithError(err).WithField("messageId", messageEnvelope.ID).Error("Can't decode or upcast message envelope, skipping it")
+			continue
+		}
+		if message != nil {
+			messages = append(messages, message)
 		}
 	}
 
-	return messages
+	return messages, nil
 }
 
-// convertEnvelopeToCommand strips out data from a MessageEnvelope to form a Message of type command
-func convertEnvelopeToCommand(messageEnvelope *repository.MessageEnvelope) (Message, error) {
-	if strings.HasSuffix(messageEnvelope.StreamName, ":command") {
-		data := make(map[string]interface{})
-		if err := json.Unmarshal(messageEnvelope.Data, &data); err != nil {
-			logrus.WithError(err).Error("Can't unmarshal JSON from message envelope data")
+// msgEnvelopeToMessage decodes an envelope, upcasts it when there is a registry, and converts it with the first converter that succeeds; the message is nil when none do
+func msgEnvelopeToMessage(messageEnvelope *repository.MessageEnvelope, codecs *codecs, registry *Registry, exactNumbers bool, converters []MessageConverter) (Message, error) {
+	messageEnvelope, err := codecs.decode(messageEnvelope)
+	if err != nil {
+		return nil, err
+	}
+
+	if registry != nil {
+		upcast, err := registry.upcast(messageEnvelope, exactNumbers)
+		if err != nil {
+			return nil, err
 		}
-		metadata := make(map[string]interface{})
-		if err := json.Unmarshal(messageEnvelope.Metadata, &metadata); err != nil {
-			logrus.WithError(err).Error("Can't unmarshal JSON from message envelope metadata")
+		messageEnvelope = upcast
+	}
+
+	for _, converter := range converters {
+		message, err := converter(messageEnvelope)
+		if message != nil && err == nil {
+			return message, nil // only one successful conversion per envelope
 		}
+	}
+
+	return nil, nil
+}
+
+// decodeJSONMaps unmarshals the data and metadata of an envelope into maps, logging anything that can't be unmarshalled
+func decodeJSONMaps(messageEnvelope *repository.MessageEnvelope, exactNumbers bool) (data, metadata map[string]interface{}) {
+	data = make(map[string]interface{})
+	if err := decodeJSON(messageEnvelope.Data, &data, exactNumbers); err != nil {
+		logrus.WithError(err).Error("Can't unmarshal JSON from message envelope data")
+	}
+	metadata = make(map[string]interface{})
+	if err := decodeJSON(messageEnvelope.Metadata, &metadata, exactNumbers); err != nil {
+		logrus.WithError(err).Error("Can't unmarshal JSON from message envelope metadata")
+	}
+
+	return data, metadata
+}
+
+// convertEnvelopeToCommand strips out data from a MessageEnvelope to form a Message of type command
+func convertEnvelopeToCommand(messageEnvelope *repository.MessageEnvelope, exactNumbers bool) (Message, error) {
+	if isCommandStream(messageEnvelope.StreamName) {
+		data, metadata := decodeJSONMaps(messageEnvelope, exactNumbers)
+		streamID := streamname.ID(messageEnvelope.StreamName)
+		entityID, _ := uuid.Parse(streamID) // category command streams and IDs that aren't UUIDs leave entityID blank
 		command := &Command{
 			ID:             messageEnvelope.ID,
+			EntityID:       entityID,
+			StreamID:       streamID,
 			MessageType:    messageEnvelope.MessageType,
-			StreamCategory: strings.TrimSuffix(messageEnvelope.StreamName, ":command"),
+			StreamCategory: streamname.EntityName(messageEnvelope.StreamName),
 			MessageVersion: messageEnvelope.Version,
 			GlobalPosition: messageEnvelope.GlobalPosition,
 			Data:           data,
@@ -87,24 +175,11 @@ func convertEnvelopeToCommand(messageEnvelope *repository.MessageEnvelope) (Mess
 }
 
 // convertEnvelopeToEvent strips out data from a MessageEnvelope to form a Message of type event
-func convertEnvelopeToEvent(messageEnvelope *repository.MessageEnvelope) (Message, error) {
-	data := make(map[string]interface{})
-	if err := json.Unmarshal(messageEnvelope.Data, &data); err != nil {
-		logrus.WithError(err).Error("Can't unmarshal JSON from message envelope data")
-	}
-	metadata := make(map[string]interface{})
-	if err := json.Unmarshal(messageEnvelope.Metadata, &metadata); err != nil {
-		logrus.WithError(err).Error("Can't unmarshal JSON from message envelope metadata")
-	}
-	category := ""
-	var id uuid.UUID
-	cats := strings.SplitN(messageEnvelope.StreamName, "-", 2)
-	if len(cats) > 0 {
-		category = cats[0]
-		if len(cats) == 2 {
-			id, _ = uuid.Parse(cats[1]) // errors on parsing just leave entityID blank
-		}
-	}
+func convertEnvelopeToEvent(messageEnvelope *repository.MessageEnvelope, exactNumbers bool) (Message, error) {
+	data, metadata := decodeJSONMaps(messageEnvelope, exactNumbers)
+	category := streamname.Category(messageEnvelope.StreamName)
+	streamID := streamname.ID(messageEnvelope.StreamName)
+	id, _ := uuid.Parse(streamID) // IDs that aren't UUIDs leave entityID blank, but are still in streamID
 	event := &Event{
 		ID:             messageEnvelope.ID,
 		MessageVersion: messageEnvelope.Version,
@@ -112,6 +187,7 @@ func convertEnvelopeToEvent(messageEnvelope *repository.MessageEnvelope) (Messag
 		MessageType:    messageEnvelope.MessageType,
 		StreamCategory: category,
 		EntityID:       id,
+		StreamID:       streamID,
 		Data:           data,
 		Metadata:       metadata,
 		Time:           messageEnvelope.Time,
@@ -120,9 +196,44 @@ func convertEnvelopeToEvent(messageEnvelope *repository.MessageEnvelope) (Messag
 	return event, nil
 }
 
-func defaultConverters() []MessageConverter {
+// isCommandStream checks for a stream whose only type is command, such as account:command or account:command-123
+func isCommandStream(streamName string) bool {
+	types := streamname.Types(streamName)
+	return len(types) == 1 && types[0] == streamname.CommandType
+}
+
+// ConvertEnvelopeToStreamMessage converts any MessageEnvelope to a StreamMessage, keeping its full stream name; pass it to Converter() to read messages that are neither commands nor events
+func ConvertEnvelopeToStreamMessage(messageEnvelope *repository.MessageEnvelope) (Message, error) {
+	return convertEnvelopeToStreamMessage(messageEnvelope, false)
+}
+
+// convertEnvelopeToStreamMessage converts any MessageEnvelope to a StreamMessage
+func convertEnvelopeToStreamMessage(messageEnvelope *repository.MessageEnvelope, exactNumbers bool) (Message, error) {
+	data, metadata := decodeJSONMaps(messageEnvelope, exactNumbers)
+	msg := &StreamMessage{
+		ID:             messageEnvelope.ID,
+		StreamName:     messageEnvelope.StreamName,
+		MessageType:    messageEnvelope.MessageType,
+		MessageVersion: messageEnvelope.Version,
+		GlobalPosition: messageEnvelope.GlobalPosition,
+		Data:           data,
+		Metadata:       metadata,
+		Time:           messageEnvelope.Time,
+	}
+
+	return msg, nil
+}
+
+// withExactNumbers binds the number handling into a MessageConverter
+func withExactNumbers(convert func(*repository.MessageEnvelope, bool) (Message, error), exactNumbers bool) MessageConverter {
+	return func(messageEnvelope *repository.MessageEnvelope) (Message, error) {
+		return convert(messageEnvelope, exactNumbers)
+	}
+}
+
+func defaultConverters(exactNumbers bool) []MessageConverter {
 	return []MessageConverter{
-		convertEnvelopeToCommand,
-		convertEnvelopeToEvent, // always run this one last, as it always passes
+		withExactNumbers(convertEnvelopeToCommand, exactNumbers),
+		withExactNumbers(convertEnvelopeToEvent, exactNumbers), // always run this one last, as it always passes
 	}
 }
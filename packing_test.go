@@ -105,10 +105,24 @@ func TestPackUnpack(t *testing.T) {
 	}
 }
 
+func streamMessageFromEvent(event *Event) *StreamMessage {
+	return &StreamMessage{
+		ID:             event.ID,
+		StreamName:     event.StreamCategory + "-" + event.StreamID,
+		MessageType:    event.MessageType,
+		MessageVersion: event.MessageVersion,
+		GlobalPosition: event.GlobalPosition,
+		Data:           event.Data,
+		Metadata:       event.Metadata,
+		Time:           event.Time,
+	}
+}
+
 func TestMsgEnvelopesToMessages(t *testing.T) {
 	tests := []struct {
 		name           string
 		input          []*repository.MessageEnvelope
+		converters     []MessageConverter
 		expectedOutput []Message
 	}{{
 		name:           "converts message envelopes to events",
@@ -118,15 +132,112 @@ func TestMsgEnvelopesToMessages(t *testing.T) {
 		name:           "converts message envelopes to commands",
 		input:          getSampleCommandsAsEnvelopes(),
 		expectedOutput: commandsToMessageSlice(getSampleCommands()),
+	}, {
+		name: "keeps entity IDs that are not UUIDs",
+		input: func() []*repository.MessageEnvelope {
+			msgEnv := getSampleEventAsEnvelope()
+			msgEnv.StreamName = "test cat-ORD123"
+			return []*repository.MessageEnvelope{msgEnv}
+		}(),
+		expectedOutput: func() []Message {
+			event := getSampleEvent()
+			event.EntityID = NilUUID
+			event.StreamID = "ORD123"
+			return []Message{event}
+		}(),
+	}, {
+		name:           "converts message envelopes to stream messages",
+		input:          []*repository.MessageEnvelope{getSampleStreamMessageAsEnvelope(), getSampleEventAsEnvelope()},
+		converters:     []MessageConverter{ConvertEnvelopeToStreamMessage},
+		expectedOutput: []Message{getSampleStreamMessage(), streamMessageFromEvent(getSampleEvent())},
+	}, {
+		name:           "converts message envelopes from an entity's command stream to commands",
+		input:          []*repository.MessageEnvelope{getSampleEntityCommandAsEnvelope()},
+		expectedOutput: []Message{getSampleEntityCommand()},
+	}, {
+		name: "skips message envelopes that can't be decoded",
+		input: func() []*repository.MessageEnvelope {
+			undecodable := getSampleEventAsEnvelope()
+			undecodable.Data = []byte(`{"payload":"not base64"}`)
+			undecodable.Metadata = []byte(`{"gomessagestore.codec":"msgpack"}`)
+			return []*repository.MessageEnvelope{undecodable, getSampleEventAsEnvelope()}
+		}(),
+		expectedOutput: []Message{getSampleEvent()},
 	}}
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
 			assert := assert.New(t)
 
-			output := MsgEnvelopesToMessages(test.input)
+			output := MsgEnvelopesToMessages(test.input, test.converters...)
 
 			assert.Equal(test.expectedOutput, output)
 		})
 	}
 }
+
+type largeNumbers struct {
+	AccountID   int64       `json:"accountId"`
+	AmountCents int64       `json:"amountCents"`
+	Counter     uint64      `json:"counter"`
+	Extra       interface{} `json:"extra"`
+}
+
+func TestPackUnpackExactNumbers(t *testing.T) {
+	source := largeNumbers{
+		AccountID:   9007199254740993, // 2^53 + 1, which float64 rounds to 2^53
+		AmountCents: -9223372036854775807,
+		Counter:     18446744073709551615,
+		Extra:       json.Number("123456789012345678901"),
+	}
+
+	tests := []struct {
+		name        string
+		opts        []PackOption
+		expectExact bool
+	}{{
+		name:        "with exact numbers",
+		opts:        []PackOption{ExactNumbers()},
+		expectExact: true,
+	}, {
+		name: "without exact numbers, which rounds large integers",
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			packed, err := Pack(source, test.opts...)
+			assert.NoError(t, err)
+
+			dest := largeNumbers{}
+			err = Unpack(packed, &dest, test.opts...)
+
+			if !test.expectExact {
+				assert.IsType(t, float64(0), packed["accountId"])
+				assert.NotEqual(t, source.AccountID, int64(packed["accountId"].(float64)))
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, json.Number("9007199254740993"), packed["accountId"])
+			assert.Equal(t, json.Number("18446744073709551615"), packed["counter"])
+			assert.Equal(t, source, dest)
+		})
+	}
+}
+
+func TestMsgEnvelopesToMessagesExactNumbers(t *testing.T) {
+	msgEnv := &repository.MessageEnvelope{
+		ID:             uuid5,
+		MessageType:    "Deposited",
+		StreamName:     "account-" + uuid8.String(),
+		StreamCategory: "account",
+		Data:           []byte(`{"accountId":9007199254740993,"amountCents":1234567890123456789,"rate":0.25}`),
+		Metadata:       []byte(`{"sequence":18446744073709551615}`),
+	}
+
+	msgs := MsgEnvelopesToMessages([]*repository.MessageEnvelope{msgEnv})
+
+	if assert.Len(t, msgs, 1) {
+		event := msgs[0].(*Event)
+		assert.IsType(t, float64(0), event.Data["accountId"], "without a store set to exact numbers, the default is unchanged")
+	}
+}
@@ -0,0 +1,83 @@
+package gomessagestore
+
+import (
+	"math/rand"
+	"time"
+)
+
+// pollStrategy decides how long a subscriber waits between polls
+type pollStrategy struct {
+	config     *SubscriberConfig
+	idleDelay  time.Duration // the wait after an idle poll; doubles while the subscriber stays idle
+	errorDelay time.Duration // the wait after a failed poll; doubles while polls keep failing
+	random     *rand.Rand
+}
+
+// newPollStrategy creates a pollStrategy based on the subscriber config
+func newPollStrategy(config *SubscriberConfig) *pollStrategy {
+	return &pollStrategy{
+		config:     config,
+		idleDelay:  config.pollTime,
+		errorDelay: config.pollErrorDelay,
+		random:     rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// afterError returns how long to wait after a poll fails
+func (strat *pollStrategy) afterError() time.Duration {
+	if !strat.config.adaptive {
+		return strat.jitter(strat.config.pollErrorDelay + strat.config.pollTime)
+	}
+
+	delay := strat.errorDelay
+	strat.errorDelay = doubleUpTo(strat.errorDelay, strat.config.maxPollErrorDelay)
+	return strat.jitter(delay)
+}
+
+// afterPoll returns the fixed wait after a successful poll
+func (strat *pollStrategy) afterPoll() time.Duration {
+	strat.errorDelay = strat.config.pollErrorDelay
+	return strat.jitter(strat.config.pollTime)
+}
+
+// afterFullBatch returns the wait after a poll that found more messages waiting, which is none at all
+func (strat *pollStrategy) afterFullBatch() time.Duration {
+	strat.errorDelay = strat.config.pollErrorDelay
+	strat.idleDelay = strat.config.pollTime
+	return 0
+}
+
+// afterBatch returns the adaptive wait after a poll that received some number of messages out of the requested batch size
+func (strat *pollStrategy) afterBatch(received, requested int) time.Duration {
+	if requested > 0 && received >= requested {
+		return strat.afterFullBatch()
+	}
+
+	strat.errorDelay = strat.config.pollErrorDelay
+	if received == 0 {
+		delay := strat.idleDelay
+		strat.idleDelay = doubleUpTo(strat.idleDelay, strat.config.maxPollTime)
+		return strat.jitter(delay)
+	}
+
+	strat.idleDelay = strat.config.pollTime
+	return strat.jitter(strat.config.pollTime)
+}
+
+// jitter randomly moves a delay up or down by at most the configured fraction, so that fleets of subscribers don't poll in lockstep
+func (strat *pollStrategy) jitter(delay time.Duration) time.Duration {
+	if strat.config.pollJitter <= 0 || delay <= 0 {
+		return delay
+	}
+
+	offset := (strat.random.Float64()*2 - 1) * strat.config.pollJitter
+	return delay + time.Duration(float64(delay)*offset)
+}
+
+// doubleUpTo doubles a delay without going over the maximum
+func doubleUpTo(delay, max time.Duration) time.Duration {
+	if delay*2 > max {
+		return max
+	}
+	return delay * 2
+}
@@ -2,13 +2,18 @@ package gomessagestore
 
 import (
 	"context"
+	"sync"
+	"time"
 )
 
 //go:generate bash -c "${GOPATH}/bin/mockgen github.com/blackhatbrigade/gomessagestore Poller > mocks/poller.go"
 
 // Poller interface requires a Poll function
 type Poller interface {
-	Poll(context.Context) error // should handle a cycle of polling the message store
+	Poll(context.Context) error                              // should handle a cycle of polling the message store
+	ResetPosition(ctx context.Context, position int64) error // should store a new position and use it on the next cycle
+	CatchingUp() bool                                        // should report whether the poller is still in catch-up mode
+	LastBatch() (received int, requested int)                // should report how many messages the last poll received and asked for
 }
 
 type poller struct {
@@ -17,6 +22,13 @@ type poller struct {
 	worker              SubscriptionWorker
 	position            int64
 	numberOfMsgsHandled int
+	caughtUp            bool       // set once a poll returns less than a full batch
+	notifiedCaughtUp    bool       // set once onCaughtUp has been called
+	lastReceived        int        // the number of messages the last poll received
+	lastRequested       int        // the batch size the last poll asked for
+	savedPosition       int64      // the position that was last written to the message store
+	savedAt             time.Time  // when savedPosition was written
+	mu                  sync.Mutex // guards the poller state between Poll, ResetPosition, and CatchingUp
 }
 
 // CreatePoller returns a new instance of a Poller
@@ -30,6 +42,9 @@ func CreatePoller(ms MessageStore, worker SubscriptionWorker, config *Subscriber
 
 //Poll Handles a single tick of the handlers firing
 func (pol *poller) Poll(ctx context.Context) error {
+	pol.mu.Lock()
+	defer pol.mu.Unlock()
+
 	worker := pol.worker
 	// use the position of the worker if the poller position is still its default value or an invalid <0 value
 	if pol.position < 0 {
@@ -38,12 +53,21 @@ func (pol *poller) Poll(ctx context.Context) error {
 			return err
 		}
 		pol.position = pos
+		pol.savedPosition = pos
+		pol.savedAt = time.Now()
+	}
+
+	batchSize := pol.config.batchSize
+	if pol.catchingUp() {
+		batchSize = pol.config.catchUpBatchSize
 	}
 
-	msgs, err := worker.GetMessages(ctx, pol.position)
+	pol.lastReceived, pol.lastRequested = 0, batchSize
+	msgs, read, err := worker.GetMessages(ctx, pol.position, batchSize)
 	if err != nil {
 		return err
 	}
+	pol.lastReceived = read.Count // messages that were skipped were still read
 
 	numberOfMsgsHandled, posOfLastHandled, err := worker.ProcessMessages(ctx, msgs) // ProcessMessages logs errors but does not return them as the process should continue despite an error occuring
 	if err != nil {
@@ -54,15 +78,95 @@ func (pol *poller) Poll(ctx context.Context) error {
 	}
 	if numberOfMsgsHandled > 0 {
 		pol.position = posOfLastHandled + 1 // update poller with the new position
+	} else if read.Count > 0 {
+		pol.position = pol.lastPositionRead(read) + 1 // nothing in the batch is handled, so don't read it again
 	}
 	pol.numberOfMsgsHandled += numberOfMsgsHandled
 
-	if pol.numberOfMsgsHandled >= pol.config.updateInterval {
+	if read.Count < batchSize {
+		pol.caughtUp = true
+		if !pol.notifiedCaughtUp && pol.config.onCaughtUp != nil {
+			pol.config.onCaughtUp()
+		}
+		pol.notifiedCaughtUp = true
+	}
+
+	updateInterval := pol.config.updateInterval
+	if pol.catchingUp() {
+		updateInterval = pol.config.catchUpUpdateInterval
+	}
+
+	if pol.numberOfMsgsHandled >= updateInterval || pol.checkpointDue(read.Count == 0) {
 		if err = worker.SetPosition(ctx, pol.position); err != nil {
 			return err
 		}
 		pol.numberOfMsgsHandled = 0
+		pol.savedPosition = pol.position
+		pol.savedAt = time.Now()
+	}
+
+	return nil
+}
+
+// lastPositionRead returns where the last envelope of a batch was read from; its version for stream subscriptions, and its global position otherwise
+func (pol *poller) lastPositionRead(read ReadBatch) int64 {
+	if pol.config.stream {
+		return read.LastVersion
+	}
+
+	return read.LastPosition
+}
+
+// checkpointDue reports whether the position has moved since it was last saved and either the poll was idle or the checkpoint interval has passed
+func (pol *poller) checkpointDue(idle bool) bool {
+	if pol.position == pol.savedPosition {
+		return false
 	}
+	if idle {
+		return true
+	}
+
+	return pol.config.checkpointInterval > 0 && time.Since(pol.savedAt) >= pol.config.checkpointInterval
+}
+
+// ResetPosition writes a new position for the subscriber and replaces the cached position; waits for any in-flight Poll to finish first
+func (pol *poller) ResetPosition(ctx context.Context, position int64) error {
+	if position < 0 {
+		return ErrInvalidSubscriberPosition
+	}
+
+	pol.mu.Lock()
+	defer pol.mu.Unlock()
+
+	if err := pol.worker.SetPosition(ctx, position); err != nil {
+		return err
+	}
+	pol.position = position
+	pol.numberOfMsgsHandled = 0
+	pol.savedPosition = position
+	pol.savedAt = time.Now()
+	pol.caughtUp = false // a rewound subscriber may be far behind again
 
 	return nil
 }
+
+// CatchingUp reports whether catch-up mode is enabled and the poller has not yet reached the head
+func (pol *poller) CatchingUp() bool {
+	pol.mu.Lock()
+	defer pol.mu.Unlock()
+
+	return pol.catchingUp()
+}
+
+// catchingUp is CatchingUp for callers already holding the lock
+func (pol *poller) catchingUp() bool {
+	return pol.config.catchUp && !pol.caughtUp
+}
+
+// LastBatch reports how many messages the last poll received and how many it asked for
+func (pol *poller) LastBatch() (received int, requested int) {
+	pol.mu.Lock()
+	defer pol.mu.Unlock()
+
+	return pol.lastReceived, pol.lastRequested
+}
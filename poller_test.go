@@ -3,8 +3,10 @@ package gomessagestore_test
 import (
 	"context"
 	"testing"
+	"time"
 
 	. "github.com/blackhatbrigade/gomessagestore"
+	"github.com/blackhatbrigade/gomessagestore/inmem_repository"
 	mock_gomessagestore "github.com/blackhatbrigade/gomessagestore/mocks"
 	mock_repository "github.com/blackhatbrigade/gomessagestore/repository/mocks"
 	"github.com/golang/mock/gomock"
@@ -68,6 +70,18 @@ func TestPoller(t *testing.T) {
 		processMsgsParams:  []processMessagesParams{{eventsToMessageSlice(getLotsOfSampleEvents(3, 100))}},
 		processMsgsReturns: []processMessagesReturns{{2, 1012, nil}},
 		expectedErrors:     []error{nil},
+	}, {
+		name: "It ran with several categories",
+		subOpts: []SubscriberOption{
+			SubscribeToCategories("some cat", "some other cat"),
+		},
+		handlers:           []MessageHandler{},
+		callPollNumTimes:   1,
+		getMsgsParams:      []getMessagesParams{{0}},
+		getMsgsReturns:     []getMessagesReturns{{eventsToMessageSlice(getLotsOfSampleEvents(3, 100)), nil}},
+		processMsgsParams:  []processMessagesParams{{eventsToMessageSlice(getLotsOfSampleEvents(3, 100))}},
+		processMsgsReturns: []processMessagesReturns{{2, 1012, nil}},
+		expectedErrors:     []error{nil},
 	}, {
 		name: "GetPosition Errors are returned",
 		subOpts: []SubscriberOption{
@@ -217,6 +231,34 @@ func TestPoller(t *testing.T) {
 			{nil},
 		},
 		expectedErrors: []error{nil, nil, nil},
+	}, {
+		name: "SetPosition is called with a single position when subscribed to several categories",
+		subOpts: []SubscriberOption{
+			SubscribeToCategory("some cat"),
+			SubscribeToCategory("some other cat"),
+			UpdatePositionEvery(5),
+		},
+		handlers:         []MessageHandler{},
+		callPollNumTimes: 2,
+		getMsgsParams: []getMessagesParams{
+			{0},
+			{1013},
+		},
+		getMsgsReturns: []getMessagesReturns{
+			{eventsToMessageSlice(getLotsOfSampleEvents(3, 100)), nil},
+			{eventsToMessageSlice(getLotsOfSampleEvents(3, 103)), nil},
+		},
+		processMsgsParams: []processMessagesParams{
+			{eventsToMessageSlice(getLotsOfSampleEvents(3, 100))},
+			{eventsToMessageSlice(getLotsOfSampleEvents(3, 103))},
+		},
+		processMsgsReturns: []processMessagesReturns{
+			{3, 1012, nil},
+			{3, 9000, nil},
+		},
+		setPosParams:   []setPositionParams{{9001}},
+		setPosReturns:  []setPositionReturns{{nil}},
+		expectedErrors: []error{nil, nil},
 	}, {
 		name: "SetPosition is called (multiple, multiple times) when the correct amount of messages are processed",
 		subOpts: []SubscriberOption{
@@ -356,8 +398,8 @@ func TestPoller(t *testing.T) {
 			for index, _ := range test.getMsgsParams {
 				thisCall := myWorker.
 					EXPECT().
-					GetMessages(ctx, test.getMsgsParams[index].position).
-					Return(test.getMsgsReturns[index].messages, test.getMsgsReturns[index].err)
+					GetMessages(ctx, test.getMsgsParams[index].position, 1000).
+					Return(test.getMsgsReturns[index].messages, readOf(test.getMsgsReturns[index].messages), test.getMsgsReturns[index].err)
 				if lastCall != nil {
 					thisCall.After(lastCall)
 				}
@@ -421,3 +463,342 @@ func TestPoller(t *testing.T) {
 		})
 	}
 }
+
+func TestPollerResetPosition(t *testing.T) {
+	tests := []struct {
+		name             string
+		position         int64
+		setPosError      error
+		expectedError    error
+		expectedPosition int64
+	}{{
+		name:             "ResetPosition writes the position and the next Poll uses it",
+		position:         50,
+		expectedPosition: 50,
+	}, {
+		name:             "ResetPosition does not change the cached position when the write fails",
+		position:         50,
+		setPosError:      potato,
+		expectedError:    potato,
+		expectedPosition: 1013,
+	}, {
+		name:             "ResetPosition rejects negative positions",
+		position:         -5,
+		expectedError:    ErrInvalidSubscriberPosition,
+		expectedPosition: 1013,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			ctx := context.Background()
+
+			mockRepo := mock_repository.NewMockRepository(ctrl)
+			myWorker := mock_gomessagestore.NewMockSubscriptionWorker(ctrl)
+
+			firstMsgs := eventsToMessageSlice(getLotsOfSampleEvents(3, 100))
+			secondMsgs := eventsToMessageSlice(getLotsOfSampleEvents(3, 103))
+
+			myWorker.
+				EXPECT().
+				GetPosition(ctx).
+				Return(int64(0), nil)
+			first := myWorker.
+				EXPECT().
+				GetMessages(ctx, int64(0), 1000).
+				Return(firstMsgs, readOf(firstMsgs), nil)
+			myWorker.
+				EXPECT().
+				ProcessMessages(ctx, firstMsgs).
+				Return(3, int64(1012), nil)
+			if test.position >= 0 {
+				myWorker.
+					EXPECT().
+					SetPosition(ctx, test.position).
+					Return(test.setPosError)
+			}
+			myWorker.
+				EXPECT().
+				GetMessages(ctx, test.expectedPosition, 1000).
+				Return(secondMsgs, readOf(secondMsgs), nil).
+				After(first)
+			myWorker.
+				EXPECT().
+				ProcessMessages(ctx, secondMsgs).
+				Return(0, int64(0), nil)
+
+			msgStore := NewMessageStoreFromRepository(mockRepo, logrus.New())
+			opts, err := GetSubscriberConfig(SubscribeToCommandStream("some cat"))
+			panicIf(err)
+			myPoller, err := CreatePoller(msgStore, myWorker, opts)
+			panicIf(err)
+
+			panicIf(myPoller.Poll(ctx))
+
+			err = myPoller.ResetPosition(ctx, test.position)
+			if err != test.expectedError {
+				t.Errorf("Failed on ResetPosition()\nWant: %v\nHave: %v\n", test.expectedError, err)
+			}
+
+			panicIf(myPoller.Poll(ctx))
+		})
+	}
+}
+
+func TestPollerCatchUp(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx := context.Background()
+
+	mockRepo := mock_repository.NewMockRepository(ctrl)
+	myWorker := mock_gomessagestore.NewMockSubscriptionWorker(ctrl)
+
+	fullBatch := eventsToMessageSlice(getLotsOfSampleEvents(3, 100))
+	partialBatch := eventsToMessageSlice(getLotsOfSampleEvents(2, 103))
+	emptyBatch := []Message{}
+
+	myWorker.
+		EXPECT().
+		GetPosition(ctx).
+		Return(int64(0), nil)
+	gomock.InOrder(
+		myWorker.EXPECT().GetMessages(ctx, int64(0), 3).Return(fullBatch, readOf(fullBatch), nil),
+		myWorker.EXPECT().ProcessMessages(ctx, fullBatch).Return(3, int64(1012), nil),
+		myWorker.EXPECT().GetMessages(ctx, int64(1013), 3).Return(partialBatch, readOf(partialBatch), nil),
+		myWorker.EXPECT().ProcessMessages(ctx, partialBatch).Return(2, int64(2000), nil),
+		myWorker.EXPECT().SetPosition(ctx, int64(2001)).Return(nil), // live interval applies as soon as the head is reached
+		myWorker.EXPECT().GetMessages(ctx, int64(2001), 1000).Return(emptyBatch, readOf(emptyBatch), nil),
+		myWorker.EXPECT().ProcessMessages(ctx, emptyBatch).Return(0, int64(0), nil),
+	)
+
+	caughtUpCalled := 0
+	msgStore := NewMessageStoreFromRepository(mockRepo, logrus.New())
+	opts, err := GetSubscriberConfig(
+		SubscribeToCategory("some cat"),
+		UpdatePositionEvery(5),
+		CatchUpBatchSize(3),
+		CatchUpUpdatePositionEvery(10),
+		OnCaughtUp(func() {
+			caughtUpCalled++
+		}),
+	)
+	panicIf(err)
+	myPoller, err := CreatePoller(msgStore, myWorker, opts)
+	panicIf(err)
+
+	expectedCatchingUp := []bool{true, false, false}
+	expectedCaughtUpCalls := []int{0, 1, 1}
+	for c := 0; c < 3; c++ {
+		if err := myPoller.Poll(ctx); err != nil {
+			t.Errorf("Failed on Poll() %d: %s", c, err)
+		}
+		if myPoller.CatchingUp() != expectedCatchingUp[c] {
+			t.Errorf("Wrong catch-up state after Poll() %d\nWant: %t\nHave: %t\n", c, expectedCatchingUp[c], myPoller.CatchingUp())
+		}
+		if caughtUpCalled != expectedCaughtUpCalls[c] {
+			t.Errorf("Wrong number of OnCaughtUp calls after Poll() %d\nWant: %d\nHave: %d\n", c, expectedCaughtUpCalls[c], caughtUpCalled)
+		}
+	}
+}
+
+func TestPollerMovesPastBatchesWithNothingHandled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx := context.Background()
+
+	mockRepo := mock_repository.NewMockRepository(ctrl)
+	myWorker := mock_gomessagestore.NewMockSubscriptionWorker(ctrl)
+
+	unhandledBatch := eventsToMessageSlice(getLotsOfSampleEvents(3, 100)) // global positions 600 to 602
+	emptyBatch := []Message{}
+
+	myWorker.
+		EXPECT().
+		GetPosition(ctx).
+		Return(int64(0), nil)
+	gomock.InOrder(
+		myWorker.EXPECT().GetMessages(ctx, int64(0), 3).Return(unhandledBatch, readOf(unhandledBatch), nil),
+		myWorker.EXPECT().ProcessMessages(ctx, unhandledBatch).Return(0, int64(0), nil),
+		myWorker.EXPECT().GetMessages(ctx, int64(603), 3).Return(emptyBatch, readOf(emptyBatch), nil),
+		myWorker.EXPECT().ProcessMessages(ctx, emptyBatch).Return(0, int64(0), nil),
+		myWorker.EXPECT().SetPosition(ctx, int64(603)).Return(nil),
+	)
+
+	msgStore := NewMessageStoreFromRepository(mockRepo, logrus.New())
+	opts, err := GetSubscriberConfig(
+		SubscribeToCategory("some cat"),
+		CatchUpBatchSize(3),
+	)
+	panicIf(err)
+	myPoller, err := CreatePoller(msgStore, myWorker, opts)
+	panicIf(err)
+
+	for c := 0; c < 2; c++ {
+		if err := myPoller.Poll(ctx); err != nil {
+			t.Errorf("Failed on Poll() %d: %s", c, err)
+		}
+	}
+}
+
+func TestPollerToAllStreamsStopsSavingItsPositionWhenIdle(t *testing.T) {
+	ctx := context.Background()
+	msgStore := NewMessageStoreFromRepository(inmem_repository.NewInMemoryRepository(nil), logrus.New())
+	writeDeposits(t, msgStore, 10)
+
+	opts, err := GetSubscriberConfig(SubscribeToAllStreams())
+	panicIf(err)
+	myWorker, err := CreateWorker(msgStore, "someid", []MessageHandler{&msgHandler{class: "Deposited"}}, opts)
+	panicIf(err)
+	myPoller, err := CreatePoller(msgStore, myWorker, opts)
+	panicIf(err)
+
+	for c := 0; c < 10; c++ {
+		if err := myPoller.Poll(ctx); err != nil {
+			t.Errorf("Failed on Poll() %d: %s", c, err)
+		}
+	}
+
+	positions, err := msgStore.Get(ctx, PositionStream("someid"))
+	if err != nil {
+		t.Fatalf("Failed reading positions: %s", err)
+	}
+	if len(positions) != 1 { // the position it saves mustn't be read back as a message that moves it again
+		t.Errorf("Wrong number of positions saved\nWant: 1\nHave: %d\n", len(positions))
+	}
+}
+
+func TestPollerCountsEnvelopesThatWereSkipped(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx := context.Background()
+
+	mockRepo := mock_repository.NewMockRepository(ctrl)
+	myWorker := mock_gomessagestore.NewMockSubscriptionWorker(ctrl)
+
+	readable := eventsToMessageSlice(getLotsOfSampleEvents(1, 100)) // global position 600, while 601 and 602 can't be read
+	fullRead := ReadBatch{Count: 3, LastVersion: 106, LastPosition: 602}
+	nextBatch := eventsToMessageSlice(getLotsOfSampleEvents(3, 103))
+
+	myWorker.
+		EXPECT().
+		GetPosition(ctx).
+		Return(int64(0), nil)
+	gomock.InOrder(
+		myWorker.EXPECT().GetMessages(ctx, int64(0), 3).Return(readable, fullRead, nil),
+		myWorker.EXPECT().ProcessMessages(ctx, readable).Return(0, int64(0), nil),
+		myWorker.EXPECT().GetMessages(ctx, int64(603), 3).Return(nextBatch, readOf(nextBatch), nil), // still catching up, past the skipped envelopes
+		myWorker.EXPECT().ProcessMessages(ctx, nextBatch).Return(0, int64(0), nil),
+	)
+
+	msgStore := NewMessageStoreFromRepository(mockRepo, logrus.New())
+	opts, err := GetSubscriberConfig(
+		SubscribeToCategory("some cat"),
+		CatchUpBatchSize(3),
+	)
+	panicIf(err)
+	myPoller, err := CreatePoller(msgStore, myWorker, opts)
+	panicIf(err)
+
+	for c := 0; c < 2; c++ {
+		if err := myPoller.Poll(ctx); err != nil {
+			t.Errorf("Failed on Poll() %d: %s", c, err)
+		}
+		if !myPoller.CatchingUp() {
+			t.Errorf("A full batch with skipped envelopes ended catch-up after Poll() %d", c)
+		}
+	}
+}
+
+func TestPollerCheckpoints(t *testing.T) {
+	firstMsgs := eventsToMessageSlice(getLotsOfSampleEvents(3, 100))
+	secondMsgs := eventsToMessageSlice(getLotsOfSampleEvents(3, 103))
+	emptyBatch := []Message{}
+
+	tests := []struct {
+		name           string
+		subOpts        []SubscriberOption
+		sleepAfterPoll time.Duration
+		batches        [][]Message
+		processReturns []processMessagesReturns
+		setPosParams   []setPositionParams
+	}{{
+		name: "Position is saved on an idle poll when it has moved since the last save, but only once",
+		subOpts: []SubscriberOption{
+			SubscribeToCategory("some cat"),
+		},
+		batches:        [][]Message{firstMsgs, emptyBatch, emptyBatch},
+		processReturns: []processMessagesReturns{{3, 1012, nil}, {0, 0, nil}, {0, 0, nil}},
+		setPosParams:   []setPositionParams{{1013}},
+	}, {
+		name: "Position is not saved on an idle poll when it has not moved",
+		subOpts: []SubscriberOption{
+			SubscribeToCategory("some cat"),
+		},
+		batches:        [][]Message{emptyBatch, emptyBatch},
+		processReturns: []processMessagesReturns{{0, 0, nil}, {0, 0, nil}},
+	}, {
+		name: "Position is saved once the checkpoint interval has passed, before the message count is reached",
+		subOpts: []SubscriberOption{
+			SubscribeToCategory("some cat"),
+			CheckpointEvery(20 * time.Millisecond),
+		},
+		sleepAfterPoll: 30 * time.Millisecond,
+		batches:        [][]Message{firstMsgs, secondMsgs},
+		processReturns: []processMessagesReturns{{3, 1012, nil}, {3, 2000, nil}},
+		setPosParams:   []setPositionParams{{2001}},
+	}, {
+		name: "Position is not saved before the checkpoint interval has passed",
+		subOpts: []SubscriberOption{
+			SubscribeToCategory("some cat"),
+			CheckpointEvery(time.Hour),
+		},
+		batches:        [][]Message{firstMsgs, secondMsgs},
+		processReturns: []processMessagesReturns{{3, 1012, nil}, {3, 2000, nil}},
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			ctx := context.Background()
+
+			mockRepo := mock_repository.NewMockRepository(ctrl)
+			myWorker := mock_gomessagestore.NewMockSubscriptionWorker(ctrl)
+
+			myWorker.
+				EXPECT().
+				GetPosition(ctx).
+				Return(int64(0), nil)
+			for index, batch := range test.batches {
+				myWorker.
+					EXPECT().
+					GetMessages(ctx, gomock.Any(), 1000).
+					Return(batch, readOf(batch), nil)
+				myWorker.
+					EXPECT().
+					ProcessMessages(ctx, batch).
+					Return(test.processReturns[index].msgsHandled, test.processReturns[index].lastPos, test.processReturns[index].err)
+			}
+			for _, setPos := range test.setPosParams {
+				myWorker.
+					EXPECT().
+					SetPosition(ctx, setPos.position).
+					Return(nil)
+			}
+
+			msgStore := NewMessageStoreFromRepository(mockRepo, logrus.New())
+			opts, err := GetSubscriberConfig(test.subOpts...)
+			panicIf(err)
+			myPoller, err := CreatePoller(msgStore, myWorker, opts)
+			panicIf(err)
+
+			for range test.batches {
+				if err := myPoller.Poll(ctx); err != nil {
+					t.Errorf("Failed on Poll(): %s", err)
+				}
+				time.Sleep(test.sleepAfterPoll)
+			}
+		})
+	}
+}
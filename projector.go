@@ -4,6 +4,7 @@ import (
 	"context"
 	"reflect"
 
+	"github.com/blackhatbrigade/gomessagestore/streamname"
 	"github.com/blackhatbrigade/gomessagestore/uuid"
 )
 
@@ -32,6 +33,22 @@ func (ms *msgStore) CreateProjector(opts ...ProjectorOption) (Projector, error)
 		return nil, ErrDefaultStateNotSet
 	}
 
+	if projector.snapshotInterval < 0 {
+		return nil, ErrInvalidSnapshotInterval
+	}
+
+	if projector.snapshotInterval > 0 && projector.snapshotStore == nil {
+		projector.snapshotStore = &streamSnapshotStore{ms: ms}
+	}
+
+	if projector.cacheSize < 0 {
+		return nil, ErrInvalidCacheSize
+	}
+
+	if projector.cacheSize > 0 {
+		projector.cache = newProjectionCache(projector.cacheSize, projector.cacheCopier)
+	}
+
 	return projector, nil
 }
 
@@ -41,32 +58,135 @@ type ProjectorOption func(proj *projector)
 // Projector A base level interface that defines the projection functionality of gomessagestore.
 type Projector interface {
 	Run(ctx context.Context, category string, entityID uuid.UUID) (interface{}, error)
+	RunStreamID(ctx context.Context, category string, streamID string) (interface{}, error)
+	RunWithVersion(ctx context.Context, category string, entityID uuid.UUID) (Projection, error)
+	RunStreamIDWithVersion(ctx context.Context, category string, streamID string) (Projection, error)
+	RunUntil(ctx context.Context, category string, entityID uuid.UUID, until RunBoundary) (Projection, error)
+	RunStreamIDUntil(ctx context.Context, category string, streamID string, until RunBoundary) (Projection, error)
 	Step(msg Message, previousState interface{}) (interface{}, bool)
 }
 
+// Projection is the state a projector derived, along with how far into the stream it got
+// Version and GlobalPosition are those of the last message read, or -1 for an empty stream, so Version can be passed straight to AtPosition.
+type Projection struct {
+	State          interface{}
+	Version        int64
+	GlobalPosition int64
+}
+
 // projector The base projector struct.
 type projector struct {
-	ms           MessageStore
-	reducers     []MessageReducer
-	defaultState interface{}
+	ms               MessageStore
+	reducers         []MessageReducer
+	defaultState     interface{}
+	snapshotInterval int // when set, a snapshot is saved after replaying at least this many messages
+	snapshotStore    SnapshotStore
+	snapshotVersion  string
+	cacheSize        int // when set, the projections of this many streams are cached
+	cacheCopier      func(state interface{}) interface{}
+	cache            *projectionCache
 }
 
 // Run calls getMessages on the projector and runs each messagae through a matching reducer to derive the state, and returns the state after all messages are processed
 func (proj *projector) Run(ctx context.Context, category string, entityID uuid.UUID) (interface{}, error) {
-	msgs, err := proj.getMessages(ctx, category, entityID)
+	return proj.RunStreamID(ctx, category, entityID.String())
+}
 
+// RunStreamID is Run for an entity whose ID is not a UUID, such as order-ORD123
+func (proj *projector) RunStreamID(ctx context.Context, category string, streamID string) (interface{}, error) {
+	projection, err := proj.RunStreamIDWithVersion(ctx, category, streamID)
 	if err != nil {
 		return nil, err
 	}
 
-	state := proj.defaultState
+	return projection.State, nil
+}
+
+// RunWithVersion is Run that also returns the version and global position the state was projected to
+func (proj *projector) RunWithVersion(ctx context.Context, category string, entityID uuid.UUID) (Projection, error) {
+	return proj.RunStreamIDWithVersion(ctx, category, entityID.String())
+}
+
+// RunStreamIDWithVersion is RunWithVersion for an entity whose ID is not a UUID, such as order-ORD123
+func (proj *projector) RunStreamIDWithVersion(ctx context.Context, category string, streamID string) (Projection, error) {
+	return proj.run(ctx, category, streamID, nil)
+}
+
+// RunUntil is RunWithVersion that stops applying messages at a boundary, giving the state as it was at a version, global position or time
+// Cached projections and snapshots are used when they are from before the boundary; RunUntil doesn't cache its projections or save snapshots.
+func (proj *projector) RunUntil(ctx context.Context, category string, entityID uuid.UUID, until RunBoundary) (Projection, error) {
+	return proj.RunStreamIDUntil(ctx, category, entityID.String(), until)
+}
+
+// RunStreamIDUntil is RunUntil for an entity whose ID is not a UUID, such as order-ORD123
+func (proj *projector) RunStreamIDUntil(ctx context.Context, category string, streamID string, until RunBoundary) (Projection, error) {
+	if !until.isSet() {
+		return Projection{}, ErrMissingRunBoundary
+	}
+
+	return proj.run(ctx, category, streamID, &until)
+}
+
+// run projects the stream, up to until when it is set
+func (proj *projector) run(ctx context.Context, category string, streamID string, until *RunBoundary) (Projection, error) {
+	projection := Projection{
+		State:          proj.defaultState,
+		Version:        -1,
+		GlobalPosition: -1,
+	}
+
+	streamName := streamname.Compose(category, streamID)
+	startsFrom := func(start Projection) bool {
+		return until == nil || until.startsFrom(start)
+	}
+
+	started := false
+	if proj.cache != nil {
+		if fromCache, cached := proj.cache.get(streamName); cached && startsFrom(fromCache) {
+			projection = fromCache
+			started = true
+		}
+	}
+	if !started && proj.snapshotInterval > 0 && (until == nil || until.time.IsZero()) {
+		snapshot, found, err := proj.loadSnapshot(ctx, streamName)
+		if err != nil {
+			return Projection{}, err
+		}
+		if found && startsFrom(snapshot) {
+			projection = snapshot
+		}
+	}
+
+	msgs, err := proj.getMessages(ctx, category, streamID, projection.Version+1, until)
+
+	if err != nil {
+		return Projection{}, err
+	}
+
 	for _, message := range msgs {
-		if newState, ok := proj.Step(message, state); ok {
-			state = newState
+		if until != nil && !until.includes(message) {
+			break
 		}
+		if newState, ok := proj.Step(message, projection.State); ok {
+			projection.State = newState
+		}
+		projection.Version = message.Version()
+		projection.GlobalPosition = message.Position()
+	}
+
+	if until != nil { // the cache and snapshots hold the latest projection
+		return projection, nil
+	}
+
+	if proj.snapshotInterval > 0 && len(msgs) >= proj.snapshotInterval {
+		proj.saveSnapshot(ctx, streamName, projection)
+	}
+
+	if proj.cache != nil {
+		proj.cache.put(streamName, projection)
 	}
 
-	return state, nil
+	return projection, nil
 }
 
 // Step is ran for each message, iterating the state for the reducer mapped to that message
@@ -101,26 +221,38 @@ func DefaultState(defaultState interface{}) ProjectorOption {
 	}
 }
 
-// getMessages retrieves messages from the message store
-func (proj *projector) getMessages(ctx context.Context, category string, entityID uuid.UUID) ([]Message, error) {
+// getMessages retrieves messages from the message store, starting at sinceVersion
+// With until set, it stops paging once it reads past the boundary, and leaves boundaries in time to the message store.
+// A message that can't be decoded or upcast fails the run, as a state projected without it would be wrong.
+func (proj *projector) getMessages(ctx context.Context, category string, streamID string, sinceVersion int64, until *RunBoundary) ([]Message, error) {
 	batchsize := 1000
-	msgs, err := proj.ms.Get(ctx,
-		EventStream(category, entityID),
-		BatchSize(batchsize),
-	)
+	var read ReadBatch // pages by the envelopes read, as there can be fewer messages than envelopes
+	get := func(sinceVersion int64) ([]Message, error) {
+		opts := []GetOption{
+			EventStreamID(category, streamID),
+			BatchSize(batchsize),
+			Strict(),
+			ReportRead(&read),
+		}
+		if sinceVersion > 0 {
+			opts = append(opts, SinceVersion(sinceVersion))
+		}
+		if until != nil && !until.time.IsZero() {
+			opts = append(opts, Until(until.time))
+		}
+		return proj.ms.Get(ctx, opts...)
+	}
+
+	msgs, err := get(sinceVersion)
 	if err != nil {
 		return nil, err
 	}
 
-	if len(msgs) == batchsize {
+	if read.Count == batchsize {
 		allMsgs := make([]Message, 0, batchsize*2)
 		allMsgs = append(allMsgs, msgs...)
-		for len(msgs) == batchsize {
-			msgs, err = proj.ms.Get(ctx,
-				EventStream(category, entityID),
-				BatchSize(batchsize),
-				SinceVersion(msgs[batchsize-1].Version()+1), // Since grabs an inclusive list, so grab 1 after the latest version
-			)
+		for read.Count == batchsize && (until == nil || len(msgs) == 0 || until.includes(msgs[len(msgs)-1])) {
+			msgs, err = get(read.LastVersion + 1) // Since grabs an inclusive list, so grab 1 after the latest version
 			if err != nil {
 				return nil, err
 			}
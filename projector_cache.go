@@ -0,0 +1,185 @@
+package gomessagestore
+
+import (
+	"container/list"
+	"reflect"
+	"sync"
+)
+
+// WithCache has the projector keep the projections of the size most recently run streams in memory
+// A run for a cached stream only reads the messages written since, starting from a copy of the cached state; 0 turns the cache off.
+// States are copied going into and out of the cache, so callers and reducers can change the states they get; see CacheCopier.
+func WithCache(size int) ProjectorOption {
+	return func(proj *projector) {
+		proj.cacheSize = size
+	}
+}
+
+// CacheCopier replaces how the cache copies states
+// The default deep copies maps, slices, pointers and exported struct fields, keeping what they share and any cycles, but can only share unexported fields; states with unexported maps, slices or pointers need a copier of their own.
+func CacheCopier(copier func(state interface{}) interface{}) ProjectorOption {
+	return func(proj *projector) {
+		proj.cacheCopier = copier
+	}
+}
+
+// projectionCache is a least recently used cache of projections, keyed by stream name
+type projectionCache struct {
+	mutex   sync.Mutex
+	size    int
+	copier  func(state interface{}) interface{}
+	order   *list.List // most recently used first
+	entries map[string]*list.Element
+}
+
+// cacheEntry is what the elements of projectionCache.order hold
+type cacheEntry struct {
+	streamName string
+	projection Projection
+}
+
+func newProjectionCache(size int, copier func(state interface{}) interface{}) *projectionCache {
+	if copier == nil {
+		copier = deepCopy
+	}
+
+	return &projectionCache{
+		size:    size,
+		copier:  copier,
+		order:   list.New(),
+		entries: make(map[string]*list.Element),
+	}
+}
+
+// get returns a copy of the cached projection of the stream
+func (cache *projectionCache) get(streamName string) (Projection, bool) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	element, ok := cache.entries[streamName]
+	if !ok {
+		return Projection{}, false
+	}
+	cache.order.MoveToFront(element)
+
+	projection := element.Value.(*cacheEntry).projection
+	projection.State = cache.copier(projection.State)
+	return projection, true
+}
+
+// put caches a copy of the projection of the stream, dropping the least recently used stream when the cache is full
+func (cache *projectionCache) put(streamName string, projection Projection) {
+	projection.State = cache.copier(projection.State)
+
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	if element, ok := cache.entries[streamName]; ok {
+		entry := element.Value.(*cacheEntry)
+		if entry.projection.Version <= projection.Version { // a slower run may finish after a faster one that read further
+			entry.projection = projection
+		}
+		cache.order.MoveToFront(element)
+		return
+	}
+
+	cache.entries[streamName] = cache.order.PushFront(&cacheEntry{streamName, projection})
+	if cache.order.Len() > cache.size {
+		oldest := cache.order.Back()
+		cache.order.Remove(oldest)
+		delete(cache.entries, oldest.Value.(*cacheEntry).streamName)
+	}
+}
+
+// deepCopy copies a state so that changing the copy can't change the original
+// Pointers, maps and slices shared within the state are shared within the copy too, which also copies states with cycles.
+func deepCopy(state interface{}) interface{} {
+	if state == nil {
+		return nil
+	}
+
+	copier := &deepCopier{copies: make(map[copiedRef]reflect.Value)}
+	return copier.copyValue(reflect.ValueOf(state)).Interface()
+}
+
+// copiedRef identifies a pointer, map or slice that has already been copied
+// The type is part of it, as a pointer to a struct and a pointer to its first field share an address.
+type copiedRef struct {
+	address uintptr
+	typ     reflect.Type
+	length  int
+}
+
+// deepCopier remembers the copies made during one deepCopy
+type deepCopier struct {
+	copies map[copiedRef]reflect.Value
+}
+
+func (copier *deepCopier) copyValue(value reflect.Value) reflect.Value {
+	switch value.Kind() {
+	case reflect.Ptr:
+		if value.IsNil() {
+			return value
+		}
+		ref := copiedRef{address: value.Pointer(), typ: value.Type()}
+		if copied, ok := copier.copies[ref]; ok {
+			return copied
+		}
+		copied := reflect.New(value.Elem().Type())
+		copier.copies[ref] = copied // before copying what it points to, which may point back to it
+		copied.Elem().Set(copier.copyValue(value.Elem()))
+		return copied
+	case reflect.Interface:
+		if value.IsNil() {
+			return value
+		}
+		copied := reflect.New(value.Type()).Elem()
+		copied.Set(copier.copyValue(value.Elem()))
+		return copied
+	case reflect.Map:
+		if value.IsNil() {
+			return value
+		}
+		ref := copiedRef{address: value.Pointer(), typ: value.Type()}
+		if copied, ok := copier.copies[ref]; ok {
+			return copied
+		}
+		copied := reflect.MakeMapWithSize(value.Type(), value.Len())
+		copier.copies[ref] = copied
+		for _, key := range value.MapKeys() {
+			copied.SetMapIndex(key, copier.copyValue(value.MapIndex(key)))
+		}
+		return copied
+	case reflect.Slice:
+		if value.IsNil() {
+			return value
+		}
+		ref := copiedRef{address: value.Pointer(), typ: value.Type(), length: value.Len()}
+		if copied, ok := copier.copies[ref]; ok {
+			return copied
+		}
+		copied := reflect.MakeSlice(value.Type(), value.Len(), value.Len())
+		copier.copies[ref] = copied
+		for i := 0; i < value.Len(); i++ {
+			copied.Index(i).Set(copier.copyValue(value.Index(i)))
+		}
+		return copied
+	case reflect.Array:
+		copied := reflect.New(value.Type()).Elem()
+		for i := 0; i < value.Len(); i++ {
+			copied.Index(i).Set(copier.copyValue(value.Index(i)))
+		}
+		return copied
+	case reflect.Struct:
+		copied := reflect.New(value.Type()).Elem()
+		copied.Set(value) // unexported fields can only be shared
+		for i := 0; i < value.NumField(); i++ {
+			if copied.Field(i).CanSet() {
+				copied.Field(i).Set(copier.copyValue(value.Field(i)))
+			}
+		}
+		return copied
+	}
+
+	return value
+}
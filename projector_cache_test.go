@@ -0,0 +1,263 @@
+package gomessagestore_test
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/blackhatbrigade/gomessagestore"
+	"github.com/blackhatbrigade/gomessagestore/repository"
+	mock_repository "github.com/blackhatbrigade/gomessagestore/repository/mocks"
+	"github.com/blackhatbrigade/gomessagestore/uuid"
+	"github.com/golang/mock/gomock"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+type ledger struct {
+	Amounts []float64
+	Notes   map[string]interface{}
+	Last    *float64
+}
+
+func getLedgerProjectorOptions(opts ...ProjectorOption) []ProjectorOption {
+	return append([]ProjectorOption{
+		DefaultState(ledger{}),
+		WithReducerFunc("Deposited", func(msg Message, previousState interface{}) interface{} {
+			state := previousState.(ledger)
+			amount := msg.(*Event).Data["amount"].(float64)
+			state.Amounts = append(state.Amounts, amount)
+			if state.Notes == nil {
+				state.Notes = make(map[string]interface{})
+			}
+			state.Notes["deposits"] = len(state.Amounts)
+			state.Last = &amount
+			return state
+		}),
+	}, opts...)
+}
+
+func TestProjectorCacheReadsOnlyNewMessages(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockRepository(ctrl)
+	ctx := context.Background()
+	streamName := "account-" + uuid8.String()
+
+	gomock.InOrder(
+		mockRepo.
+			EXPECT().
+			GetAllMessagesInStream(ctx, streamName, 1000).
+			Return([]*repository.MessageEnvelope{getSampleDeposit(0, 10), getSampleDeposit(1, 5)}, nil),
+		mockRepo.
+			EXPECT().
+			GetAllMessagesInStreamSince(ctx, streamName, int64(2), 1000).
+			Return([]*repository.MessageEnvelope{getSampleDeposit(2, 1)}, nil),
+		mockRepo.
+			EXPECT().
+			GetAllMessagesInStreamSince(ctx, streamName, int64(3), 1000).
+			Return(nil, nil),
+	)
+
+	msgStore := NewMessageStoreFromRepository(mockRepo, logrus.New())
+	projector, err := msgStore.CreateProjector(getBalanceProjectorOptions(WithCache(10))...)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	var states []interface{}
+	for i := 0; i < 3; i++ {
+		projection, err := projector.RunWithVersion(ctx, "account", uuid8)
+		assert.NoError(t, err)
+		states = append(states, projection.State)
+	}
+
+	assert.Equal(t, []interface{}{float64(15), float64(16), float64(16)}, states)
+}
+
+func TestProjectorCacheIsSafeFromChangesToStates(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockRepository(ctrl)
+	ctx := context.Background()
+	streamName := "account-" + uuid8.String()
+
+	gomock.InOrder(
+		mockRepo.
+			EXPECT().
+			GetAllMessagesInStream(ctx, streamName, 1000).
+			Return([]*repository.MessageEnvelope{getSampleDeposit(0, 10), getSampleDeposit(1, 5)}, nil),
+		mockRepo.
+			EXPECT().
+			GetAllMessagesInStreamSince(ctx, streamName, int64(2), 1000).
+			Return([]*repository.MessageEnvelope{getSampleDeposit(2, 1)}, nil),
+		mockRepo.
+			EXPECT().
+			GetAllMessagesInStreamSince(ctx, streamName, int64(3), 1000).
+			Return(nil, nil),
+	)
+
+	msgStore := NewMessageStoreFromRepository(mockRepo, logrus.New())
+	projector, err := msgStore.CreateProjector(getLedgerProjectorOptions(WithCache(10))...)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	scribble := func(state interface{}) {
+		scribbled := state.(ledger)
+		scribbled.Amounts[0] = -1
+		scribbled.Notes["scribbled"] = true
+		*scribbled.Last = -1
+	}
+
+	first, err := projector.Run(ctx, "account", uuid8)
+	assert.NoError(t, err)
+	scribble(first)
+
+	// the reducers change the state they're given on this run, which must not reach the cache either
+	second, err := projector.Run(ctx, "account", uuid8)
+	assert.NoError(t, err)
+	scribble(second)
+
+	third, err := projector.Run(ctx, "account", uuid8)
+	assert.NoError(t, err)
+
+	last := float64(1)
+	assert.Equal(t, ledger{
+		Amounts: []float64{10, 5, 1},
+		Notes:   map[string]interface{}{"deposits": 3},
+		Last:    &last,
+	}, third)
+}
+
+type link struct {
+	Amount float64
+	Next   *link
+}
+
+type ring struct {
+	Head *link
+}
+
+func TestProjectorCacheCopiesStatesWithCycles(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockRepository(ctrl)
+	ctx := context.Background()
+
+	mockRepo.
+		EXPECT().
+		GetAllMessagesInStream(ctx, gomock.Any(), 1000).
+		Return([]*repository.MessageEnvelope{getSampleDeposit(0, 10), getSampleDeposit(1, 5)}, nil)
+	mockRepo.
+		EXPECT().
+		GetAllMessagesInStreamSince(ctx, gomock.Any(), int64(2), 1000)
+
+	msgStore := NewMessageStoreFromRepository(mockRepo, logrus.New())
+	projector, err := msgStore.CreateProjector(
+		DefaultState(ring{}),
+		WithReducerFunc("Deposited", func(msg Message, previousState interface{}) interface{} {
+			state := previousState.(ring)
+			added := &link{Amount: msg.(*Event).Data["amount"].(float64)}
+			if state.Head == nil {
+				added.Next = added
+			} else {
+				added.Next = state.Head.Next
+				state.Head.Next = added
+			}
+			state.Head = added
+			return state
+		}),
+		WithCache(1),
+	)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	first, err := projector.Run(ctx, "account", uuid8)
+	assert.NoError(t, err)
+	second, err := projector.Run(ctx, "account", uuid8)
+	assert.NoError(t, err)
+
+	head := second.(ring).Head
+	if !assert.NotNil(t, head) {
+		return
+	}
+	assert.False(t, head == first.(ring).Head, "the cached state is a copy")
+	assert.Equal(t, float64(5), head.Amount)
+	assert.Equal(t, float64(10), head.Next.Amount)
+	assert.True(t, head.Next.Next == head, "the copy keeps the cycle")
+}
+
+func TestProjectorCacheDropsTheLeastRecentlyUsedStream(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockRepository(ctrl)
+	ctx := context.Background()
+	firstStream := "account-" + uuid8.String()
+	secondStream := "account-" + uuid9.String()
+
+	gomock.InOrder(
+		mockRepo.EXPECT().GetAllMessagesInStream(ctx, firstStream, 1000),
+		mockRepo.EXPECT().GetAllMessagesInStream(ctx, secondStream, 1000),
+		mockRepo.EXPECT().GetAllMessagesInStream(ctx, firstStream, 1000),
+	)
+
+	msgStore := NewMessageStoreFromRepository(mockRepo, logrus.New())
+	projector, err := msgStore.CreateProjector(getBalanceProjectorOptions(WithCache(1))...)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	for _, entityID := range []uuid.UUID{uuid8, uuid9, uuid8} {
+		_, err := projector.Run(ctx, "account", entityID)
+		assert.NoError(t, err)
+	}
+}
+
+func TestProjectorCacheUsesCacheCopier(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockRepository(ctrl)
+	ctx := context.Background()
+
+	mockRepo.
+		EXPECT().
+		GetAllMessagesInStream(ctx, gomock.Any(), 1000).
+		Return([]*repository.MessageEnvelope{getSampleDeposit(0, 10)}, nil)
+	mockRepo.
+		EXPECT().
+		GetAllMessagesInStreamSince(ctx, gomock.Any(), int64(1), 1000)
+
+	copies := 0
+	copier := func(state interface{}) interface{} {
+		copies++
+		return state
+	}
+
+	msgStore := NewMessageStoreFromRepository(mockRepo, logrus.New())
+	projector, err := msgStore.CreateProjector(getBalanceProjectorOptions(WithCache(1), CacheCopier(copier))...)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	for i := 0; i < 2; i++ {
+		_, err := projector.Run(ctx, "account", uuid8)
+		assert.NoError(t, err)
+	}
+
+	assert.Equal(t, 3, copies, "states are copied into the cache on each run, and out of it on a hit")
+}
+
+func TestCreateProjectorFailsWithNegativeCacheSize(t *testing.T) {
+	msgStore := NewMessageStoreFromRepository(nil, logrus.New())
+
+	projector, err := msgStore.CreateProjector(getBalanceProjectorOptions(WithCache(-1))...)
+
+	assert.Equal(t, ErrInvalidCacheSize, err)
+	assert.Nil(t, projector)
+}
@@ -5,6 +5,8 @@ import (
 	"testing"
 
 	. "github.com/blackhatbrigade/gomessagestore"
+	"github.com/blackhatbrigade/gomessagestore/inmem_repository"
+	"github.com/blackhatbrigade/gomessagestore/repository"
 	mock_repository "github.com/blackhatbrigade/gomessagestore/repository/mocks"
 	"github.com/golang/mock/gomock"
 	"github.com/sirupsen/logrus"
@@ -18,6 +20,8 @@ import (
   4. TestCreateProjectorFailsIfGivenPointerForDefaultState
   5. TestCreateProjectorFailsIfDefaultStateIsNotSet
   6. TestCreateProjectorFailsWithoutAtLeastOneReducer
+  7. TestProjectorRunsWithVersion
+  8. TestProjectorFailsOnAMessageItCantRead
 */
 
 func TestProjectorAcceptsAReducer(t *testing.T) {
@@ -249,6 +253,88 @@ func TestProjectorPicksUpAfterFullBatch(t *testing.T) {
 	}
 }
 
+func TestProjectorFailsOnAMessageItCantRead(t *testing.T) {
+	var history []repository.MessageEnvelope
+	for _, msgEnv := range getLotsOfSampleEventsAsEnvelopes(1500, 0) {
+		history = append(history, *msgEnv)
+	}
+	history[5].Data = []byte(`{"payload":"not base64"}`) // in the middle of the first batch
+	history[5].Metadata = []byte(`{"gomessagestore.codec":"msgpack"}`)
+
+	ctx := context.Background()
+	myMessageStore := NewMessageStoreFromRepository(inmem_repository.NewInMemoryRepository(history), logrus.New())
+	projectorOpts := []ProjectorOption{
+		DefaultState(mockDataStructure{}),
+		WithReducer(new(mockReducer1)),
+		WithReducer(new(mockReducer2)),
+	}
+
+	myprojector, err := myMessageStore.CreateProjector(projectorOpts...)
+	panicIf(err)
+
+	projection, err := myprojector.RunWithVersion(ctx, "test cat", uuid8)
+
+	if err == nil {
+		t.Errorf("Projected a state without a message it couldn't read, up to version %d", projection.Version)
+	}
+
+	agg, err := myMessageStore.CreateAggregate(projectorOpts)
+	panicIf(err)
+
+	decided := false
+	err = agg.Handle(ctx, "test cat", uuid8, func(ctx context.Context, state interface{}) ([]Message, error) {
+		decided = true
+		return nil, nil
+	})
+
+	if err == nil || err == ErrExpectedVersionFailed {
+		t.Errorf("Failed to get the read error from Handle(), got: %v", err)
+	}
+	if decided {
+		t.Error("Decided on a state without a message that couldn't be read")
+	}
+}
+
+func TestProjectorRunsWithStreamID(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockRepository(ctrl)
+	myMessageStore := NewMessageStoreFromRepository(mockRepo, logrus.New())
+
+	myprojector, err := myMessageStore.CreateProjector(
+		DefaultState(mockDataStructure{}),
+		WithReducer(new(mockReducer1)),
+	)
+	if err != nil {
+		t.Fatalf("Error creating projector: %s", err)
+	}
+
+	mockEventEnvs := getSampleEventsAsEnvelopes()
+	for _, msgEnv := range mockEventEnvs {
+		msgEnv.StreamName = "test cat-ORD123"
+	}
+	ctx := context.Background()
+
+	mockRepo.
+		EXPECT().
+		GetAllMessagesInStream(ctx, "test cat-ORD123", 1000).
+		Return(mockEventEnvs, nil)
+
+	projection, err := myprojector.RunStreamID(ctx, "test cat", "ORD123")
+
+	if err != nil {
+		t.Errorf("An error has occurred with running a projector, err: %s", err)
+	}
+	myStruct, ok := projection.(mockDataStructure)
+	if !ok {
+		t.Fatalf("projection is the wrong type: %T", projection)
+	}
+	if myStruct.MockReducer1CallCount != 1 {
+		t.Errorf("Reducer 1 was called %d times instead of 1", myStruct.MockReducer1CallCount)
+	}
+}
+
 func TestCreateProjectorFailsIfGivenPointerForDefaultState(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -310,3 +396,66 @@ func TestCreateProjectorFailsWithoutAtLeastOneReducer(t *testing.T) {
 		t.Errorf("Expected ErrProjectorNeedsAtLeastOneReducer and got %s\n", err)
 	}
 }
+
+func TestProjectorRunsWithVersion(t *testing.T) {
+	tests := []struct {
+		name                   string
+		msgEnvs                []*repository.MessageEnvelope
+		expectedCallCount      int
+		expectedVersion        int64
+		expectedGlobalPosition int64
+	}{{
+		name:                   "returns the version and position of the last message, even without a reducer for it",
+		msgEnvs:                getSampleEventsAsEnvelopes(),
+		expectedCallCount:      1,
+		expectedVersion:        8,
+		expectedGlobalPosition: 349,
+	}, {
+		name:                   "returns -1 for an empty stream",
+		expectedVersion:        -1,
+		expectedGlobalPosition: -1,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mock_repository.NewMockRepository(ctrl)
+			myMessageStore := NewMessageStoreFromRepository(mockRepo, logrus.New())
+
+			myprojector, err := myMessageStore.CreateProjector(
+				DefaultState(mockDataStructure{}),
+				WithReducer(new(mockReducer2)),
+			)
+			if err != nil {
+				t.Fatalf("Error creating projector: %s", err)
+			}
+
+			ctx := context.Background()
+			mockRepo.
+				EXPECT().
+				GetAllMessagesInStream(ctx, "test cat-"+uuid8.String(), 1000).
+				Return(test.msgEnvs, nil)
+
+			projection, err := myprojector.RunWithVersion(ctx, "test cat", uuid8)
+
+			if err != nil {
+				t.Errorf("An error has occurred with running a projector, err: %s", err)
+			}
+			myStruct, ok := projection.State.(mockDataStructure)
+			if !ok {
+				t.Fatalf("projection state is the wrong type: %T", projection.State)
+			}
+			if myStruct.MockReducer2CallCount != test.expectedCallCount {
+				t.Errorf("Reducer 2 was called %d times instead of %d", myStruct.MockReducer2CallCount, test.expectedCallCount)
+			}
+			if projection.Version != test.expectedVersion {
+				t.Errorf("Wrong version\nExpected: %d\n     Got: %d", test.expectedVersion, projection.Version)
+			}
+			if projection.GlobalPosition != test.expectedGlobalPosition {
+				t.Errorf("Wrong global position\nExpected: %d\n     Got: %d", test.expectedGlobalPosition, projection.GlobalPosition)
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package gomessagestore
+
+import (
+	"time"
+)
+
+// RunBoundary is where RunUntil stops applying messages; make one with UntilVersion, UntilPosition or UntilTime
+type RunBoundary struct {
+	version  *int64
+	position *int64
+	time     time.Time
+}
+
+// UntilVersion has RunUntil apply messages up to and including version
+func UntilVersion(version int64) RunBoundary {
+	return RunBoundary{version: &version}
+}
+
+// UntilPosition has RunUntil apply messages up to and including globalPosition
+func UntilPosition(globalPosition int64) RunBoundary {
+	return RunBoundary{position: &globalPosition}
+}
+
+// UntilTime has RunUntil apply messages written before until, as Get's Until() does
+func UntilTime(until time.Time) RunBoundary {
+	return RunBoundary{time: until}
+}
+
+// isSet reports whether the boundary was made by one of the Until functions
+func (until RunBoundary) isSet() bool {
+	return until.version != nil || until.position != nil || !until.time.IsZero()
+}
+
+// includes reports whether a message falls within the boundary; boundaries in time are left to the message store
+func (until RunBoundary) includes(msg Message) bool {
+	switch {
+	case until.version != nil:
+		return msg.Version() <= *until.version
+	case until.position != nil:
+		return msg.Position() <= *until.position
+	}
+
+	return true
+}
+
+// startsFrom reports whether a cached or snapshotted projection can be projected on up to the boundary
+// Projections don't record when their last message was written, so projecting up to a time always starts from the beginning.
+func (until RunBoundary) startsFrom(projection Projection) bool {
+	switch {
+	case until.version != nil:
+		return projection.Version <= *until.version
+	case until.position != nil:
+		return projection.GlobalPosition <= *until.position
+	}
+
+	return false
+}
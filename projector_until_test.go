@@ -0,0 +1,242 @@
+package gomessagestore_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	. "github.com/blackhatbrigade/gomessagestore"
+	"github.com/blackhatbrigade/gomessagestore/inmem_repository"
+	mock_repository "github.com/blackhatbrigade/gomessagestore/repository/mocks"
+	"github.com/blackhatbrigade/gomessagestore/uuid"
+	"github.com/golang/mock/gomock"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+var depositTimes = []time.Time{
+	time.Date(2019, 3, 1, 9, 0, 0, 0, time.UTC),
+	time.Date(2019, 3, 1, 10, 0, 0, 0, time.UTC),
+	time.Date(2019, 3, 1, 11, 0, 0, 0, time.UTC),
+	time.Date(2019, 3, 1, 12, 0, 0, 0, time.UTC),
+	time.Date(2019, 3, 1, 13, 0, 0, 0, time.UTC),
+}
+
+// getDepositHistory gives account uuid8 deposits of 1, 2, 4, 8 and 16 at versions 0 to 4, and global positions 0, 2, 4, 6 and 8
+func getDepositHistory(t *testing.T) MessageStore {
+	msgStore := NewMessageStoreFromRepository(inmem_repository.NewInMemoryRepository(nil), logrus.New())
+	amounts := []float64{1, 2, 4, 8, 16}
+	for i, at := range depositTimes {
+		for _, entityID := range []uuid.UUID{uuid8, uuid9} {
+			err := msgStore.Write(context.Background(), &Event{
+				ID:             NewID(),
+				MessageType:    "Deposited",
+				EntityID:       entityID,
+				StreamCategory: "account",
+				Data:           map[string]interface{}{"amount": amounts[i]},
+				Time:           at,
+			})
+			if err != nil {
+				t.Fatalf("Error writing deposit: %s", err)
+			}
+		}
+	}
+	return msgStore
+}
+
+func TestProjectorRunsUntil(t *testing.T) {
+	tests := []struct {
+		name               string
+		until              RunBoundary
+		expectedProjection Projection
+	}{{
+		name:               "a version",
+		until:              UntilVersion(2),
+		expectedProjection: Projection{State: balance{Amount: 7, Deposits: 3}, Version: 2, GlobalPosition: 4},
+	}, {
+		name:               "a global position",
+		until:              UntilPosition(5),
+		expectedProjection: Projection{State: balance{Amount: 7, Deposits: 3}, Version: 2, GlobalPosition: 4},
+	}, {
+		name:               "the global position of a message, including it",
+		until:              UntilPosition(4),
+		expectedProjection: Projection{State: balance{Amount: 7, Deposits: 3}, Version: 2, GlobalPosition: 4},
+	}, {
+		name:               "a time, leaving out messages written at that time",
+		until:              UntilTime(depositTimes[2]),
+		expectedProjection: Projection{State: balance{Amount: 3, Deposits: 2}, Version: 1, GlobalPosition: 2},
+	}, {
+		name:               "before the first message",
+		until:              UntilVersion(-1),
+		expectedProjection: Projection{State: balance{}, Version: -1, GlobalPosition: -1},
+	}, {
+		name:               "past the end of the stream",
+		until:              UntilVersion(10),
+		expectedProjection: Projection{State: balance{Amount: 31, Deposits: 5}, Version: 4, GlobalPosition: 8},
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			msgStore := getDepositHistory(t)
+			projector, err := msgStore.CreateProjector(getSnapshotProjectorOptions()...)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			projection, err := projector.RunUntil(context.Background(), "account", uuid8, test.until)
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.expectedProjection, projection)
+		})
+	}
+}
+
+func TestProjectorRunsUntilAcrossBatches(t *testing.T) {
+	tests := []struct {
+		name              string
+		until             RunBoundary
+		expectedCallCount int
+		readsSecondBatch  bool
+	}{{
+		name:              "stops reading at a version in the first batch",
+		until:             UntilVersion(503),
+		expectedCallCount: 500,
+	}, {
+		name:              "reads on to a version in the second batch",
+		until:             UntilVersion(1203),
+		expectedCallCount: 1200,
+		readsSecondBatch:  true,
+	}, {
+		name:              "stops reading at a global position in the first batch",
+		until:             UntilPosition(1199),
+		expectedCallCount: 700,
+	}, {
+		name:              "reads on to a global position in the second batch",
+		until:             UntilPosition(1699),
+		expectedCallCount: 1200,
+		readsSecondBatch:  true,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mock_repository.NewMockRepository(ctrl)
+			ctx := context.Background()
+			streamName := "test cat-" + uuid8.String()
+
+			mockRepo.
+				EXPECT().
+				GetAllMessagesInStream(ctx, streamName, 1000).
+				Return(getLotsOfSampleEventsAsEnvelopes(1000, 0), nil)
+			if test.readsSecondBatch {
+				mockRepo.
+					EXPECT().
+					GetAllMessagesInStreamSince(ctx, streamName, int64(1004), 1000).
+					Return(getLotsOfSampleEventsAsEnvelopes(500, 1000), nil)
+			}
+
+			msgStore := NewMessageStoreFromRepository(mockRepo, logrus.New())
+			projector, err := msgStore.CreateProjector(
+				DefaultState(mockDataStructure{}),
+				WithReducer(new(mockReducer1)),
+				WithReducer(new(mockReducer2)),
+			)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			projection, err := projector.RunUntil(ctx, "test cat", uuid8, test.until)
+
+			assert.NoError(t, err)
+			state := projection.State.(mockDataStructure)
+			assert.Equal(t, test.expectedCallCount, state.MockReducer1CallCount+state.MockReducer2CallCount)
+		})
+	}
+}
+
+func TestProjectorRunsUntilFromSnapshots(t *testing.T) {
+	ctx := context.Background()
+	msgStore := getDepositHistory(t)
+	store := &memorySnapshotStore{snapshots: make(map[string]Snapshot)}
+	projector, err := msgStore.CreateProjector(getSnapshotProjectorOptions(WithSnapshots(1), WithSnapshotStore(store))...)
+	if !assert.NoError(t, err) {
+		return
+	}
+	_, err = projector.Run(ctx, "account", uuid8)
+	assert.NoError(t, err)
+
+	// a state the deposits couldn't give shows when the snapshot was used
+	streamName := "account-" + uuid8.String()
+	snapshot := store.snapshots[streamName]
+	snapshot.State = json.RawMessage(`{"amount": 1000, "deposits": 5}`)
+	store.snapshots[streamName] = snapshot
+
+	tests := []struct {
+		name          string
+		until         RunBoundary
+		expectedState balance
+	}{{
+		name:          "starts from a snapshot at the boundary",
+		until:         UntilVersion(4),
+		expectedState: balance{Amount: 1000, Deposits: 5},
+	}, {
+		name:          "starts from a snapshot before the boundary",
+		until:         UntilPosition(100),
+		expectedState: balance{Amount: 1000, Deposits: 5},
+	}, {
+		name:          "ignores a snapshot after the boundary",
+		until:         UntilVersion(2),
+		expectedState: balance{Amount: 7, Deposits: 3},
+	}, {
+		name:          "ignores snapshots for boundaries in time",
+		until:         UntilTime(depositTimes[4].Add(time.Hour)),
+		expectedState: balance{Amount: 31, Deposits: 5},
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			projection, err := projector.RunUntil(ctx, "account", uuid8, test.until)
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.expectedState, projection.State)
+			assert.Equal(t, snapshot, store.snapshots[streamName], "RunUntil doesn't save snapshots")
+		})
+	}
+}
+
+func TestProjectorRunsUntilWithCache(t *testing.T) {
+	ctx := context.Background()
+	msgStore := getDepositHistory(t)
+	projector, err := msgStore.CreateProjector(getSnapshotProjectorOptions(WithCache(10))...)
+	if !assert.NoError(t, err) {
+		return
+	}
+	_, err = projector.Run(ctx, "account", uuid8)
+	assert.NoError(t, err)
+
+	before, err := projector.RunUntil(ctx, "account", uuid8, UntilVersion(2))
+	assert.NoError(t, err)
+	latest, err := projector.RunUntil(ctx, "account", uuid8, UntilVersion(4))
+	assert.NoError(t, err)
+	inTime, err := projector.RunUntil(ctx, "account", uuid8, UntilTime(depositTimes[2]))
+	assert.NoError(t, err)
+
+	assert.Equal(t, balance{Amount: 7, Deposits: 3}, before.State)
+	assert.Equal(t, balance{Amount: 31, Deposits: 5}, latest.State)
+	assert.Equal(t, balance{Amount: 3, Deposits: 2}, inTime.State)
+}
+
+func TestProjectorRunUntilNeedsABoundary(t *testing.T) {
+	msgStore := getDepositHistory(t)
+	projector, err := msgStore.CreateProjector(getSnapshotProjectorOptions()...)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	_, err = projector.RunUntil(context.Background(), "account", uuid8, RunBoundary{})
+
+	assert.Equal(t, ErrMissingRunBoundary, err)
+}
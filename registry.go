@@ -0,0 +1,205 @@
+package gomessagestore
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/blackhatbrigade/gomessagestore/repository"
+	"github.com/blackhatbrigade/gomessagestore/streamname"
+	"github.com/blackhatbrigade/gomessagestore/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// Registry maps message types to the Go types their data is decoded into
+type Registry struct {
+	mutex     sync.RWMutex
+	byName    map[string]reflect.Type
+	byType    map[reflect.Type]string
+	upcasters map[string][]Upcaster // by message type, upcasters[n] takes data from schema version n+1 to n+2
+}
+
+// NewRegistry creates an empty Registry; pass it to a message store with WithRegistry()
+func NewRegistry() *Registry {
+	return &Registry{
+		byName:    make(map[string]reflect.Type),
+		byType:    make(map[reflect.Type]string),
+		upcasters: make(map[string][]Upcaster),
+	}
+}
+
+// Register maps a message type to the type of example, such as Register("Deposited", Deposited{}); decoded data has the same type as example, so register a pointer to get pointers back
+func (r *Registry) Register(messageType string, example interface{}) error {
+	if messageType == "" {
+		return ErrMissingMessageType
+	}
+	if example == nil {
+		return ErrRegistryNilType
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	dataType := reflect.TypeOf(example)
+	if _, ok := r.byName[messageType]; ok {
+		return ErrRegistryTypeAlreadyRegistered
+	}
+	if _, ok := r.byType[dataType]; ok {
+		return ErrRegistryTypeAlreadyRegistered
+	}
+
+	r.byName[messageType] = dataType
+	r.byType[dataType] = messageType
+	return nil
+}
+
+// MessageType returns the message type data was registered under
+func (r *Registry) MessageType(data interface{}) (string, bool) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	messageType, ok := r.byType[reflect.TypeOf(data)]
+	return messageType, ok
+}
+
+// Convert is a MessageConverter that decodes the data of registered message types into a TypedMessage
+func (r *Registry) Convert(messageEnvelope *repository.MessageEnvelope) (Message, error) {
+	return r.convert(messageEnvelope, false)
+}
+
+// convert decodes registered message types, keeping numbers in metadata and in interface{} values of the data as json.Number when exactNumbers is set
+func (r *Registry) convert(messageEnvelope *repository.MessageEnvelope, exactNumbers bool) (Message, error) {
+	r.mutex.RLock()
+	dataType, ok := r.byName[messageEnvelope.MessageType]
+	r.mutex.RUnlock()
+	if !ok {
+		return nil, errors.New("Message type is not registered, moving on to next converter")
+	}
+
+	data, err := decodeData(messageEnvelope.Data, dataType, exactNumbers)
+	if err != nil {
+		return nil, err
+	}
+	metadata := make(map[string]interface{})
+	if err := decodeJSON(messageEnvelope.Metadata, &metadata, exactNumbers); err != nil {
+		logrus.WithError(err).Error("Can't unmarshal JSON from message envelope metadata")
+	}
+
+	msg := &TypedMessage{
+		ID:             messageEnvelope.ID,
+		StreamName:     messageEnvelope.StreamName,
+		MessageType:    messageEnvelope.MessageType,
+		MessageVersion: messageEnvelope.Version,
+		GlobalPosition: messageEnvelope.GlobalPosition,
+		Data:           data,
+		Metadata:       metadata,
+		Time:           messageEnvelope.Time,
+	}
+
+	return msg, nil
+}
+
+// decodeData unmarshals data into a new value of dataType
+func decodeData(data []byte, dataType reflect.Type, exactNumbers bool) (interface{}, error) {
+	if dataType.Kind() == reflect.Ptr {
+		value := reflect.New(dataType.Elem())
+		if err := decodeJSON(data, value.Interface(), exactNumbers); err != nil {
+			return nil, err
+		}
+		return value.Interface(), nil
+	}
+
+	value := reflect.New(dataType)
+	if err := decodeJSON(data, value.Interface(), exactNumbers); err != nil {
+		return nil, err
+	}
+	return value.Elem().Interface(), nil
+}
+
+// TypedMessage implements the Message interface for a message whose data is a registered Go type
+// Write one through a message store with a Registry and its MessageType is filled in from the registration.
+type TypedMessage struct {
+	ID             uuid.UUID   // ID of the message
+	StreamName     string      // the full name of the stream the message is in; build it with the streamname package
+	MessageType    string      // the message type of the message; may be left blank when writing registered data
+	MessageVersion int64       // the version number of the message
+	GlobalPosition int64       // the global position of the message
+	Data           interface{} // the data of the message, of the type it was registered with
+	Metadata       map[string]interface{}
+	Time           time.Time
+}
+
+// Type returns the type of the message
+func (msg *TypedMessage) Type() string {
+	return msg.MessageType
+}
+
+// Version returns the version of the message
+func (msg *TypedMessage) Version() int64 {
+	return msg.MessageVersion
+}
+
+// Position returns the global position of the message
+func (msg *TypedMessage) Position() int64 {
+	return msg.GlobalPosition
+}
+
+// ToEnvelope converts the message to a MessageEnvelope which is then returned
+func (msg *TypedMessage) ToEnvelope() (*repository.MessageEnvelope, error) {
+	// check to ensure that all required fields of the message are valid
+	if msg.MessageType == "" {
+		return nil, ErrMissingMessageType
+	}
+
+	if msg.StreamName == "" {
+		return nil, ErrMissingStreamName
+	}
+
+	if msg.Data == nil {
+		return nil, ErrMissingMessageData
+	}
+
+	if msg.ID == NilUUID {
+		return nil, ErrMessageNoID
+	}
+
+	data, err := json.Marshal(msg.Data)
+	metadata, errm := json.Marshal(msg.Metadata)
+	if err != nil || errm != nil {
+		return nil, ErrUnserializableData
+	}
+
+	// create a new MessageEnvelope based on the message
+	msgEnv := &repository.MessageEnvelope{
+		ID:             msg.ID,
+		MessageType:    msg.MessageType,
+		StreamName:     msg.StreamName,
+		StreamCategory: streamname.Category(msg.StreamName),
+		Data:           data,
+		Metadata:       metadata,
+		Time:           msg.Time,
+		Version:        msg.MessageVersion,
+		GlobalPosition: msg.GlobalPosition,
+	}
+
+	return msgEnv, nil
+}
+
+// withRegisteredType fills in the MessageType of typed messages from the registry, leaving the caller's message untouched
+func (r *Registry) withRegisteredType(message Message) (Message, error) {
+	typed, ok := message.(*TypedMessage)
+	if !ok || typed.MessageType != "" || typed.Data == nil {
+		return message, nil
+	}
+
+	messageType, ok := r.MessageType(typed.Data)
+	if !ok {
+		return nil, ErrUnregisteredMessageData
+	}
+
+	withType := *typed
+	withType.MessageType = messageType
+	return &withType, nil
+}
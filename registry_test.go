@@ -0,0 +1,313 @@
+package gomessagestore_test
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/blackhatbrigade/gomessagestore"
+	"github.com/blackhatbrigade/gomessagestore/inmem_repository"
+	"github.com/blackhatbrigade/gomessagestore/repository"
+	"github.com/blackhatbrigade/gomessagestore/repository/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+type deposited struct {
+	Amount int    `json:"amount"`
+	Note   string `json:"note"`
+}
+
+type withdrawn struct {
+	Amount int `json:"amount"`
+}
+
+func getSampleRegistry(t *testing.T) *Registry {
+	registry := NewRegistry()
+	if err := registry.Register("Deposited", deposited{}); err != nil {
+		t.Fatalf("registering Deposited failed: %s", err)
+	}
+	if err := registry.Register("Withdrawn", &withdrawn{}); err != nil {
+		t.Fatalf("registering Withdrawn failed: %s", err)
+	}
+
+	return registry
+}
+
+func getSampleTypedEnvelope(messageType, data string) *repository.MessageEnvelope {
+	return &repository.MessageEnvelope{
+		ID:             uuid3,
+		MessageType:    messageType,
+		StreamName:     "account-" + uuid8.String(),
+		StreamCategory: "account",
+		Data:           []byte(data),
+		Metadata:       []byte(`{"correlationId":"abc"}`),
+		Version:        2,
+		GlobalPosition: 9,
+	}
+}
+
+func TestRegistryRegister(t *testing.T) {
+	tests := []struct {
+		name        string
+		messageType string
+		example     interface{}
+		expectedErr error
+	}{{
+		name:        "registers a new type",
+		messageType: "Closed",
+		example:     struct{ Reason string }{},
+	}, {
+		name:        "fails without a message type",
+		example:     struct{ Reason string }{},
+		expectedErr: ErrMissingMessageType,
+	}, {
+		name:        "fails with a nil example",
+		messageType: "Closed",
+		expectedErr: ErrRegistryNilType,
+	}, {
+		name:        "fails when the message type is taken",
+		messageType: "Deposited",
+		example:     struct{ Reason string }{},
+		expectedErr: ErrRegistryTypeAlreadyRegistered,
+	}, {
+		name:        "fails when the Go type is taken",
+		messageType: "Deposited2",
+		example:     deposited{},
+		expectedErr: ErrRegistryTypeAlreadyRegistered,
+	}, {
+		name:        "treats a pointer as its own Go type",
+		messageType: "DepositedPointer",
+		example:     &deposited{},
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			registry := getSampleRegistry(t)
+
+			err := registry.Register(test.messageType, test.example)
+
+			assert.Equal(t, test.expectedErr, err)
+			if test.expectedErr == nil {
+				messageType, ok := registry.MessageType(test.example)
+				assert.True(t, ok)
+				assert.Equal(t, test.messageType, messageType)
+			}
+		})
+	}
+}
+
+func TestRegistryConvert(t *testing.T) {
+	tests := []struct {
+		name         string
+		envelope     *repository.MessageEnvelope
+		expectedData interface{}
+		expectError  bool
+	}{{
+		name:         "decodes a type registered by value",
+		envelope:     getSampleTypedEnvelope("Deposited", `{"amount":5,"note":"rent"}`),
+		expectedData: deposited{Amount: 5, Note: "rent"},
+	}, {
+		name:         "decodes a type registered by pointer",
+		envelope:     getSampleTypedEnvelope("Withdrawn", `{"amount":3}`),
+		expectedData: &withdrawn{Amount: 3},
+	}, {
+		name:        "passes on types that aren't registered",
+		envelope:    getSampleTypedEnvelope("Frozen", `{}`),
+		expectError: true,
+	}, {
+		name:        "passes on data that doesn't decode",
+		envelope:    getSampleTypedEnvelope("Deposited", `{"amount":"five"}`),
+		expectError: true,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			registry := getSampleRegistry(t)
+
+			msg, err := registry.Convert(test.envelope)
+
+			if test.expectError {
+				assert.Error(t, err)
+				assert.Nil(t, msg)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, &TypedMessage{
+				ID:             uuid3,
+				StreamName:     "account-" + uuid8.String(),
+				MessageType:    test.envelope.MessageType,
+				MessageVersion: 2,
+				GlobalPosition: 9,
+				Data:           test.expectedData,
+				Metadata:       map[string]interface{}{"correlationId": "abc"},
+			}, msg)
+		})
+	}
+}
+
+func TestGetWithRegistry(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockRepository(ctrl)
+	ctx := context.Background()
+
+	registered := getSampleTypedEnvelope("Deposited", `{"amount":5}`)
+	unregistered := getSampleTypedEnvelope("Frozen", `{"reason":"fraud"}`)
+
+	mockRepo.
+		EXPECT().
+		GetAllMessagesInStream(ctx, registered.StreamName, 1000).
+		Return([]*repository.MessageEnvelope{registered, unregistered}, nil)
+
+	msgStore := NewMessageStoreFromRepository(mockRepo, logrus.New(), WithRegistry(getSampleRegistry(t)))
+	msgs, err := msgStore.Get(ctx, EventStream("account", uuid8))
+
+	assert.NoError(t, err)
+	if assert.Len(t, msgs, 2) {
+		typed, ok := msgs[0].(*TypedMessage)
+		if assert.True(t, ok, "registered type should be a TypedMessage, got %T", msgs[0]) {
+			assert.Equal(t, deposited{Amount: 5}, typed.Data)
+		}
+		event, ok := msgs[1].(*Event)
+		if assert.True(t, ok, "unregistered type should fall back to an Event, got %T", msgs[1]) {
+			assert.Equal(t, map[string]interface{}{"reason": "fraud"}, event.Data)
+		}
+	}
+}
+
+func TestGetByIDWithRegistry(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockRepository(ctrl)
+	ctx := context.Background()
+
+	msgEnv := getSampleTypedEnvelope("Withdrawn", `{"amount":3}`)
+
+	mockRepo.
+		EXPECT().
+		GetMessageByID(ctx, msgEnv.ID).
+		Return(msgEnv, nil)
+
+	msgStore := NewMessageStoreFromRepository(mockRepo, logrus.New(), WithRegistry(getSampleRegistry(t)))
+	msg, err := msgStore.GetByID(ctx, msgEnv.ID)
+
+	assert.NoError(t, err)
+	typed, ok := msg.(*TypedMessage)
+	if assert.True(t, ok, "registered type should be a TypedMessage, got %T", msg) {
+		assert.Equal(t, &withdrawn{Amount: 3}, typed.Data)
+	}
+}
+
+func TestWriteWithRegistry(t *testing.T) {
+	tests := []struct {
+		name         string
+		registry     bool
+		message      *TypedMessage
+		expectedType string
+		expectedErr  error
+	}{{
+		name:         "derives the message type from the registration",
+		registry:     true,
+		message:      &TypedMessage{Data: deposited{Amount: 5}},
+		expectedType: "Deposited",
+	}, {
+		name:         "derives the message type of pointer registrations",
+		registry:     true,
+		message:      &TypedMessage{Data: &withdrawn{Amount: 3}},
+		expectedType: "Withdrawn",
+	}, {
+		name:         "keeps a message type that was set",
+		registry:     true,
+		message:      &TypedMessage{MessageType: "Refunded", Data: deposited{Amount: 5}},
+		expectedType: "Refunded",
+	}, {
+		name:        "fails for data that isn't registered",
+		registry:    true,
+		message:     &TypedMessage{Data: struct{ Reason string }{"fraud"}},
+		expectedErr: ErrUnregisteredMessageData,
+	}, {
+		name:        "fails without a registry or message type",
+		message:     &TypedMessage{Data: deposited{Amount: 5}},
+		expectedErr: ErrMissingMessageType,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mock_repository.NewMockRepository(ctrl)
+			ctx := context.Background()
+
+			test.message.ID = uuid3
+			test.message.StreamName = "account-" + uuid8.String()
+			originalType := test.message.MessageType
+
+			var opts []MessageStoreOption
+			if test.registry {
+				opts = append(opts, WithRegistry(getSampleRegistry(t)))
+			}
+
+			if test.expectedErr == nil {
+				mockRepo.
+					EXPECT().
+					WriteMessage(ctx, gomock.Any()).
+					Do(func(_ context.Context, msgEnv *repository.MessageEnvelope) {
+						assert.Equal(t, test.expectedType, msgEnv.MessageType)
+						assert.Equal(t, "account", msgEnv.StreamCategory)
+					})
+			}
+
+			msgStore := NewMessageStoreFromRepository(mockRepo, logrus.New(), opts...)
+			err := msgStore.Write(ctx, test.message)
+
+			assert.Equal(t, test.expectedErr, err)
+			assert.Equal(t, originalType, test.message.MessageType, "the caller's message should not be changed")
+		})
+	}
+}
+
+type depositReducer struct{}
+
+func (depositReducer) Type() string {
+	return "Deposited"
+}
+
+func (depositReducer) Reduce(msg Message, previousState interface{}) interface{} {
+	total := previousState.(int)
+	typed, ok := msg.(*TypedMessage)
+	if !ok {
+		return total
+	}
+
+	return total + typed.Data.(deposited).Amount
+}
+
+func TestProjectorWithRegistry(t *testing.T) {
+	envs := []repository.MessageEnvelope{
+		*getSampleTypedEnvelope("Deposited", `{"amount":5}`),
+		*getSampleTypedEnvelope("Deposited", `{"amount":7}`),
+	}
+	envs[1].ID = uuid4
+	envs[1].Version = 3
+	envs[1].GlobalPosition = 10
+
+	repo := inmem_repository.NewInMemoryRepository(envs)
+	msgStore := NewMessageStoreFromRepository(repo, logrus.New(), WithRegistry(getSampleRegistry(t)))
+
+	projector, err := msgStore.CreateProjector(
+		DefaultState(0),
+		WithReducer(depositReducer{}),
+	)
+	if err != nil {
+		t.Fatalf("Error creating projector: %s", err)
+	}
+
+	projection, err := projector.Run(context.Background(), "account", uuid8)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 12, projection)
+}
@@ -3,6 +3,11 @@ package repository
 import "errors"
 
 var (
-	ErrMessageNoID       = errors.New("Message cannot be written without a new UUID")
-	ErrNegativeBatchSize = errors.New("Batch size cannot be negative")
+	ErrMessageNoID        = errors.New("Message cannot be written without a new UUID")
+	ErrNegativeBatchSize  = errors.New("Batch size cannot be negative")
+	ErrBlankMessageID     = errors.New("Message ID cannot be blank")
+	ErrInvalidFilter      = errors.New("Filter must have something to match on")
+	ErrUnsafeFilterValue  = errors.New("Filter values cannot contain a NUL character")
+	ErrWrongStreamVersion = errors.New("Stream is not at the expected version")
+	ErrMixedStreams       = errors.New("Messages written together must all be in the same stream")
 )
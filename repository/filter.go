@@ -0,0 +1,332 @@
+package repository
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type filterKind int
+
+const (
+	filterNone filterKind = iota
+	filterMetadataEquals
+	filterDataEquals
+	filterTypeIn
+	filterTimeRange
+	filterAnd
+)
+
+//Filter narrows down the messages a read returns.
+//Build one with MetadataEquals, DataEquals, TypeIn, TimeRange or And; it is rendered as a Message DB _condition for postgres and evaluated in Go for the in memory repository.
+type Filter struct {
+	kind    filterKind
+	path    []string // the JSON keys leading to the compared value, for metadata and data filters
+	value   string   // the text the JSON value must equal, for metadata and data filters
+	types   []string // the message types to match, for type filters
+	since   time.Time
+	until   time.Time
+	filters []Filter // every filter that must match, for and filters
+}
+
+//MetadataEquals matches messages whose metadata has the key with the value (compared as text, like postgres's ->> operator)
+func MetadataEquals(key string, value string) Filter {
+	return Filter{kind: filterMetadataEquals, path: []string{key}, value: value}
+}
+
+//DataEquals matches messages whose data has the value at the JSON path (compared as text, like postgres's #>> operator); integer keys index into arrays
+func DataEquals(value string, path ...string) Filter {
+	return Filter{kind: filterDataEquals, path: path, value: value}
+}
+
+//TypeIn matches messages of any of the types
+func TypeIn(types ...string) Filter {
+	return Filter{kind: filterTypeIn, types: types}
+}
+
+//TimeRange matches messages written at or after since and before until; a zero time leaves that end of the range open
+func TimeRange(since, until time.Time) Filter {
+	return Filter{kind: filterTimeRange, since: since, until: until}
+}
+
+//And matches messages that match every one of the filters
+func And(filters ...Filter) Filter {
+	return Filter{kind: filterAnd, filters: filters}
+}
+
+//Condition renders the filter as a SQL condition on the messages table, with every value escaped as a literal; Message DB pastes _condition into its query, so it cannot take bind parameters
+func (f Filter) Condition() (string, error) {
+	switch f.kind {
+	case filterMetadataEquals, filterDataEquals:
+		if len(f.path) == 0 {
+			return "", ErrInvalidFilter
+		}
+		column := "metadata"
+		if f.kind == filterDataEquals {
+			column = "data"
+		}
+		keys := make([]string, len(f.path))
+		for i, key := range f.path {
+			literal, err := quoteLiteral(key)
+			if err != nil {
+				return "", err
+			}
+			keys[i] = literal
+		}
+		value, err := quoteLiteral(f.value)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("(%s #>> ARRAY[%s]) = %s", column, strings.Join(keys, ", "), value), nil
+
+	case filterTypeIn:
+		if len(f.types) == 0 {
+			return "", ErrInvalidFilter
+		}
+		types := make([]string, len(f.types))
+		for i, msgType := range f.types {
+			literal, err := quoteLiteral(msgType)
+			if err != nil {
+				return "", err
+			}
+			types[i] = literal
+		}
+		return fmt.Sprintf("type IN (%s)", strings.Join(types, ", ")), nil
+
+	case filterTimeRange:
+		conditions := []string{}
+		if !f.since.IsZero() {
+			conditions = append(conditions, fmt.Sprintf("time >= '%s'", formatTimestamp(f.since)))
+		}
+		if !f.until.IsZero() {
+			conditions = append(conditions, fmt.Sprintf("time < '%s'", formatTimestamp(f.until)))
+		}
+		if len(conditions) == 0 {
+			return "", ErrInvalidFilter
+		}
+		return strings.Join(conditions, " AND "), nil
+
+	case filterAnd:
+		if len(f.filters) == 0 {
+			return "", ErrInvalidFilter
+		}
+		conditions := make([]string, len(f.filters))
+		for i, filter := range f.filters {
+			condition, err := filter.Condition()
+			if err != nil {
+				return "", err
+			}
+			conditions[i] = "(" + condition + ")"
+		}
+		return strings.Join(conditions, " AND "), nil
+	}
+
+	return "", ErrInvalidFilter
+}
+
+//Matches evaluates the filter against a message the same way postgres would evaluate its condition
+func (f Filter) Matches(msg *MessageEnvelope) bool {
+	switch f.kind {
+	case filterMetadataEquals:
+		text, ok := jsonText(msg.Metadata, f.path)
+		return ok && text == f.value
+	case filterDataEquals:
+		text, ok := jsonText(msg.Data, f.path)
+		return ok && text == f.value
+	case filterTypeIn:
+		for _, msgType := range f.types {
+			if msg.MessageType == msgType {
+				return true
+			}
+		}
+		return false
+	case filterTimeRange:
+		if !f.since.IsZero() && msg.Time.Before(f.since) {
+			return false
+		}
+		return f.until.IsZero() || msg.Time.Before(f.until)
+	case filterAnd:
+		for _, filter := range f.filters {
+			if !filter.Matches(msg) {
+				return false
+			}
+		}
+		return len(f.filters) > 0
+	}
+
+	return false
+}
+
+// quoteLiteral escapes a string as a postgres literal, whatever standard_conforming_strings is set to
+func quoteLiteral(value string) (string, error) {
+	if strings.Contains(value, "\x00") {
+		return "", ErrUnsafeFilterValue
+	}
+
+	literal := "'" + strings.Replace(value, "'", "''", -1) + "'"
+	if strings.Contains(value, `\`) {
+		literal = "E" + strings.Replace(literal, `\`, `\\`, -1)
+	}
+
+	return literal, nil
+}
+
+// formatTimestamp writes a time the way message db stores it, in utc without a time zone
+func formatTimestamp(t time.Time) string {
+	return t.UTC().Format("2006-01-02 15:04:05.999999")
+}
+
+// jsonText finds the value at the path and returns it as text, like postgres's #>> operator on a jsonb column; ok is false when there is no such value or it is null
+// Keys in the path that are integers index into arrays, counting from the end when negative.
+func jsonText(doc []byte, path []string) (text string, ok bool) {
+	decoder := json.NewDecoder(bytes.NewReader(doc))
+	decoder.UseNumber() // keeps numbers as they were written, as jsonb does
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return "", false
+	}
+
+	for _, key := range path {
+		switch node := value.(type) {
+		case map[string]interface{}:
+			if value, ok = node[key]; !ok {
+				return "", false
+			}
+		case []interface{}:
+			index, err := strconv.Atoi(key)
+			if err != nil {
+				return "", false
+			}
+			if index < 0 {
+				index += len(node)
+			}
+			if index < 0 || index >= len(node) {
+				return "", false
+			}
+			value = node[index]
+		default:
+			return "", false
+		}
+	}
+
+	switch value := value.(type) {
+	case nil:
+		return "", false
+	case string:
+		return value, true
+	}
+
+	var builder strings.Builder
+	writeJSONB(&builder, value)
+	return builder.String(), true
+}
+
+// writeJSONB writes a decoded JSON value the way postgres prints jsonb: object keys sorted shortest first, and a space after every colon and comma
+func writeJSONB(builder *strings.Builder, value interface{}) {
+	switch value := value.(type) {
+	case nil:
+		builder.WriteString("null")
+	case bool:
+		builder.WriteString(strconv.FormatBool(value))
+	case json.Number:
+		builder.WriteString(numericText(value.String()))
+	case string:
+		writeJSONBString(builder, value)
+	case []interface{}:
+		builder.WriteString("[")
+		for i, element := range value {
+			if i > 0 {
+				builder.WriteString(", ")
+			}
+			writeJSONB(builder, element)
+		}
+		builder.WriteString("]")
+	case map[string]interface{}:
+		keys := make([]string, 0, len(value))
+		for key := range value {
+			keys = append(keys, key)
+		}
+		sort.Slice(keys, func(i, j int) bool {
+			if len(keys[i]) != len(keys[j]) {
+				return len(keys[i]) < len(keys[j])
+			}
+			return keys[i] < keys[j]
+		})
+
+		builder.WriteString("{")
+		for i, key := range keys {
+			if i > 0 {
+				builder.WriteString(", ")
+			}
+			writeJSONBString(builder, key)
+			builder.WriteString(": ")
+			writeJSONB(builder, value[key])
+		}
+		builder.WriteString("}")
+	}
+}
+
+// writeJSONBString quotes a string the way postgres does, escaping only quotes, backslashes and control characters
+func writeJSONBString(builder *strings.Builder, value string) {
+	builder.WriteString(`"`)
+	for _, r := range value {
+		switch r {
+		case '"':
+			builder.WriteString(`\"`)
+		case '\\':
+			builder.WriteString(`\\`)
+		case '\b':
+			builder.WriteString(`\b`)
+		case '\f':
+			builder.WriteString(`\f`)
+		case '\n':
+			builder.WriteString(`\n`)
+		case '\r':
+			builder.WriteString(`\r`)
+		case '\t':
+			builder.WriteString(`\t`)
+		default:
+			if r < 0x20 {
+				fmt.Fprintf(builder, `\u%04x`, r)
+			} else {
+				builder.WriteRune(r)
+			}
+		}
+	}
+	builder.WriteString(`"`)
+}
+
+// numericText writes a JSON number the way postgres's numeric type does: as written, but without an exponent or a negative zero
+func numericText(number string) string {
+	exponentAt := strings.IndexAny(number, "eE")
+	if exponentAt < 0 {
+		if strings.Trim(number, "-0.") == "" {
+			return strings.TrimPrefix(number, "-")
+		}
+		return number
+	}
+
+	exponent, err := strconv.Atoi(number[exponentAt+1:])
+	if err != nil {
+		return number
+	}
+	scale := 0
+	if pointAt := strings.Index(number[:exponentAt], "."); pointAt >= 0 {
+		scale = exponentAt - pointAt - 1
+	}
+	scale -= exponent
+	if scale < 0 {
+		scale = 0
+	}
+
+	rat, ok := new(big.Rat).SetString(number)
+	if !ok {
+		return number
+	}
+	return rat.FloatString(scale)
+}
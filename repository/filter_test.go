@@ -0,0 +1,202 @@
+package repository_test
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/blackhatbrigade/gomessagestore/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilterCondition(t *testing.T) {
+	tests := []struct {
+		name              string
+		filter            Filter
+		expectedCondition string
+		expectedErr       error
+	}{{
+		name:              "metadata equals compares the key as text",
+		filter:            MetadataEquals("correlationStreamName", "account-123"),
+		expectedCondition: "(metadata #>> ARRAY['correlationStreamName']) = 'account-123'",
+	}, {
+		name:              "data equals follows the path",
+		filter:            DataEquals("123", "account", "id"),
+		expectedCondition: "(data #>> ARRAY['account', 'id']) = '123'",
+	}, {
+		name:              "type in lists every type",
+		filter:            TypeIn("Deposited", "Withdrawn"),
+		expectedCondition: "type IN ('Deposited', 'Withdrawn')",
+	}, {
+		name:              "time range uses utc timestamps",
+		filter:            TimeRange(time.Date(2019, 1, 2, 3, 4, 5, 6000, time.FixedZone("test", 3600)), time.Date(2019, 1, 3, 0, 0, 0, 0, time.UTC)),
+		expectedCondition: "time >= '2019-01-02 02:04:05.000006' AND time < '2019-01-03 00:00:00'",
+	}, {
+		name:              "time range can be open at the start",
+		filter:            TimeRange(time.Time{}, time.Date(2019, 1, 3, 0, 0, 0, 0, time.UTC)),
+		expectedCondition: "time < '2019-01-03 00:00:00'",
+	}, {
+		name:              "and joins every filter",
+		filter:            And(TypeIn("Deposited"), MetadataEquals("userId", "bob")),
+		expectedCondition: "(type IN ('Deposited')) AND ((metadata #>> ARRAY['userId']) = 'bob')",
+	}, {
+		name:              "quotes are escaped",
+		filter:            MetadataEquals("name", "o'brien'); DROP TABLE messages; --"),
+		expectedCondition: "(metadata #>> ARRAY['name']) = 'o''brien''); DROP TABLE messages; --'",
+	}, {
+		name:              "backslashes are escaped",
+		filter:            DataEquals(`a\'b`, "path"),
+		expectedCondition: `(data #>> ARRAY['path']) = E'a\\''b'`,
+	}, {
+		name:        "NUL characters are rejected",
+		filter:      MetadataEquals("name", "a\x00b"),
+		expectedErr: ErrUnsafeFilterValue,
+	}, {
+		name:        "an empty filter is rejected",
+		filter:      Filter{},
+		expectedErr: ErrInvalidFilter,
+	}, {
+		name:        "data equals without a path is rejected",
+		filter:      DataEquals("123"),
+		expectedErr: ErrInvalidFilter,
+	}, {
+		name:        "type in without types is rejected",
+		filter:      TypeIn(),
+		expectedErr: ErrInvalidFilter,
+	}, {
+		name:        "a time range without times is rejected",
+		filter:      TimeRange(time.Time{}, time.Time{}),
+		expectedErr: ErrInvalidFilter,
+	}, {
+		name:        "and without filters is rejected",
+		filter:      And(),
+		expectedErr: ErrInvalidFilter,
+	}, {
+		name:        "and with an invalid filter is rejected",
+		filter:      And(TypeIn("Deposited"), TypeIn()),
+		expectedErr: ErrInvalidFilter,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			condition, err := test.filter.Condition()
+
+			assert.Equal(test.expectedErr, err)
+			assert.Equal(test.expectedCondition, condition)
+		})
+	}
+}
+
+func TestFilterMatches(t *testing.T) {
+	msg := &MessageEnvelope{
+		MessageType: "Deposited",
+		Data:        []byte(`{"account":{"id":"123","amount":10.5,"open":true},"note":null,"items":[{"sku":"a1","qty":1.0},{"qty":2,"sku":"b2"}],"limit":1e2,"zero":-0}`),
+		Metadata:    []byte(`{"userId":"bob"}`),
+		Time:        time.Date(2019, 1, 2, 0, 0, 0, 0, time.UTC),
+	}
+
+	tests := []struct {
+		name     string
+		filter   Filter
+		expected bool
+	}{{
+		name:     "metadata with the value matches",
+		filter:   MetadataEquals("userId", "bob"),
+		expected: true,
+	}, {
+		name:   "metadata with another value does not match",
+		filter: MetadataEquals("userId", "alice"),
+	}, {
+		name:   "missing metadata does not match",
+		filter: MetadataEquals("traceId", "bob"),
+	}, {
+		name:     "data at the path matches",
+		filter:   DataEquals("123", "account", "id"),
+		expected: true,
+	}, {
+		name:     "numbers match as text",
+		filter:   DataEquals("10.5", "account", "amount"),
+		expected: true,
+	}, {
+		name:     "booleans match as text",
+		filter:   DataEquals("true", "account", "open"),
+		expected: true,
+	}, {
+		name:   "null never matches",
+		filter: DataEquals("null", "note"),
+	}, {
+		name:   "a path through a value does not match",
+		filter: DataEquals("123", "account", "id", "deeper"),
+	}, {
+		name:     "numbers match as written, as jsonb keeps them",
+		filter:   DataEquals("1.0", "items", "0", "qty"),
+		expected: true,
+	}, {
+		name:   "numbers do not match another way of writing them",
+		filter: DataEquals("1", "items", "0", "qty"),
+	}, {
+		name:     "numbers with exponents match as postgres's numeric prints them",
+		filter:   DataEquals("100", "limit"),
+		expected: true,
+	}, {
+		name:     "negative zero matches as zero",
+		filter:   DataEquals("0", "zero"),
+		expected: true,
+	}, {
+		name:     "integer keys index into arrays",
+		filter:   DataEquals("b2", "items", "1", "sku"),
+		expected: true,
+	}, {
+		name:     "negative keys index arrays from the end",
+		filter:   DataEquals("a1", "items", "-2", "sku"),
+		expected: true,
+	}, {
+		name:   "keys past the end of an array do not match",
+		filter: DataEquals("a1", "items", "2", "sku"),
+	}, {
+		name:   "other keys do not index into arrays",
+		filter: DataEquals("a1", "items", "first", "sku"),
+	}, {
+		name:     "objects match as jsonb prints them, with keys shortest first",
+		filter:   DataEquals(`{"qty": 2, "sku": "b2"}`, "items", "1"),
+		expected: true,
+	}, {
+		name:     "arrays match as jsonb prints them",
+		filter:   DataEquals(`[{"qty": 1.0, "sku": "a1"}, {"qty": 2, "sku": "b2"}]`, "items"),
+		expected: true,
+	}, {
+		name:   "objects do not match as they were written",
+		filter: DataEquals(`{"qty":2,"sku":"b2"}`, "items", "1"),
+	}, {
+		name:     "one of the types matches",
+		filter:   TypeIn("Withdrawn", "Deposited"),
+		expected: true,
+	}, {
+		name:   "other types do not match",
+		filter: TypeIn("Withdrawn"),
+	}, {
+		name:     "a time in the range matches",
+		filter:   TimeRange(time.Date(2019, 1, 2, 0, 0, 0, 0, time.UTC), time.Date(2019, 1, 3, 0, 0, 0, 0, time.UTC)),
+		expected: true,
+	}, {
+		name:   "the end of the range is not included",
+		filter: TimeRange(time.Time{}, time.Date(2019, 1, 2, 0, 0, 0, 0, time.UTC)),
+	}, {
+		name:     "and matches when everything matches",
+		filter:   And(TypeIn("Deposited"), MetadataEquals("userId", "bob")),
+		expected: true,
+	}, {
+		name:   "and does not match when anything does not match",
+		filter: And(TypeIn("Deposited"), MetadataEquals("userId", "alice")),
+	}, {
+		name:   "an empty filter does not match",
+		filter: Filter{},
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, test.filter.Matches(msg))
+		})
+	}
+}
@@ -7,8 +7,10 @@ package mock_repository
 import (
 	context "context"
 	repository "github.com/blackhatbrigade/gomessagestore/repository"
+	uuid "github.com/blackhatbrigade/gomessagestore/uuid"
 	gomock "github.com/golang/mock/gomock"
 	reflect "reflect"
+	time "time"
 )
 
 // MockRepository is a mock of Repository interface
@@ -34,6 +36,36 @@ func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
 	return m.recorder
 }
 
+// GetAllMessagesBetween mocks base method
+func (m *MockRepository) GetAllMessagesBetween(arg0 context.Context, arg1 int64, arg2, arg3 time.Time, arg4 int) ([]*repository.MessageEnvelope, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllMessagesBetween", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].([]*repository.MessageEnvelope)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllMessagesBetween indicates an expected call of GetAllMessagesBetween
+func (mr *MockRepositoryMockRecorder) GetAllMessagesBetween(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllMessagesBetween", reflect.TypeOf((*MockRepository)(nil).GetAllMessagesBetween), arg0, arg1, arg2, arg3, arg4)
+}
+
+// GetAllMessagesInCategoriesSince mocks base method
+func (m *MockRepository) GetAllMessagesInCategoriesSince(arg0 context.Context, arg1 []string, arg2 int64, arg3 int) ([]*repository.MessageEnvelope, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllMessagesInCategoriesSince", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]*repository.MessageEnvelope)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllMessagesInCategoriesSince indicates an expected call of GetAllMessagesInCategoriesSince
+func (mr *MockRepositoryMockRecorder) GetAllMessagesInCategoriesSince(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllMessagesInCategoriesSince", reflect.TypeOf((*MockRepository)(nil).GetAllMessagesInCategoriesSince), arg0, arg1, arg2, arg3)
+}
+
 // GetAllMessagesInCategory mocks base method
 func (m *MockRepository) GetAllMessagesInCategory(arg0 context.Context, arg1 string, arg2 int) ([]*repository.MessageEnvelope, error) {
 	m.ctrl.T.Helper()
@@ -49,6 +81,21 @@ func (mr *MockRepositoryMockRecorder) GetAllMessagesInCategory(arg0, arg1, arg2
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllMessagesInCategory", reflect.TypeOf((*MockRepository)(nil).GetAllMessagesInCategory), arg0, arg1, arg2)
 }
 
+// GetAllMessagesInCategoryBetween mocks base method
+func (m *MockRepository) GetAllMessagesInCategoryBetween(arg0 context.Context, arg1 string, arg2 int64, arg3, arg4 time.Time, arg5 int) ([]*repository.MessageEnvelope, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllMessagesInCategoryBetween", arg0, arg1, arg2, arg3, arg4, arg5)
+	ret0, _ := ret[0].([]*repository.MessageEnvelope)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllMessagesInCategoryBetween indicates an expected call of GetAllMessagesInCategoryBetween
+func (mr *MockRepositoryMockRecorder) GetAllMessagesInCategoryBetween(arg0, arg1, arg2, arg3, arg4, arg5 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllMessagesInCategoryBetween", reflect.TypeOf((*MockRepository)(nil).GetAllMessagesInCategoryBetween), arg0, arg1, arg2, arg3, arg4, arg5)
+}
+
 // GetAllMessagesInCategorySince mocks base method
 func (m *MockRepository) GetAllMessagesInCategorySince(arg0 context.Context, arg1 string, arg2 int64, arg3 int) ([]*repository.MessageEnvelope, error) {
 	m.ctrl.T.Helper()
@@ -64,6 +111,21 @@ func (mr *MockRepositoryMockRecorder) GetAllMessagesInCategorySince(arg0, arg1,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllMessagesInCategorySince", reflect.TypeOf((*MockRepository)(nil).GetAllMessagesInCategorySince), arg0, arg1, arg2, arg3)
 }
 
+// GetAllMessagesInCategoryWhere mocks base method
+func (m *MockRepository) GetAllMessagesInCategoryWhere(arg0 context.Context, arg1 string, arg2 int64, arg3 repository.Filter, arg4 int) ([]*repository.MessageEnvelope, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllMessagesInCategoryWhere", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].([]*repository.MessageEnvelope)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllMessagesInCategoryWhere indicates an expected call of GetAllMessagesInCategoryWhere
+func (mr *MockRepositoryMockRecorder) GetAllMessagesInCategoryWhere(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllMessagesInCategoryWhere", reflect.TypeOf((*MockRepository)(nil).GetAllMessagesInCategoryWhere), arg0, arg1, arg2, arg3, arg4)
+}
+
 // GetAllMessagesInStream mocks base method
 func (m *MockRepository) GetAllMessagesInStream(arg0 context.Context, arg1 string, arg2 int) ([]*repository.MessageEnvelope, error) {
 	m.ctrl.T.Helper()
@@ -79,6 +141,21 @@ func (mr *MockRepositoryMockRecorder) GetAllMessagesInStream(arg0, arg1, arg2 in
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllMessagesInStream", reflect.TypeOf((*MockRepository)(nil).GetAllMessagesInStream), arg0, arg1, arg2)
 }
 
+// GetAllMessagesInStreamBetween mocks base method
+func (m *MockRepository) GetAllMessagesInStreamBetween(arg0 context.Context, arg1 string, arg2 int64, arg3, arg4 time.Time, arg5 int) ([]*repository.MessageEnvelope, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllMessagesInStreamBetween", arg0, arg1, arg2, arg3, arg4, arg5)
+	ret0, _ := ret[0].([]*repository.MessageEnvelope)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllMessagesInStreamBetween indicates an expected call of GetAllMessagesInStreamBetween
+func (mr *MockRepositoryMockRecorder) GetAllMessagesInStreamBetween(arg0, arg1, arg2, arg3, arg4, arg5 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllMessagesInStreamBetween", reflect.TypeOf((*MockRepository)(nil).GetAllMessagesInStreamBetween), arg0, arg1, arg2, arg3, arg4, arg5)
+}
+
 // GetAllMessagesInStreamSince mocks base method
 func (m *MockRepository) GetAllMessagesInStreamSince(arg0 context.Context, arg1 string, arg2 int64, arg3 int) ([]*repository.MessageEnvelope, error) {
 	m.ctrl.T.Helper()
@@ -94,6 +171,51 @@ func (mr *MockRepositoryMockRecorder) GetAllMessagesInStreamSince(arg0, arg1, ar
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllMessagesInStreamSince", reflect.TypeOf((*MockRepository)(nil).GetAllMessagesInStreamSince), arg0, arg1, arg2, arg3)
 }
 
+// GetAllMessagesInStreamWhere mocks base method
+func (m *MockRepository) GetAllMessagesInStreamWhere(arg0 context.Context, arg1 string, arg2 int64, arg3 repository.Filter, arg4 int) ([]*repository.MessageEnvelope, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllMessagesInStreamWhere", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].([]*repository.MessageEnvelope)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllMessagesInStreamWhere indicates an expected call of GetAllMessagesInStreamWhere
+func (mr *MockRepositoryMockRecorder) GetAllMessagesInStreamWhere(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllMessagesInStreamWhere", reflect.TypeOf((*MockRepository)(nil).GetAllMessagesInStreamWhere), arg0, arg1, arg2, arg3, arg4)
+}
+
+// GetAllMessagesSince mocks base method
+func (m *MockRepository) GetAllMessagesSince(arg0 context.Context, arg1 int64, arg2 int) ([]*repository.MessageEnvelope, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllMessagesSince", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]*repository.MessageEnvelope)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllMessagesSince indicates an expected call of GetAllMessagesSince
+func (mr *MockRepositoryMockRecorder) GetAllMessagesSince(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllMessagesSince", reflect.TypeOf((*MockRepository)(nil).GetAllMessagesSince), arg0, arg1, arg2)
+}
+
+// GetLastMessage mocks base method
+func (m *MockRepository) GetLastMessage(arg0 context.Context) (*repository.MessageEnvelope, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLastMessage", arg0)
+	ret0, _ := ret[0].(*repository.MessageEnvelope)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLastMessage indicates an expected call of GetLastMessage
+func (mr *MockRepositoryMockRecorder) GetLastMessage(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLastMessage", reflect.TypeOf((*MockRepository)(nil).GetLastMessage), arg0)
+}
+
 // GetLastMessageInStream mocks base method
 func (m *MockRepository) GetLastMessageInStream(arg0 context.Context, arg1 string) (*repository.MessageEnvelope, error) {
 	m.ctrl.T.Helper()
@@ -109,6 +231,51 @@ func (mr *MockRepositoryMockRecorder) GetLastMessageInStream(arg0, arg1 interfac
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLastMessageInStream", reflect.TypeOf((*MockRepository)(nil).GetLastMessageInStream), arg0, arg1)
 }
 
+// GetMessageByID mocks base method
+func (m *MockRepository) GetMessageByID(arg0 context.Context, arg1 uuid.UUID) (*repository.MessageEnvelope, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMessageByID", arg0, arg1)
+	ret0, _ := ret[0].(*repository.MessageEnvelope)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMessageByID indicates an expected call of GetMessageByID
+func (mr *MockRepositoryMockRecorder) GetMessageByID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMessageByID", reflect.TypeOf((*MockRepository)(nil).GetMessageByID), arg0, arg1)
+}
+
+// GetMessagesInCategoryBackward mocks base method
+func (m *MockRepository) GetMessagesInCategoryBackward(arg0 context.Context, arg1 string, arg2 int64, arg3 int) ([]*repository.MessageEnvelope, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMessagesInCategoryBackward", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]*repository.MessageEnvelope)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMessagesInCategoryBackward indicates an expected call of GetMessagesInCategoryBackward
+func (mr *MockRepositoryMockRecorder) GetMessagesInCategoryBackward(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMessagesInCategoryBackward", reflect.TypeOf((*MockRepository)(nil).GetMessagesInCategoryBackward), arg0, arg1, arg2, arg3)
+}
+
+// GetMessagesInStreamBackward mocks base method
+func (m *MockRepository) GetMessagesInStreamBackward(arg0 context.Context, arg1 string, arg2 int64, arg3 int) ([]*repository.MessageEnvelope, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMessagesInStreamBackward", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]*repository.MessageEnvelope)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMessagesInStreamBackward indicates an expected call of GetMessagesInStreamBackward
+func (mr *MockRepositoryMockRecorder) GetMessagesInStreamBackward(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMessagesInStreamBackward", reflect.TypeOf((*MockRepository)(nil).GetMessagesInStreamBackward), arg0, arg1, arg2, arg3)
+}
+
 // WriteMessage mocks base method
 func (m *MockRepository) WriteMessage(arg0 context.Context, arg1 *repository.MessageEnvelope) error {
 	m.ctrl.T.Helper()
@@ -136,3 +303,17 @@ func (mr *MockRepositoryMockRecorder) WriteMessageWithExpectedPosition(arg0, arg
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WriteMessageWithExpectedPosition", reflect.TypeOf((*MockRepository)(nil).WriteMessageWithExpectedPosition), arg0, arg1, arg2)
 }
+
+// WriteMessagesWithExpectedPosition mocks base method
+func (m *MockRepository) WriteMessagesWithExpectedPosition(arg0 context.Context, arg1 []*repository.MessageEnvelope, arg2 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WriteMessagesWithExpectedPosition", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WriteMessagesWithExpectedPosition indicates an expected call of WriteMessagesWithExpectedPosition
+func (mr *MockRepositoryMockRecorder) WriteMessagesWithExpectedPosition(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WriteMessagesWithExpectedPosition", reflect.TypeOf((*MockRepository)(nil).WriteMessagesWithExpectedPosition), arg0, arg1, arg2)
+}
@@ -11,12 +11,13 @@ import (
 func NewPostgresRepository(db *sql.DB, log logrus.FieldLogger) Repository {
 	r := new(postgresRepo)
 	r.dbx = sqlx.NewDb(db, "postgres")
+	r.log = log
 	return r
 }
 
 type postgresRepo struct {
 	dbx *sqlx.DB
-	log logrus.Logger
+	log logrus.FieldLogger
 }
 
 type returnPair struct {
@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/blackhatbrigade/gomessagestore/streamname"
+	"github.com/sirupsen/logrus"
+)
+
+// notPositionStream leaves subscribers' position streams out of reads across every stream, so a subscriber to every stream doesn't read the positions it writes
+var notPositionStream = "stream_name NOT LIKE '%" + streamname.Compound("", streamname.PositionType) + "'"
+
+func (r postgresRepo) GetAllMessagesSince(ctx context.Context, globalPosition int64, batchSize int) (m []*MessageEnvelope, err error) {
+	if batchSize < 0 {
+		logrus.WithError(ErrNegativeBatchSize).Error("Failure in repo_postgres.go::GetAllMessagesSince")
+
+		return nil, ErrNegativeBatchSize
+	}
+
+	// our return channel for our goroutine that will either finish or be cancelled
+	retChan := make(chan returnPair, 1)
+	go func() {
+		// last thing we do is ensure our return channel is populated
+		defer func() {
+			retChan <- returnPair{nil, nil}
+		}()
+
+		var msgs []*MessageEnvelope
+		// message db has no function for reading every stream, so read the messages table directly in global order
+		query := "SELECT id, stream_name, category(stream_name) AS stream_category, type, position, global_position, data, metadata, time FROM messages WHERE global_position >= $1 AND " + notPositionStream + " ORDER BY global_position LIMIT $2"
+		if err := r.dbx.SelectContext(ctx, &msgs, query, globalPosition, batchSize); err != nil {
+			logrus.WithError(err).Error("Failure in repo_postgres.go::GetAllMessagesSince")
+			retChan <- returnPair{nil, err}
+			return
+		}
+
+		if len(msgs) == 0 {
+			retChan <- returnPair{[]*MessageEnvelope{}, nil}
+			return
+		}
+
+		retChan <- returnPair{msgs, nil}
+	}()
+
+	// wait for our return channel or the context to cancel
+	select {
+	case retval := <-retChan:
+		return retval.messages, retval.err
+	case <-ctx.Done():
+		return []*MessageEnvelope{}, nil
+	}
+}
+
+func (r postgresRepo) GetLastMessage(ctx context.Context) (*MessageEnvelope, error) {
+	// our return channel for our goroutine that will either finish or be cancelled
+	retChan := make(chan returnPair, 1)
+	go func() {
+		// last thing we do is ensure our return channel is populated
+		defer func() {
+			retChan <- returnPair{nil, nil}
+		}()
+
+		var msgs []*MessageEnvelope
+		query := "SELECT id, stream_name, category(stream_name) AS stream_category, type, position, global_position, data, metadata, time FROM messages WHERE " + notPositionStream + " ORDER BY global_position DESC LIMIT 1"
+		if err := r.dbx.SelectContext(ctx, &msgs, query); err != nil {
+			logrus.WithError(err).Error("Failure in repo_postgres.go::GetLastMessage")
+			retChan <- returnPair{nil, err}
+			return
+		}
+
+		retChan <- returnPair{msgs, nil}
+	}()
+
+	// wait for our return channel or the context to cancel
+	select {
+	case retval := <-retChan:
+		if retval.err != nil {
+			return nil, retval.err
+		} else if len(retval.messages) > 0 {
+			return retval.messages[0], nil
+		}
+		return nil, nil
+	case <-ctx.Done():
+		return nil, nil
+	}
+}
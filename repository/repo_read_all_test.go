@@ -0,0 +1,152 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	. "github.com/blackhatbrigade/gomessagestore/repository"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPostgresRepoFindAllMessagesSince(t *testing.T) {
+	tests := []struct {
+		name             string
+		dbError          error
+		existingMessages []*MessageEnvelope
+		expectedMessages []*MessageEnvelope
+		expectedErr      error
+		callCancel       bool
+		position         int64
+		batchSize        int
+	}{{
+		name:             "when there are existing messages it should return all of them in global order",
+		existingMessages: mockMessages,
+		expectedMessages: mockMessages,
+		batchSize:        1000,
+	}, {
+		name:             "when there are existing messages past position 5 it should return them",
+		existingMessages: mockMessages,
+		expectedMessages: mockMessages[2:],
+		position:         5,
+		batchSize:        1000,
+	}, {
+		name:             "when there are no existing messages past position 10 it should return no messages",
+		existingMessages: mockMessages,
+		expectedMessages: []*MessageEnvelope{},
+		position:         10,
+		batchSize:        1000,
+	}, {
+		name:             "when there are no existing messages it should return no messages",
+		expectedMessages: []*MessageEnvelope{},
+		batchSize:        1000,
+	}, {
+		name:        "when asking for messages with a negative batch size, an error is returned",
+		expectedErr: ErrNegativeBatchSize,
+		batchSize:   -10,
+	}, {
+		name:        "when there is an issue getting the messages an error should be returned",
+		dbError:     errors.New("bad things with db happened"),
+		expectedErr: errors.New("bad things with db happened"),
+		batchSize:   1000,
+	}, {
+		name:             "when it is asked to cancel, it does",
+		existingMessages: mockMessages,
+		callCancel:       true,
+		expectedMessages: []*MessageEnvelope{},
+		batchSize:        1000,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+			db, mockDb, _ := sqlmock.New()
+			logrusLogger := logrus.New()
+			repo := NewPostgresRepository(db, logrusLogger)
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel() // free all resources
+
+			expectedQuery := mockDb.
+				ExpectQuery("SELECT .* FROM messages WHERE global_position >= \\$1 AND stream_name NOT LIKE '%\\+position' ORDER BY global_position LIMIT \\$2").
+				WithArgs(test.position, test.batchSize).
+				WillDelayFor(time.Millisecond * 10)
+
+			if test.dbError == nil {
+				rows := sqlmock.NewRows([]string{"id", "stream_name", "stream_category", "type", "position", "global_position", "data", "metadata", "time"})
+				for _, row := range test.existingMessages {
+					if row.GlobalPosition >= test.position {
+						rows.AddRow(
+							row.ID, row.StreamName, row.StreamCategory, row.MessageType, row.Version, row.GlobalPosition, row.Data, row.Metadata, row.Time,
+						)
+					}
+				}
+
+				expectedQuery.WillReturnRows(rows)
+			} else {
+				expectedQuery.WillReturnError(test.dbError)
+			}
+
+			if test.callCancel {
+				time.AfterFunc(time.Millisecond*5, cancel) // after the call to the DB, but before it finishes
+			}
+			messages, err := repo.GetAllMessagesSince(ctx, test.position, test.batchSize)
+
+			assert.Equal(test.expectedErr, err)
+			assert.Equal(test.expectedMessages, messages)
+		})
+	}
+}
+
+func TestPostgresRepoFindLastMessage(t *testing.T) {
+	tests := []struct {
+		name            string
+		dbError         error
+		existingMessage *MessageEnvelope
+		expectedMessage *MessageEnvelope
+		expectedErr     error
+	}{{
+		name:            "when there are existing messages it should return the last one",
+		existingMessage: mockMessages[4],
+		expectedMessage: mockMessages[4],
+	}, {
+		name: "when there are no existing messages it should return nothing",
+	}, {
+		name:        "when there is an issue getting the message an error should be returned",
+		dbError:     errors.New("bad things with db happened"),
+		expectedErr: errors.New("bad things with db happened"),
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+			db, mockDb, _ := sqlmock.New()
+			logrusLogger := logrus.New()
+			repo := NewPostgresRepository(db, logrusLogger)
+			ctx := context.Background()
+
+			expectedQuery := mockDb.
+				ExpectQuery("SELECT .* FROM messages WHERE stream_name NOT LIKE '%\\+position' ORDER BY global_position DESC LIMIT 1")
+
+			if test.dbError == nil {
+				rows := sqlmock.NewRows([]string{"id", "stream_name", "stream_category", "type", "position", "global_position", "data", "metadata", "time"})
+				if row := test.existingMessage; row != nil {
+					rows.AddRow(
+						row.ID, row.StreamName, row.StreamCategory, row.MessageType, row.Version, row.GlobalPosition, row.Data, row.Metadata, row.Time,
+					)
+				}
+
+				expectedQuery.WillReturnRows(rows)
+			} else {
+				expectedQuery.WillReturnError(test.dbError)
+			}
+
+			message, err := repo.GetLastMessage(ctx)
+
+			assert.Equal(test.expectedErr, err)
+			assert.Equal(test.expectedMessage, message)
+		})
+	}
+}
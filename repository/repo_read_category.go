@@ -2,8 +2,10 @@ package repository
 
 import (
 	"context"
+	"fmt"
 	"strings"
 
+	"github.com/blackhatbrigade/gomessagestore/streamname"
 	"github.com/sirupsen/logrus"
 )
 
@@ -22,7 +24,7 @@ func (r postgresRepo) GetAllMessagesInCategorySince(ctx context.Context, categor
 
 		return nil, ErrNegativeBatchSize
 	}
-	if strings.Contains(category, "-") {
+	if !streamname.IsCategory(category) {
 		logrus.WithError(ErrInvalidCategory).Error("Failure in repo_postgres.go::GetAllMessagesInCategorySince")
 		return nil, ErrInvalidCategory
 	}
@@ -66,3 +68,123 @@ func (r postgresRepo) GetAllMessagesInCategorySince(ctx context.Context, categor
 		return []*MessageEnvelope{}, nil
 	}
 }
+
+func (r postgresRepo) GetMessagesInCategoryBackward(ctx context.Context, category string, beforePosition int64, batchSize int) (m []*MessageEnvelope, err error) {
+	if category == "" {
+		logrus.WithError(ErrBlankCategory).Error("Failure in repo_postgres.go::GetMessagesInCategoryBackward")
+
+		return nil, ErrBlankCategory
+	}
+	if batchSize < 0 {
+		logrus.WithError(ErrNegativeBatchSize).Error("Failure in repo_postgres.go::GetMessagesInCategoryBackward")
+
+		return nil, ErrNegativeBatchSize
+	}
+	if !streamname.IsCategory(category) {
+		logrus.WithError(ErrInvalidCategory).Error("Failure in repo_postgres.go::GetMessagesInCategoryBackward")
+		return nil, ErrInvalidCategory
+	}
+
+	// get_category_messages only reads forward, so read the messages table directly, newest first
+	query := "SELECT id, stream_name, category(stream_name) AS stream_category, type, position, global_position, data, metadata, time FROM messages WHERE category(stream_name) = $1 ORDER BY global_position DESC LIMIT $2"
+	args := []interface{}{category, batchSize}
+	if beforePosition >= 0 {
+		query = "SELECT id, stream_name, category(stream_name) AS stream_category, type, position, global_position, data, metadata, time FROM messages WHERE category(stream_name) = $1 AND global_position < $3 ORDER BY global_position DESC LIMIT $2"
+		args = append(args, beforePosition)
+	}
+
+	// our return channel for our goroutine that will either finish or be cancelled
+	retChan := make(chan returnPair, 1)
+	go func() {
+		// last thing we do is ensure our return channel is populated
+		defer func() {
+			retChan <- returnPair{nil, nil}
+		}()
+
+		var msgs []*MessageEnvelope
+		if err := r.dbx.SelectContext(ctx, &msgs, query, args...); err != nil {
+			logrus.WithError(err).Error("Failure in repo_postgres.go::GetMessagesInCategoryBackward")
+			retChan <- returnPair{nil, err}
+			return
+		}
+
+		if len(msgs) == 0 {
+			retChan <- returnPair{[]*MessageEnvelope{}, nil}
+			return
+		}
+
+		retChan <- returnPair{msgs, nil}
+	}()
+
+	// wait for our return channel or the context to cancel
+	select {
+	case retval := <-retChan:
+		return retval.messages, retval.err
+	case <-ctx.Done():
+		return []*MessageEnvelope{}, nil
+	}
+}
+
+func (r postgresRepo) GetAllMessagesInCategoriesSince(ctx context.Context, categories []string, globalPosition int64, batchSize int) (m []*MessageEnvelope, err error) {
+	if len(categories) == 0 {
+		logrus.WithError(ErrBlankCategory).Error("Failure in repo_postgres.go::GetAllMessagesInCategoriesSince")
+
+		return nil, ErrBlankCategory
+	}
+	if batchSize < 0 {
+		logrus.WithError(ErrNegativeBatchSize).Error("Failure in repo_postgres.go::GetAllMessagesInCategoriesSince")
+
+		return nil, ErrNegativeBatchSize
+	}
+
+	// get_category_messages only reads a single category, so query the messages table directly and let postgres merge the categories in global order
+	args := []interface{}{globalPosition, batchSize}
+	placeholders := make([]string, len(categories))
+	for i, category := range categories {
+		if category == "" {
+			logrus.WithError(ErrBlankCategory).Error("Failure in repo_postgres.go::GetAllMessagesInCategoriesSince")
+			return nil, ErrBlankCategory
+		}
+		if !streamname.IsCategory(category) {
+			logrus.WithError(ErrInvalidCategory).Error("Failure in repo_postgres.go::GetAllMessagesInCategoriesSince")
+			return nil, ErrInvalidCategory
+		}
+		args = append(args, category)
+		placeholders[i] = fmt.Sprintf("$%d", i+3)
+	}
+
+	// our return channel for our goroutine that will either finish or be cancelled
+	retChan := make(chan returnPair, 1)
+	go func() {
+		// last thing we do is ensure our return channel is populated
+		defer func() {
+			retChan <- returnPair{nil, nil}
+		}()
+
+		var msgs []*MessageEnvelope
+		query := fmt.Sprintf(
+			"SELECT id, stream_name, category(stream_name) AS stream_category, type, position, global_position, data, metadata, time FROM messages WHERE global_position >= $1 AND category(stream_name) IN (%s) ORDER BY global_position LIMIT $2",
+			strings.Join(placeholders, ", "),
+		)
+		if err := r.dbx.SelectContext(ctx, &msgs, query, args...); err != nil {
+			logrus.WithError(err).Error("Failure in repo_postgres.go::GetAllMessagesInCategoriesSince")
+			retChan <- returnPair{nil, err}
+			return
+		}
+
+		if len(msgs) == 0 {
+			retChan <- returnPair{[]*MessageEnvelope{}, nil}
+			return
+		}
+
+		retChan <- returnPair{msgs, nil}
+	}()
+
+	// wait for our return channel or the context to cancel
+	select {
+	case retval := <-retChan:
+		return retval.messages, retval.err
+	case <-ctx.Done():
+		return []*MessageEnvelope{}, nil
+	}
+}
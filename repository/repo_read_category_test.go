@@ -2,7 +2,10 @@ package repository_test
 
 import (
 	"context"
+	"database/sql/driver"
 	"errors"
+	"fmt"
+	"strings"
 	"testing"
 	"time"
 
@@ -242,3 +245,229 @@ func TestPostgresRepoFindAllMessagesInCategorySince(t *testing.T) {
 		})
 	}
 }
+
+func TestPostgresRepoFindAllMessagesInCategoriesSince(t *testing.T) {
+	tests := []struct {
+		name             string
+		dbError          error
+		existingMessages []*MessageEnvelope
+		expectedMessages []*MessageEnvelope
+		expectedErr      error
+		categories       []string
+		callCancel       bool
+		position         int64
+		batchSize        int
+	}{{
+		name:             "when there are existing messages in several categories it should return them in global order",
+		existingMessages: mockMessages,
+		categories:       []string{"other_type", "some_other_type"},
+		expectedMessages: copyAndAppend(mockMessages[:3], mockMessages[4:]...),
+		batchSize:        1000,
+	}, {
+		name:             "when there are existing messages past position 5 it should return them",
+		existingMessages: mockMessages,
+		categories:       []string{"other_type", "some_other_type"},
+		expectedMessages: copyAndAppend(mockMessages[2:3], mockMessages[4:]...),
+		position:         5,
+		batchSize:        1000,
+	}, {
+		name:             "when there are no messages in my categories it should return no messages",
+		existingMessages: mockMessages,
+		categories:       []string{"some_non_existant_type", "another_non_existant_type"},
+		expectedMessages: []*MessageEnvelope{},
+		batchSize:        1000,
+	}, {
+		name:        "when asking for messages from no categories, an error is returned",
+		expectedErr: ErrBlankCategory,
+		batchSize:   1000,
+	}, {
+		name:        "when asking for messages from a blank category, an error is returned",
+		categories:  []string{"other_type", ""},
+		expectedErr: ErrBlankCategory,
+		batchSize:   1000,
+	}, {
+		name:        "when asking for messages from an invalid category, an error is returned",
+		categories:  []string{"other_type", "something-bad"},
+		expectedErr: ErrInvalidCategory,
+		batchSize:   1000,
+	}, {
+		name:        "when asking for messages with a negative batch size, an error is returned",
+		categories:  []string{"other_type", "some_other_type"},
+		expectedErr: ErrNegativeBatchSize,
+		batchSize:   -10,
+	}, {
+		name:        "when there is an issue getting the messages an error should be returned",
+		categories:  []string{"other_type", "some_other_type"},
+		dbError:     errors.New("bad things with db happened"),
+		expectedErr: errors.New("bad things with db happened"),
+		batchSize:   1000,
+	}, {
+		name:             "when it is asked to cancel, it does",
+		existingMessages: mockMessages,
+		categories:       []string{"other_type", "some_other_type"},
+		callCancel:       true,
+		expectedMessages: []*MessageEnvelope{},
+		batchSize:        1000,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+			db, mockDb, _ := sqlmock.New()
+			logrusLogger := logrus.New()
+			repo := NewPostgresRepository(db, logrusLogger)
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel() // free all resources
+
+			args := []driver.Value{test.position, test.batchSize}
+			placeholders := []string{}
+			for i, category := range test.categories {
+				args = append(args, category)
+				placeholders = append(placeholders, fmt.Sprintf("\\$%d", i+3))
+			}
+			expectedQuery := mockDb.
+				ExpectQuery("SELECT .* FROM messages WHERE global_position >= \\$1 AND category\\(stream_name\\) IN \\(" + strings.Join(placeholders, ", ") + "\\) ORDER BY global_position LIMIT \\$2").
+				WithArgs(args...).
+				WillDelayFor(time.Millisecond * 10)
+
+			if test.dbError == nil {
+				rows := sqlmock.NewRows([]string{"id", "stream_name", "stream_category", "type", "position", "global_position", "data", "metadata", "time"})
+				for _, row := range test.existingMessages {
+					for _, category := range test.categories {
+						if row.StreamCategory == category && row.GlobalPosition >= test.position {
+							rows.AddRow(
+								row.ID, row.StreamName, row.StreamCategory, row.MessageType, row.Version, row.GlobalPosition, row.Data, row.Metadata, row.Time,
+							)
+						}
+					}
+				}
+
+				expectedQuery.WillReturnRows(rows)
+			} else {
+				expectedQuery.WillReturnError(test.dbError)
+			}
+
+			if test.callCancel {
+				time.AfterFunc(time.Millisecond*5, cancel) // after the call to the DB, but before it finishes
+			}
+			messages, err := repo.GetAllMessagesInCategoriesSince(ctx, test.categories, test.position, test.batchSize)
+
+			assert.Equal(test.expectedErr, err)
+			assert.Equal(test.expectedMessages, messages)
+		})
+	}
+}
+
+func TestPostgresRepoFindMessagesInCategoryBackward(t *testing.T) {
+	tests := []struct {
+		name             string
+		dbError          error
+		existingMessages []*MessageEnvelope
+		expectedMessages []*MessageEnvelope
+		expectedErr      error
+		streamCategory   string
+		callCancel       bool
+		beforePosition   int64
+		batchSize        int
+	}{{
+		name:             "when there are existing messages it should return them newest first",
+		existingMessages: mockMessages,
+		streamCategory:   "other_type",
+		expectedMessages: []*MessageEnvelope{mockMessages[4], mockMessages[1], mockMessages[0]},
+		beforePosition:   -1,
+		batchSize:        1000,
+	}, {
+		name:             "when there are existing messages before position 5 it should return them",
+		existingMessages: mockMessages,
+		streamCategory:   "other_type",
+		expectedMessages: []*MessageEnvelope{mockMessages[1], mockMessages[0]},
+		beforePosition:   5,
+		batchSize:        1000,
+	}, {
+		name:             "when there are no messages in my category it should return no messages",
+		existingMessages: mockMessages,
+		streamCategory:   "some_non_existant_type",
+		expectedMessages: []*MessageEnvelope{},
+		beforePosition:   -1,
+		batchSize:        1000,
+	}, {
+		name:           "when asking for messages from a blank category, an error is returned",
+		expectedErr:    ErrBlankCategory,
+		beforePosition: -1,
+		batchSize:      1000,
+	}, {
+		name:           "when asking for messages from an invalid category, an error is returned",
+		streamCategory: "something-bad",
+		expectedErr:    ErrInvalidCategory,
+		beforePosition: -1,
+		batchSize:      1000,
+	}, {
+		name:           "when asking for messages with a negative batch size, an error is returned",
+		streamCategory: "other_type",
+		expectedErr:    ErrNegativeBatchSize,
+		beforePosition: -1,
+		batchSize:      -10,
+	}, {
+		name:           "when there is an issue getting the messages an error should be returned",
+		streamCategory: "other_type",
+		dbError:        errors.New("bad things with db happened"),
+		expectedErr:    errors.New("bad things with db happened"),
+		beforePosition: -1,
+		batchSize:      1000,
+	}, {
+		name:             "when it is asked to cancel, it does",
+		existingMessages: mockMessages,
+		streamCategory:   "other_type",
+		callCancel:       true,
+		expectedMessages: []*MessageEnvelope{},
+		beforePosition:   -1,
+		batchSize:        1000,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+			db, mockDb, _ := sqlmock.New()
+			logrusLogger := logrus.New()
+			repo := NewPostgresRepository(db, logrusLogger)
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel() // free all resources
+
+			var expectedQuery *sqlmock.ExpectedQuery
+			if test.beforePosition < 0 {
+				expectedQuery = mockDb.
+					ExpectQuery("SELECT .* FROM messages WHERE category\\(stream_name\\) = \\$1 ORDER BY global_position DESC LIMIT \\$2").
+					WithArgs(test.streamCategory, test.batchSize)
+			} else {
+				expectedQuery = mockDb.
+					ExpectQuery("SELECT .* FROM messages WHERE category\\(stream_name\\) = \\$1 AND global_position < \\$3 ORDER BY global_position DESC LIMIT \\$2").
+					WithArgs(test.streamCategory, test.batchSize, test.beforePosition)
+			}
+			expectedQuery.WillDelayFor(time.Millisecond * 10)
+
+			if test.dbError == nil {
+				rows := sqlmock.NewRows([]string{"id", "stream_name", "stream_category", "type", "position", "global_position", "data", "metadata", "time"})
+				for i := len(test.existingMessages) - 1; i >= 0; i-- {
+					row := test.existingMessages[i]
+					if row.StreamCategory == test.streamCategory && (test.beforePosition < 0 || row.GlobalPosition < test.beforePosition) {
+						rows.AddRow(
+							row.ID, row.StreamName, row.StreamCategory, row.MessageType, row.Version, row.GlobalPosition, row.Data, row.Metadata, row.Time,
+						)
+					}
+				}
+
+				expectedQuery.WillReturnRows(rows)
+			} else {
+				expectedQuery.WillReturnError(test.dbError)
+			}
+
+			if test.callCancel {
+				time.AfterFunc(time.Millisecond*5, cancel) // after the call to the DB, but before it finishes
+			}
+			messages, err := repo.GetMessagesInCategoryBackward(ctx, test.streamCategory, test.beforePosition, test.batchSize)
+
+			assert.Equal(test.expectedErr, err)
+			assert.Equal(test.expectedMessages, messages)
+		})
+	}
+}
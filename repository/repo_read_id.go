@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/blackhatbrigade/gomessagestore/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+func (r postgresRepo) GetMessageByID(ctx context.Context, id uuid.UUID) (*MessageEnvelope, error) {
+	if id == uuid.Nil {
+		logrus.WithError(ErrBlankMessageID).Error("Failure in repo_postgres.go::GetMessageByID")
+
+		return nil, ErrBlankMessageID
+	}
+
+	// our return channel for our goroutine that will either finish or be cancelled
+	retChan := make(chan returnPair, 1)
+	go func() {
+		// last thing we do is ensure our return channel is populated
+		defer func() {
+			retChan <- returnPair{nil, nil}
+		}()
+
+		var msgs []*MessageEnvelope
+		// message db has no function for reading by id, so read the messages table directly
+		query := "SELECT id, stream_name, category(stream_name) AS stream_category, type, position, global_position, data, metadata, time FROM messages WHERE id = $1"
+		if err := r.dbx.SelectContext(ctx, &msgs, query, id); err != nil {
+			logrus.WithError(err).Error("Failure in repo_postgres.go::GetMessageByID")
+			retChan <- returnPair{nil, err}
+			return
+		}
+
+		retChan <- returnPair{msgs, nil}
+	}()
+
+	// wait for our return channel or the context to cancel
+	select {
+	case retval := <-retChan:
+		if retval.err != nil {
+			return nil, retval.err
+		} else if len(retval.messages) > 0 {
+			return retval.messages[0], nil
+		}
+		return nil, nil
+	case <-ctx.Done():
+		return nil, nil
+	}
+}
@@ -0,0 +1,85 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	. "github.com/blackhatbrigade/gomessagestore/repository"
+	"github.com/blackhatbrigade/gomessagestore/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPostgresRepoGetMessageByID(t *testing.T) {
+	tests := []struct {
+		name            string
+		dbError         error
+		id              uuid.UUID
+		existingMessage *MessageEnvelope
+		expectedMessage *MessageEnvelope
+		expectedErr     error
+		callCancel      bool
+	}{{
+		name:            "when the message exists it should return it",
+		id:              uuid3,
+		existingMessage: mockMessages[2],
+		expectedMessage: mockMessages[2],
+	}, {
+		name: "when the message does not exist it should return nothing",
+		id:   uuid3,
+	}, {
+		name:        "when asking for a blank ID, an error is returned",
+		id:          uuid.Nil,
+		expectedErr: ErrBlankMessageID,
+	}, {
+		name:        "when there is an issue getting the message an error should be returned",
+		id:          uuid3,
+		dbError:     errors.New("bad things with db happened"),
+		expectedErr: errors.New("bad things with db happened"),
+	}, {
+		name:            "when it is asked to cancel, it does",
+		id:              uuid3,
+		existingMessage: mockMessages[2],
+		callCancel:      true,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+			db, mockDb, _ := sqlmock.New()
+			logrusLogger := logrus.New()
+			repo := NewPostgresRepository(db, logrusLogger)
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel() // free all resources
+
+			expectedQuery := mockDb.
+				ExpectQuery("SELECT .* FROM messages WHERE id = \\$1").
+				WithArgs(test.id).
+				WillDelayFor(time.Millisecond * 10)
+
+			if test.dbError == nil {
+				rows := sqlmock.NewRows([]string{"id", "stream_name", "stream_category", "type", "position", "global_position", "data", "metadata", "time"})
+				if row := test.existingMessage; row != nil {
+					rows.AddRow(
+						row.ID, row.StreamName, row.StreamCategory, row.MessageType, row.Version, row.GlobalPosition, row.Data, row.Metadata, row.Time,
+					)
+				}
+
+				expectedQuery.WillReturnRows(rows)
+			} else {
+				expectedQuery.WillReturnError(test.dbError)
+			}
+
+			if test.callCancel {
+				time.AfterFunc(time.Millisecond*5, cancel) // after the call to the DB, but before it finishes
+			}
+			message, err := repo.GetMessageByID(ctx, test.id)
+
+			assert.Equal(test.expectedErr, err)
+			assert.Equal(test.expectedMessage, message)
+		})
+	}
+}
@@ -108,3 +108,55 @@ func (r postgresRepo) GetAllMessagesInStreamSince(ctx context.Context, streamNam
 		return []*MessageEnvelope{}, nil
 	}
 }
+
+func (r postgresRepo) GetMessagesInStreamBackward(ctx context.Context, streamName string, beforeVersion int64, batchSize int) ([]*MessageEnvelope, error) {
+	if streamName == "" {
+		logrus.WithError(ErrInvalidStreamName).Error("Failure in repo_postgres.go::GetMessagesInStreamBackward")
+
+		return nil, ErrInvalidStreamName
+	}
+	if batchSize < 0 {
+		logrus.WithError(ErrNegativeBatchSize).Error("Failure in repo_postgres.go::GetMessagesInStreamBackward")
+
+		return nil, ErrNegativeBatchSize
+	}
+
+	// message db functions only read forward, so read the messages table directly, newest first
+	query := "SELECT id, stream_name, category(stream_name) AS stream_category, type, position, global_position, data, metadata, time FROM messages WHERE stream_name = $1 ORDER BY position DESC LIMIT $2"
+	args := []interface{}{streamName, batchSize}
+	if beforeVersion >= 0 {
+		query = "SELECT id, stream_name, category(stream_name) AS stream_category, type, position, global_position, data, metadata, time FROM messages WHERE stream_name = $1 AND position < $3 ORDER BY position DESC LIMIT $2"
+		args = append(args, beforeVersion)
+	}
+
+	// our return channel for our goroutine that will either finish or be cancelled
+	retChan := make(chan returnPair, 1)
+	go func() {
+		// last thing we do is ensure our return channel is populated
+		defer func() {
+			retChan <- returnPair{nil, nil}
+		}()
+
+		var msgs []*MessageEnvelope
+		if err := r.dbx.SelectContext(ctx, &msgs, query, args...); err != nil {
+			logrus.WithError(err).Error("Failure in repo_postgres.go::GetMessagesInStreamBackward")
+			retChan <- returnPair{nil, err}
+			return
+		}
+
+		if len(msgs) == 0 {
+			retChan <- returnPair{[]*MessageEnvelope{}, nil}
+			return
+		}
+
+		retChan <- returnPair{msgs, nil}
+	}()
+
+	// wait for our return channel or the context to cancel
+	select {
+	case retval := <-retChan:
+		return retval.messages, retval.err
+	case <-ctx.Done():
+		return []*MessageEnvelope{}, nil
+	}
+}
@@ -322,3 +322,118 @@ func TestPostgresRepoFindLastMessageInStream(t *testing.T) {
 		})
 	}
 }
+
+func TestPostgresRepoFindMessagesInStreamBackward(t *testing.T) {
+	tests := []struct {
+		name             string
+		dbError          error
+		existingMessages []*MessageEnvelope
+		expectedMessages []*MessageEnvelope
+		expectedErr      error
+		streamName       string
+		callCancel       bool
+		beforeVersion    int64
+		batchSize        int
+	}{{
+		name:             "when there are existing messages it should return them newest first",
+		existingMessages: mockMessages,
+		streamName:       "some_type-12345",
+		expectedMessages: []*MessageEnvelope{mockMessages[4], mockMessages[0]},
+		beforeVersion:    -1,
+		batchSize:        1000,
+	}, {
+		name:             "when there are existing messages before version 1 it should return them",
+		existingMessages: mockMessages,
+		streamName:       "some_type-12345",
+		expectedMessages: []*MessageEnvelope{mockMessages[0]},
+		beforeVersion:    1,
+		batchSize:        1000,
+	}, {
+		name:             "when there are no existing messages before version 0 it should return no messages",
+		existingMessages: mockMessages,
+		streamName:       "some_type-12345",
+		expectedMessages: []*MessageEnvelope{},
+		beforeVersion:    0,
+		batchSize:        1000,
+	}, {
+		name:             "when there are no messages in my stream it should return no messages",
+		existingMessages: mockMessages,
+		streamName:       "some_non_existant_type-12345",
+		expectedMessages: []*MessageEnvelope{},
+		beforeVersion:    -1,
+		batchSize:        1000,
+	}, {
+		name:          "when asking for messages from a blank stream, an error is returned",
+		expectedErr:   ErrInvalidStreamName,
+		beforeVersion: -1,
+		batchSize:     1000,
+	}, {
+		name:          "when asking for messages with a negative batch size, an error is returned",
+		streamName:    "some_type-12345",
+		expectedErr:   ErrNegativeBatchSize,
+		beforeVersion: -1,
+		batchSize:     -10,
+	}, {
+		name:          "when there is an issue getting the messages an error should be returned",
+		streamName:    "some_type-12345",
+		dbError:       errors.New("bad things with db happened"),
+		expectedErr:   errors.New("bad things with db happened"),
+		beforeVersion: -1,
+		batchSize:     1000,
+	}, {
+		name:             "when it is asked to cancel, it does",
+		existingMessages: mockMessages,
+		streamName:       "some_type-12345",
+		callCancel:       true,
+		expectedMessages: []*MessageEnvelope{},
+		beforeVersion:    -1,
+		batchSize:        1000,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+			db, mockDb, _ := sqlmock.New()
+			logrusLogger := logrus.New()
+			repo := NewPostgresRepository(db, logrusLogger)
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel() // free all resources
+
+			var expectedQuery *sqlmock.ExpectedQuery
+			if test.beforeVersion < 0 {
+				expectedQuery = mockDb.
+					ExpectQuery("SELECT .* FROM messages WHERE stream_name = \\$1 ORDER BY position DESC LIMIT \\$2").
+					WithArgs(test.streamName, test.batchSize)
+			} else {
+				expectedQuery = mockDb.
+					ExpectQuery("SELECT .* FROM messages WHERE stream_name = \\$1 AND position < \\$3 ORDER BY position DESC LIMIT \\$2").
+					WithArgs(test.streamName, test.batchSize, test.beforeVersion)
+			}
+			expectedQuery.WillDelayFor(time.Millisecond * 10)
+
+			if test.dbError == nil {
+				rows := sqlmock.NewRows([]string{"id", "stream_name", "stream_category", "type", "position", "global_position", "data", "metadata", "time"})
+				for i := len(test.existingMessages) - 1; i >= 0; i-- {
+					row := test.existingMessages[i]
+					if row.StreamName == test.streamName && (test.beforeVersion < 0 || row.Version < test.beforeVersion) {
+						rows.AddRow(
+							row.ID, row.StreamName, row.StreamCategory, row.MessageType, row.Version, row.GlobalPosition, row.Data, row.Metadata, row.Time,
+						)
+					}
+				}
+
+				expectedQuery.WillReturnRows(rows)
+			} else {
+				expectedQuery.WillReturnError(test.dbError)
+			}
+
+			if test.callCancel {
+				time.AfterFunc(time.Millisecond*5, cancel) // after the call to the DB, but before it finishes
+			}
+			messages, err := repo.GetMessagesInStreamBackward(ctx, test.streamName, test.beforeVersion, test.batchSize)
+
+			assert.Equal(test.expectedErr, err)
+			assert.Equal(test.expectedMessages, messages)
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/blackhatbrigade/gomessagestore/streamname"
+	"github.com/sirupsen/logrus"
+)
+
+func (r postgresRepo) GetAllMessagesInStreamBetween(ctx context.Context, streamName string, version int64, since, until time.Time, batchSize int) ([]*MessageEnvelope, error) {
+	if streamName == "" {
+		logrus.WithError(ErrInvalidStreamName).Error("Failure in repo_postgres.go::GetAllMessagesInStreamBetween")
+
+		return nil, ErrInvalidStreamName
+	}
+	if batchSize < 0 {
+		logrus.WithError(ErrNegativeBatchSize).Error("Failure in repo_postgres.go::GetAllMessagesInStreamBetween")
+
+		return nil, ErrNegativeBatchSize
+	}
+
+	query, args := timeRangeQuery("stream_name = $1 AND position >= $2", "position", []interface{}{streamName, version}, since, until, batchSize)
+
+	return r.selectBetween(ctx, "GetAllMessagesInStreamBetween", query, args)
+}
+
+func (r postgresRepo) GetAllMessagesInCategoryBetween(ctx context.Context, category string, globalPosition int64, since, until time.Time, batchSize int) ([]*MessageEnvelope, error) {
+	if category == "" {
+		logrus.WithError(ErrBlankCategory).Error("Failure in repo_postgres.go::GetAllMessagesInCategoryBetween")
+
+		return nil, ErrBlankCategory
+	}
+	if batchSize < 0 {
+		logrus.WithError(ErrNegativeBatchSize).Error("Failure in repo_postgres.go::GetAllMessagesInCategoryBetween")
+
+		return nil, ErrNegativeBatchSize
+	}
+	if !streamname.IsCategory(category) {
+		logrus.WithError(ErrInvalidCategory).Error("Failure in repo_postgres.go::GetAllMessagesInCategoryBetween")
+		return nil, ErrInvalidCategory
+	}
+
+	query, args := timeRangeQuery("category(stream_name) = $1 AND global_position >= $2", "global_position", []interface{}{category, globalPosition}, since, until, batchSize)
+
+	return r.selectBetween(ctx, "GetAllMessagesInCategoryBetween", query, args)
+}
+
+func (r postgresRepo) GetAllMessagesBetween(ctx context.Context, globalPosition int64, since, until time.Time, batchSize int) ([]*MessageEnvelope, error) {
+	if batchSize < 0 {
+		logrus.WithError(ErrNegativeBatchSize).Error("Failure in repo_postgres.go::GetAllMessagesBetween")
+
+		return nil, ErrNegativeBatchSize
+	}
+
+	query, args := timeRangeQuery("global_position >= $1 AND "+notPositionStream, "global_position", []interface{}{globalPosition}, since, until, batchSize)
+
+	return r.selectBetween(ctx, "GetAllMessagesBetween", query, args)
+}
+
+// timeRangeQuery adds the time range and batch size to a query against the messages table, so postgres does the filtering; message db's functions can't filter on time without sql conditions enabled
+func timeRangeQuery(where string, orderBy string, args []interface{}, since, until time.Time, batchSize int) (string, []interface{}) {
+	conditions := []string{where}
+	if !since.IsZero() {
+		args = append(args, since.UTC()) // message db stores time in utc without a time zone
+		conditions = append(conditions, fmt.Sprintf("time >= $%d", len(args)))
+	}
+	if !until.IsZero() {
+		args = append(args, until.UTC())
+		conditions = append(conditions, fmt.Sprintf("time < $%d", len(args)))
+	}
+	args = append(args, batchSize)
+
+	query := fmt.Sprintf(
+		"SELECT id, stream_name, category(stream_name) AS stream_category, type, position, global_position, data, metadata, time FROM messages WHERE %s ORDER BY %s LIMIT $%d",
+		strings.Join(conditions, " AND "),
+		orderBy,
+		len(args),
+	)
+
+	return query, args
+}
+
+// selectBetween runs a filtered query, stopping early if the context is cancelled
+func (r postgresRepo) selectBetween(ctx context.Context, fnName string, query string, args []interface{}) ([]*MessageEnvelope, error) {
+	// our return channel for our goroutine that will either finish or be cancelled
+	retChan := make(chan returnPair, 1)
+	go func() {
+		// last thing we do is ensure our return channel is populated
+		defer func() {
+			retChan <- returnPair{nil, nil}
+		}()
+
+		var msgs []*MessageEnvelope
+		if err := r.dbx.SelectContext(ctx, &msgs, query, args...); err != nil {
+			logrus.WithError(err).Error("Failure in repo_postgres.go::" + fnName)
+			retChan <- returnPair{nil, err}
+			return
+		}
+
+		if len(msgs) == 0 {
+			retChan <- returnPair{[]*MessageEnvelope{}, nil}
+			return
+		}
+
+		retChan <- returnPair{msgs, nil}
+	}()
+
+	// wait for our return channel or the context to cancel
+	select {
+	case retval := <-retChan:
+		return retval.messages, retval.err
+	case <-ctx.Done():
+		return []*MessageEnvelope{}, nil
+	}
+}
@@ -0,0 +1,306 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	. "github.com/blackhatbrigade/gomessagestore/repository"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+// rowsBetween returns the messages that match and were written in the time range, the way postgres would
+func rowsBetween(msgs []*MessageEnvelope, since, until time.Time, matches func(*MessageEnvelope) bool) *sqlmock.Rows {
+	rows := sqlmock.NewRows([]string{"id", "stream_name", "stream_category", "type", "position", "global_position", "data", "metadata", "time"})
+	for _, row := range msgs {
+		if !since.IsZero() && row.Time.Before(since) {
+			continue
+		}
+		if !until.IsZero() && !row.Time.Before(until) {
+			continue
+		}
+		if matches(row) {
+			rows.AddRow(
+				row.ID, row.StreamName, row.StreamCategory, row.MessageType, row.Version, row.GlobalPosition, row.Data, row.Metadata, row.Time,
+			)
+		}
+	}
+
+	return rows
+}
+
+func TestPostgresRepoFindAllMessagesInStreamBetween(t *testing.T) {
+	tests := []struct {
+		name             string
+		dbError          error
+		existingMessages []*MessageEnvelope
+		expectedMessages []*MessageEnvelope
+		expectedErr      error
+		expectedQuery    string
+		expectedArgs     []driver.Value
+		streamName       string
+		callCancel       bool
+		version          int64
+		since            time.Time
+		until            time.Time
+		batchSize        int
+	}{{
+		name:             "when there are existing messages since a time it should return them",
+		existingMessages: mockMessages,
+		streamName:       "some_type-12345",
+		since:            time.Unix(1545539340, 0),
+		expectedMessages: mockMessages[4:],
+		expectedQuery:    "SELECT .* FROM messages WHERE stream_name = \\$1 AND position >= \\$2 AND time >= \\$3 ORDER BY position LIMIT \\$4",
+		expectedArgs:     []driver.Value{"some_type-12345", int64(0), time.Unix(1545539340, 0).UTC(), 1000},
+		batchSize:        1000,
+	}, {
+		name:             "when there are existing messages since a version until a time it should return them",
+		existingMessages: mockMessages,
+		streamName:       "some_type-12345",
+		until:            time.Unix(1545539340, 0),
+		expectedMessages: mockMessages[:1],
+		expectedQuery:    "SELECT .* FROM messages WHERE stream_name = \\$1 AND position >= \\$2 AND time < \\$3 ORDER BY position LIMIT \\$4",
+		expectedArgs:     []driver.Value{"some_type-12345", int64(0), time.Unix(1545539340, 0).UTC(), 1000},
+		batchSize:        1000,
+	}, {
+		name:             "when there are no messages in the time range it should return no messages",
+		existingMessages: mockMessages,
+		streamName:       "some_type-12345",
+		since:            time.Unix(1545539340, 0),
+		until:            time.Unix(1545549339, 0),
+		expectedMessages: []*MessageEnvelope{},
+		expectedQuery:    "SELECT .* FROM messages WHERE stream_name = \\$1 AND position >= \\$2 AND time >= \\$3 AND time < \\$4 ORDER BY position LIMIT \\$5",
+		expectedArgs:     []driver.Value{"some_type-12345", int64(0), time.Unix(1545539340, 0).UTC(), time.Unix(1545549339, 0).UTC(), 1000},
+		batchSize:        1000,
+	}, {
+		name:        "when asking for messages from a blank stream, an error is returned",
+		expectedErr: ErrInvalidStreamName,
+		batchSize:   1000,
+	}, {
+		name:        "when asking for messages with a negative batch size, an error is returned",
+		streamName:  "some_type-12345",
+		expectedErr: ErrNegativeBatchSize,
+		batchSize:   -10,
+	}, {
+		name:          "when there is an issue getting the messages an error should be returned",
+		streamName:    "some_type-12345",
+		dbError:       errors.New("bad things with db happened"),
+		expectedErr:   errors.New("bad things with db happened"),
+		expectedQuery: "SELECT .* FROM messages WHERE stream_name = \\$1 AND position >= \\$2 ORDER BY position LIMIT \\$3",
+		expectedArgs:  []driver.Value{"some_type-12345", int64(5), 1000},
+		version:       5,
+		batchSize:     1000,
+	}, {
+		name:             "when it is asked to cancel, it does",
+		existingMessages: mockMessages,
+		streamName:       "some_type-12345",
+		callCancel:       true,
+		expectedMessages: []*MessageEnvelope{},
+		expectedQuery:    "SELECT .* FROM messages WHERE stream_name = \\$1 AND position >= \\$2 ORDER BY position LIMIT \\$3",
+		expectedArgs:     []driver.Value{"some_type-12345", int64(0), 1000},
+		batchSize:        1000,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+			db, mockDb, _ := sqlmock.New()
+			logrusLogger := logrus.New()
+			repo := NewPostgresRepository(db, logrusLogger)
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel() // free all resources
+
+			if test.expectedQuery != "" {
+				expectedQuery := mockDb.
+					ExpectQuery(test.expectedQuery).
+					WithArgs(test.expectedArgs...).
+					WillDelayFor(time.Millisecond * 10)
+
+				if test.dbError == nil {
+					expectedQuery.WillReturnRows(rowsBetween(test.existingMessages, test.since, test.until, func(row *MessageEnvelope) bool {
+						return row.StreamName == test.streamName && row.Version >= test.version
+					}))
+				} else {
+					expectedQuery.WillReturnError(test.dbError)
+				}
+			}
+
+			if test.callCancel {
+				time.AfterFunc(time.Millisecond*5, cancel) // after the call to the DB, but before it finishes
+			}
+			messages, err := repo.GetAllMessagesInStreamBetween(ctx, test.streamName, test.version, test.since, test.until, test.batchSize)
+
+			assert.Equal(test.expectedErr, err)
+			assert.Equal(test.expectedMessages, messages)
+			assert.Nil(mockDb.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestPostgresRepoFindAllMessagesInCategoryBetween(t *testing.T) {
+	tests := []struct {
+		name             string
+		dbError          error
+		existingMessages []*MessageEnvelope
+		expectedMessages []*MessageEnvelope
+		expectedErr      error
+		expectedQuery    string
+		expectedArgs     []driver.Value
+		category         string
+		position         int64
+		since            time.Time
+		until            time.Time
+		batchSize        int
+	}{{
+		name:             "when there are existing messages between two times it should return them",
+		existingMessages: mockMessages,
+		category:         "other_type",
+		since:            time.Unix(1545539340, 0),
+		until:            time.Unix(1546773907, 0),
+		expectedMessages: []*MessageEnvelope{mockMessages[1], mockMessages[4]},
+		expectedQuery:    "SELECT .* FROM messages WHERE category\\(stream_name\\) = \\$1 AND global_position >= \\$2 AND time >= \\$3 AND time < \\$4 ORDER BY global_position LIMIT \\$5",
+		expectedArgs:     []driver.Value{"other_type", int64(0), time.Unix(1545539340, 0).UTC(), time.Unix(1546773907, 0).UTC(), 1000},
+		batchSize:        1000,
+	}, {
+		name:             "when there are existing messages since a position until a time it should return them",
+		existingMessages: mockMessages,
+		category:         "other_type",
+		position:         4,
+		until:            time.Unix(1546773907, 0),
+		expectedMessages: []*MessageEnvelope{mockMessages[1], mockMessages[4]},
+		expectedQuery:    "SELECT .* FROM messages WHERE category\\(stream_name\\) = \\$1 AND global_position >= \\$2 AND time < \\$3 ORDER BY global_position LIMIT \\$4",
+		expectedArgs:     []driver.Value{"other_type", int64(4), time.Unix(1546773907, 0).UTC(), 1000},
+		batchSize:        1000,
+	}, {
+		name:        "when asking for messages from a blank category, an error is returned",
+		expectedErr: ErrBlankCategory,
+		batchSize:   1000,
+	}, {
+		name:        "when asking for messages from an invalid category, an error is returned",
+		category:    "something-bad",
+		expectedErr: ErrInvalidCategory,
+		batchSize:   1000,
+	}, {
+		name:        "when asking for messages with a negative batch size, an error is returned",
+		category:    "other_type",
+		expectedErr: ErrNegativeBatchSize,
+		batchSize:   -10,
+	}, {
+		name:          "when there is an issue getting the messages an error should be returned",
+		category:      "other_type",
+		dbError:       errors.New("bad things with db happened"),
+		expectedErr:   errors.New("bad things with db happened"),
+		expectedQuery: "SELECT .* FROM messages WHERE category\\(stream_name\\) = \\$1 AND global_position >= \\$2 ORDER BY global_position LIMIT \\$3",
+		expectedArgs:  []driver.Value{"other_type", int64(0), 1000},
+		batchSize:     1000,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+			db, mockDb, _ := sqlmock.New()
+			logrusLogger := logrus.New()
+			repo := NewPostgresRepository(db, logrusLogger)
+			ctx := context.Background()
+
+			if test.expectedQuery != "" {
+				expectedQuery := mockDb.
+					ExpectQuery(test.expectedQuery).
+					WithArgs(test.expectedArgs...)
+
+				if test.dbError == nil {
+					expectedQuery.WillReturnRows(rowsBetween(test.existingMessages, test.since, test.until, func(row *MessageEnvelope) bool {
+						return row.StreamCategory == test.category && row.GlobalPosition >= test.position
+					}))
+				} else {
+					expectedQuery.WillReturnError(test.dbError)
+				}
+			}
+
+			messages, err := repo.GetAllMessagesInCategoryBetween(ctx, test.category, test.position, test.since, test.until, test.batchSize)
+
+			assert.Equal(test.expectedErr, err)
+			assert.Equal(test.expectedMessages, messages)
+			assert.Nil(mockDb.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestPostgresRepoFindAllMessagesBetween(t *testing.T) {
+	tests := []struct {
+		name             string
+		dbError          error
+		existingMessages []*MessageEnvelope
+		expectedMessages []*MessageEnvelope
+		expectedErr      error
+		expectedQuery    string
+		expectedArgs     []driver.Value
+		position         int64
+		since            time.Time
+		until            time.Time
+		batchSize        int
+	}{{
+		name:             "when there are existing messages since a time it should return them",
+		existingMessages: mockMessages,
+		since:            time.Unix(1546773907, 0),
+		expectedMessages: mockMessages[2:4],
+		expectedQuery:    "SELECT .* FROM messages WHERE global_position >= \\$1 AND stream_name NOT LIKE '%\\+position' AND time >= \\$2 ORDER BY global_position LIMIT \\$3",
+		expectedArgs:     []driver.Value{int64(0), time.Unix(1546773907, 0).UTC(), 1000},
+		batchSize:        1000,
+	}, {
+		name:             "when there are existing messages since a position until a time it should return them",
+		existingMessages: mockMessages,
+		position:         4,
+		until:            time.Unix(1546773907, 0),
+		expectedMessages: []*MessageEnvelope{mockMessages[1], mockMessages[4]},
+		expectedQuery:    "SELECT .* FROM messages WHERE global_position >= \\$1 AND stream_name NOT LIKE '%\\+position' AND time < \\$2 ORDER BY global_position LIMIT \\$3",
+		expectedArgs:     []driver.Value{int64(4), time.Unix(1546773907, 0).UTC(), 1000},
+		batchSize:        1000,
+	}, {
+		name:        "when asking for messages with a negative batch size, an error is returned",
+		expectedErr: ErrNegativeBatchSize,
+		batchSize:   -10,
+	}, {
+		name:          "when there is an issue getting the messages an error should be returned",
+		dbError:       errors.New("bad things with db happened"),
+		expectedErr:   errors.New("bad things with db happened"),
+		since:         time.Unix(1546773907, 0),
+		expectedQuery: "SELECT .* FROM messages WHERE global_position >= \\$1 AND stream_name NOT LIKE '%\\+position' AND time >= \\$2 ORDER BY global_position LIMIT \\$3",
+		expectedArgs:  []driver.Value{int64(0), time.Unix(1546773907, 0).UTC(), 1000},
+		batchSize:     1000,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+			db, mockDb, _ := sqlmock.New()
+			logrusLogger := logrus.New()
+			repo := NewPostgresRepository(db, logrusLogger)
+			ctx := context.Background()
+
+			if test.expectedQuery != "" {
+				expectedQuery := mockDb.
+					ExpectQuery(test.expectedQuery).
+					WithArgs(test.expectedArgs...)
+
+				if test.dbError == nil {
+					expectedQuery.WillReturnRows(rowsBetween(test.existingMessages, test.since, test.until, func(row *MessageEnvelope) bool {
+						return row.GlobalPosition >= test.position
+					}))
+				} else {
+					expectedQuery.WillReturnError(test.dbError)
+				}
+			}
+
+			messages, err := repo.GetAllMessagesBetween(ctx, test.position, test.since, test.until, test.batchSize)
+
+			assert.Equal(test.expectedErr, err)
+			assert.Equal(test.expectedMessages, messages)
+			assert.Nil(mockDb.ExpectationsWereMet())
+		})
+	}
+}
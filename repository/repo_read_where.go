@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/blackhatbrigade/gomessagestore/streamname"
+	"github.com/sirupsen/logrus"
+)
+
+// The Where reads pass the filter to message db as its _condition, which it only accepts when message_store.sql_condition is enabled for the session or database.
+
+func (r postgresRepo) GetAllMessagesInStreamWhere(ctx context.Context, streamName string, version int64, filter Filter, batchSize int) ([]*MessageEnvelope, error) {
+	if streamName == "" {
+		logrus.WithError(ErrInvalidStreamName).Error("Failure in repo_postgres.go::GetAllMessagesInStreamWhere")
+
+		return nil, ErrInvalidStreamName
+	}
+	if batchSize < 0 {
+		logrus.WithError(ErrNegativeBatchSize).Error("Failure in repo_postgres.go::GetAllMessagesInStreamWhere")
+
+		return nil, ErrNegativeBatchSize
+	}
+	condition, err := filter.Condition()
+	if err != nil {
+		logrus.WithError(err).Error("Failure in repo_postgres.go::GetAllMessagesInStreamWhere")
+
+		return nil, err
+	}
+
+	/*get_stream_messages(
+	  _stream_name varchar,
+	  _position bigint DEFAULT 0,
+	  _batch_size bigint DEFAULT 1000,
+	  _condition varchar DEFAULT NULL
+	)*/
+	query := "SELECT * FROM get_stream_messages($1, $2, $3, $4)"
+
+	return r.selectBetween(ctx, "GetAllMessagesInStreamWhere", query, []interface{}{streamName, version, batchSize, condition})
+}
+
+func (r postgresRepo) GetAllMessagesInCategoryWhere(ctx context.Context, category string, globalPosition int64, filter Filter, batchSize int) ([]*MessageEnvelope, error) {
+	if category == "" {
+		logrus.WithError(ErrBlankCategory).Error("Failure in repo_postgres.go::GetAllMessagesInCategoryWhere")
+
+		return nil, ErrBlankCategory
+	}
+	if batchSize < 0 {
+		logrus.WithError(ErrNegativeBatchSize).Error("Failure in repo_postgres.go::GetAllMessagesInCategoryWhere")
+
+		return nil, ErrNegativeBatchSize
+	}
+	if !streamname.IsCategory(category) {
+		logrus.WithError(ErrInvalidCategory).Error("Failure in repo_postgres.go::GetAllMessagesInCategoryWhere")
+		return nil, ErrInvalidCategory
+	}
+	condition, err := filter.Condition()
+	if err != nil {
+		logrus.WithError(err).Error("Failure in repo_postgres.go::GetAllMessagesInCategoryWhere")
+
+		return nil, err
+	}
+
+	/*get_category_messages(
+	  category varchar,
+	  position bigint DEFAULT 1,
+	  batch_size bigint DEFAULT 1000,
+	  correlation varchar DEFAULT NULL,
+	  consumer_group_member bigint DEFAULT NULL,
+	  consumer_group_size bigint DEFAULT NULL,
+	  condition varchar DEFAULT NULL
+	)*/
+	query := "SELECT * FROM get_category_messages($1, $2, $3, condition => $4)" // condition comes after the correlation and consumer group arguments, so name it
+
+	return r.selectBetween(ctx, "GetAllMessagesInCategoryWhere", query, []interface{}{category, globalPosition, batchSize, condition})
+}
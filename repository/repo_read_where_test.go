@@ -0,0 +1,204 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	. "github.com/blackhatbrigade/gomessagestore/repository"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPostgresRepoFindAllMessagesInStreamWhere(t *testing.T) {
+	tests := []struct {
+		name             string
+		dbError          error
+		existingMessages []*MessageEnvelope
+		expectedMessages []*MessageEnvelope
+		expectedErr      error
+		expectedQuery    string
+		expectedArgs     []driver.Value
+		streamName       string
+		callCancel       bool
+		version          int64
+		filter           Filter
+		batchSize        int
+	}{{
+		name:             "when there are matching messages it should return them",
+		existingMessages: mockMessages[4:],
+		streamName:       "some_type-12345",
+		version:          1,
+		filter:           TypeIn("some_type"),
+		expectedMessages: mockMessages[4:],
+		expectedQuery:    "SELECT \\* FROM get_stream_messages\\(\\$1, \\$2, \\$3, \\$4\\)",
+		expectedArgs:     []driver.Value{"some_type-12345", int64(1), 1000, "type IN ('some_type')"},
+		batchSize:        1000,
+	}, {
+		name:             "when there are no matching messages it should return no messages",
+		streamName:       "some_type-12345",
+		filter:           MetadataEquals("userId", "o'brien"),
+		expectedMessages: []*MessageEnvelope{},
+		expectedQuery:    "SELECT \\* FROM get_stream_messages\\(\\$1, \\$2, \\$3, \\$4\\)",
+		expectedArgs:     []driver.Value{"some_type-12345", int64(0), 1000, "(metadata #>> ARRAY['userId']) = 'o''brien'"},
+		batchSize:        1000,
+	}, {
+		name:        "when asking for messages from a blank stream, an error is returned",
+		filter:      TypeIn("some_type"),
+		expectedErr: ErrInvalidStreamName,
+		batchSize:   1000,
+	}, {
+		name:        "when asking for messages with a negative batch size, an error is returned",
+		streamName:  "some_type-12345",
+		filter:      TypeIn("some_type"),
+		expectedErr: ErrNegativeBatchSize,
+		batchSize:   -10,
+	}, {
+		name:        "when asking for messages with an invalid filter, an error is returned",
+		streamName:  "some_type-12345",
+		expectedErr: ErrInvalidFilter,
+		batchSize:   1000,
+	}, {
+		name:          "when there is an issue getting the messages an error should be returned",
+		streamName:    "some_type-12345",
+		filter:        TypeIn("some_type"),
+		dbError:       errors.New("bad things with db happened"),
+		expectedErr:   errors.New("bad things with db happened"),
+		expectedQuery: "SELECT \\* FROM get_stream_messages\\(\\$1, \\$2, \\$3, \\$4\\)",
+		expectedArgs:  []driver.Value{"some_type-12345", int64(0), 1000, "type IN ('some_type')"},
+		batchSize:     1000,
+	}, {
+		name:             "when it is asked to cancel, it does",
+		existingMessages: mockMessages[4:],
+		streamName:       "some_type-12345",
+		filter:           TypeIn("some_type"),
+		callCancel:       true,
+		expectedMessages: []*MessageEnvelope{},
+		expectedQuery:    "SELECT \\* FROM get_stream_messages\\(\\$1, \\$2, \\$3, \\$4\\)",
+		expectedArgs:     []driver.Value{"some_type-12345", int64(0), 1000, "type IN ('some_type')"},
+		batchSize:        1000,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+			db, mockDb, _ := sqlmock.New()
+			logrusLogger := logrus.New()
+			repo := NewPostgresRepository(db, logrusLogger)
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel() // free all resources
+
+			if test.expectedQuery != "" {
+				expectedQuery := mockDb.
+					ExpectQuery(test.expectedQuery).
+					WithArgs(test.expectedArgs...).
+					WillDelayFor(time.Millisecond * 10)
+
+				if test.dbError == nil {
+					// postgres does the filtering, so the rows are returned as they are
+					expectedQuery.WillReturnRows(rowsBetween(test.existingMessages, time.Time{}, time.Time{}, func(*MessageEnvelope) bool { return true }))
+				} else {
+					expectedQuery.WillReturnError(test.dbError)
+				}
+			}
+
+			if test.callCancel {
+				time.AfterFunc(time.Millisecond*5, cancel) // after the call to the DB, but before it finishes
+			}
+			messages, err := repo.GetAllMessagesInStreamWhere(ctx, test.streamName, test.version, test.filter, test.batchSize)
+
+			assert.Equal(test.expectedErr, err)
+			assert.Equal(test.expectedMessages, messages)
+			assert.Nil(mockDb.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestPostgresRepoFindAllMessagesInCategoryWhere(t *testing.T) {
+	tests := []struct {
+		name             string
+		dbError          error
+		existingMessages []*MessageEnvelope
+		expectedMessages []*MessageEnvelope
+		expectedErr      error
+		expectedQuery    string
+		expectedArgs     []driver.Value
+		category         string
+		position         int64
+		filter           Filter
+		batchSize        int
+	}{{
+		name:             "when there are matching messages it should return them",
+		existingMessages: []*MessageEnvelope{mockMessages[1], mockMessages[4]},
+		category:         "other_type",
+		position:         4,
+		filter:           And(TypeIn("some_type"), DataEquals("123", "b")),
+		expectedMessages: []*MessageEnvelope{mockMessages[1], mockMessages[4]},
+		expectedQuery:    "SELECT \\* FROM get_category_messages\\(\\$1, \\$2, \\$3, condition => \\$4\\)",
+		expectedArgs:     []driver.Value{"other_type", int64(4), 1000, "(type IN ('some_type')) AND ((data #>> ARRAY['b']) = '123')"},
+		batchSize:        1000,
+	}, {
+		name:        "when asking for messages from a blank category, an error is returned",
+		filter:      TypeIn("some_type"),
+		expectedErr: ErrBlankCategory,
+		batchSize:   1000,
+	}, {
+		name:        "when asking for messages from an invalid category, an error is returned",
+		category:    "something-bad",
+		filter:      TypeIn("some_type"),
+		expectedErr: ErrInvalidCategory,
+		batchSize:   1000,
+	}, {
+		name:        "when asking for messages with a negative batch size, an error is returned",
+		category:    "other_type",
+		filter:      TypeIn("some_type"),
+		expectedErr: ErrNegativeBatchSize,
+		batchSize:   -10,
+	}, {
+		name:        "when asking for messages with an unsafe filter, an error is returned",
+		category:    "other_type",
+		filter:      TypeIn("some\x00type"),
+		expectedErr: ErrUnsafeFilterValue,
+		batchSize:   1000,
+	}, {
+		name:          "when there is an issue getting the messages an error should be returned",
+		category:      "other_type",
+		filter:        TypeIn("some_type"),
+		dbError:       errors.New("bad things with db happened"),
+		expectedErr:   errors.New("bad things with db happened"),
+		expectedQuery: "SELECT \\* FROM get_category_messages\\(\\$1, \\$2, \\$3, condition => \\$4\\)",
+		expectedArgs:  []driver.Value{"other_type", int64(0), 1000, "type IN ('some_type')"},
+		batchSize:     1000,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+			db, mockDb, _ := sqlmock.New()
+			logrusLogger := logrus.New()
+			repo := NewPostgresRepository(db, logrusLogger)
+			ctx := context.Background()
+
+			if test.expectedQuery != "" {
+				expectedQuery := mockDb.
+					ExpectQuery(test.expectedQuery).
+					WithArgs(test.expectedArgs...)
+
+				if test.dbError == nil {
+					expectedQuery.WillReturnRows(rowsBetween(test.existingMessages, time.Time{}, time.Time{}, func(*MessageEnvelope) bool { return true }))
+				} else {
+					expectedQuery.WillReturnError(test.dbError)
+				}
+			}
+
+			messages, err := repo.GetAllMessagesInCategoryWhere(ctx, test.category, test.position, test.filter, test.batchSize)
+
+			assert.Equal(test.expectedErr, err)
+			assert.Equal(test.expectedMessages, messages)
+			assert.Nil(mockDb.ExpectationsWereMet())
+		})
+	}
+}
@@ -15,6 +15,77 @@ func (r postgresRepo) WriteMessageWithExpectedPosition(ctx context.Context, msg
 	return r.writeMessageEitherWay(ctx, msg, position)
 }
 
+// WriteMessagesWithExpectedPosition writes messages to a single stream in one transaction, so either all of them are written or none are
+// The first message is written at the expected position, and each of the rest at the position after the one before it.
+func (r postgresRepo) WriteMessagesWithExpectedPosition(ctx context.Context, msgs []*MessageEnvelope, position int64) error {
+	for _, msg := range msgs {
+		if msg == nil {
+			return ErrNilMessage
+		}
+
+		if msg.ID == uuid.Nil {
+			return ErrMessageNoID
+		}
+
+		if msg.StreamName == "" {
+			return ErrInvalidStreamName
+		}
+
+		if msg.StreamName != msgs[0].StreamName {
+			return ErrMixedStreams
+		}
+	}
+
+	if position < -1 {
+		return ErrInvalidPosition
+	}
+
+	if len(msgs) == 0 {
+		return nil
+	}
+
+	// our return channel for our goroutine that will either finish or be cancelled
+	retChan := make(chan error, 1)
+	go func() {
+		// last thing we do is ensure our return channel is populated
+		defer func() {
+			retChan <- nil
+		}()
+
+		tx, err := r.dbx.BeginTxx(ctx, nil)
+		if err != nil {
+			logrus.WithError(err).Error("Failure in repo_postgres.go::WriteMessagesWithExpectedPosition")
+			retChan <- err
+			return
+		}
+
+		// write_message checks _expected_version against the stream as the transaction sees it, so each message expects the one before
+		query := "SELECT write_message($1, $2, $3, $4, $5, $6)"
+		for i, msg := range msgs {
+			if _, err := tx.ExecContext(ctx, query, msg.ID, msg.StreamName, msg.MessageType, msg.Data, msg.Metadata, position+int64(i)); err != nil {
+				logrus.WithError(err).Error("Failure in repo_postgres.go::WriteMessagesWithExpectedPosition")
+				tx.Rollback()
+				retChan <- err
+				return
+			}
+		}
+
+		if err := tx.Commit(); err != nil {
+			logrus.WithError(err).Error("Failure in repo_postgres.go::WriteMessagesWithExpectedPosition")
+			retChan <- err
+			return
+		}
+	}()
+
+	// wait for our return channel or the context to cancel
+	select {
+	case retval := <-retChan:
+		return retval
+	case <-ctx.Done():
+		return nil
+	}
+}
+
 func (r postgresRepo) writeMessageEitherWay(ctx context.Context, msg *MessageEnvelope, position ...int64) error {
 	if msg == nil {
 		return ErrNilMessage
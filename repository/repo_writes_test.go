@@ -172,3 +172,92 @@ func TestPostgresRepoWriteMessageWithExpectedPosition(t *testing.T) {
 		})
 	}
 }
+
+func TestPostgresRepoWriteMessagesWithExpectedPosition(t *testing.T) {
+	tests := []struct {
+		name        string
+		msgs        []*MessageEnvelope
+		dbError     error
+		failAt      int
+		expectedErr error
+		position    int64
+	}{{
+		name:     "when there is no db error, it writes the messages one after another in a transaction",
+		msgs:     []*MessageEnvelope{mockMessages[0], mockMessages[4]},
+		position: 1,
+	}, {
+		name:     "when the stream is new, the first message is written at -1",
+		msgs:     []*MessageEnvelope{mockMessages[0], mockMessages[4]},
+		position: -1,
+	}, {
+		name:        "when there is a db error, the transaction is rolled back and the error returned",
+		msgs:        []*MessageEnvelope{mockMessages[0], mockMessages[4]},
+		dbError:     errors.New("bad things with db happened"),
+		failAt:      1,
+		expectedErr: errors.New("bad things with db happened"),
+		position:    1,
+	}, {
+		name: "when there are no messages, nothing is written",
+	}, {
+		name:        "when there is a nil message, an error is returned",
+		msgs:        []*MessageEnvelope{mockMessages[0], nil},
+		expectedErr: ErrNilMessage,
+	}, {
+		name:        "when a message has no ID, an error is returned",
+		msgs:        []*MessageEnvelope{mockMessageNoID},
+		expectedErr: ErrMessageNoID,
+	}, {
+		name:        "when a message has no stream name, an error is returned",
+		msgs:        []*MessageEnvelope{mockMessageNoStream},
+		expectedErr: ErrInvalidStreamName,
+	}, {
+		name:        "when the messages are in different streams, an error is returned",
+		msgs:        []*MessageEnvelope{mockMessages[0], mockMessages[1]},
+		expectedErr: ErrMixedStreams,
+	}, {
+		name:        "when the position is below -1, an error is returned",
+		msgs:        []*MessageEnvelope{mockMessages[0]},
+		expectedErr: ErrInvalidPosition,
+		position:    -2,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+			db, mockDb, _ := sqlmock.New()
+			logrusLogger := logrus.New()
+			repo := NewPostgresRepository(db, logrusLogger)
+			ctx := context.Background()
+
+			if len(test.msgs) > 0 && (test.expectedErr == nil || test.dbError != nil) {
+				mockDb.ExpectBegin()
+				for i, msg := range test.msgs {
+					expectedExec := mockDb.
+						ExpectExec("SELECT write_message\\(\\$1, \\$2, \\$3, \\$4, \\$5, \\$6\\)").
+						WithArgs(msg.ID,
+							msg.StreamName,
+							msg.MessageType,
+							msg.Data,
+							msg.Metadata,
+							test.position+int64(i),
+						)
+
+					if test.dbError != nil && i == test.failAt {
+						expectedExec.WillReturnError(test.dbError)
+						mockDb.ExpectRollback()
+						break
+					}
+					expectedExec.WillReturnResult(sqlmock.NewResult(1, 1))
+				}
+				if test.dbError == nil {
+					mockDb.ExpectCommit()
+				}
+			}
+
+			err := repo.WriteMessagesWithExpectedPosition(ctx, test.msgs, test.position)
+
+			assert.Equal(test.expectedErr, err)
+			assert.Nil(mockDb.ExpectationsWereMet())
+		})
+	}
+}
@@ -3,22 +3,42 @@ package repository
 import (
 	"context"
 	"errors"
+	"time"
+
+	"github.com/blackhatbrigade/gomessagestore/uuid"
 )
 
 //go:generate bash -c "${GOPATH}/bin/mockgen github.com/blackhatbrigade/gomessagestore/repository Repository > mocks/repository.go"
 
 //Repository the storage implementation for messagestore
+// Backward reads return the newest message first; a negative before version/position starts at the newest message.
+// Between reads return messages written at or after since and before until; a zero time leaves that end of the range open.
+// Where reads return only the messages matching the filter; postgres needs message_store.sql_condition enabled for them.
 type Repository interface {
 	// writes
 	WriteMessage(ctx context.Context, message *MessageEnvelope) error
 	WriteMessageWithExpectedPosition(ctx context.Context, message *MessageEnvelope, position int64) error
+	WriteMessagesWithExpectedPosition(ctx context.Context, messages []*MessageEnvelope, position int64) error // all or none of the messages are written, one after another from position
 	// reads from stream
 	GetAllMessagesInStream(ctx context.Context, streamName string, batchSize int) ([]*MessageEnvelope, error)
 	GetAllMessagesInStreamSince(ctx context.Context, streamName string, globalPosition int64, batchSize int) ([]*MessageEnvelope, error)
 	GetLastMessageInStream(ctx context.Context, streamName string) (*MessageEnvelope, error)
+	GetMessagesInStreamBackward(ctx context.Context, streamName string, beforeVersion int64, batchSize int) ([]*MessageEnvelope, error)
+	GetAllMessagesInStreamBetween(ctx context.Context, streamName string, version int64, since, until time.Time, batchSize int) ([]*MessageEnvelope, error)
+	GetAllMessagesInStreamWhere(ctx context.Context, streamName string, version int64, filter Filter, batchSize int) ([]*MessageEnvelope, error)
 	// reads from category
 	GetAllMessagesInCategory(ctx context.Context, category string, batchSize int) ([]*MessageEnvelope, error)
 	GetAllMessagesInCategorySince(ctx context.Context, category string, globalPosition int64, batchSize int) ([]*MessageEnvelope, error)
+	GetAllMessagesInCategoriesSince(ctx context.Context, categories []string, globalPosition int64, batchSize int) ([]*MessageEnvelope, error)
+	GetMessagesInCategoryBackward(ctx context.Context, category string, beforePosition int64, batchSize int) ([]*MessageEnvelope, error)
+	GetAllMessagesInCategoryBetween(ctx context.Context, category string, globalPosition int64, since, until time.Time, batchSize int) ([]*MessageEnvelope, error)
+	GetAllMessagesInCategoryWhere(ctx context.Context, category string, globalPosition int64, filter Filter, batchSize int) ([]*MessageEnvelope, error)
+	// reads a single message
+	GetMessageByID(ctx context.Context, id uuid.UUID) (*MessageEnvelope, error)
+	// reads from every stream
+	GetAllMessagesSince(ctx context.Context, globalPosition int64, batchSize int) ([]*MessageEnvelope, error)
+	GetAllMessagesBetween(ctx context.Context, globalPosition int64, since, until time.Time, batchSize int) ([]*MessageEnvelope, error)
+	GetLastMessage(ctx context.Context) (*MessageEnvelope, error)
 }
 
 //Errors
@@ -0,0 +1,154 @@
+package gomessagestore
+
+import (
+	"context"
+	"time"
+
+	"github.com/blackhatbrigade/gomessagestore/streamname"
+	"github.com/blackhatbrigade/gomessagestore/uuid"
+)
+
+type requestOpts struct {
+	timeout      time.Duration
+	pollInterval time.Duration
+}
+
+// RequestOption provides optional arguments to the Request function
+type RequestOption func(opts *requestOpts)
+
+func checkRequestOptions(opts ...RequestOption) *requestOpts {
+	requestOptions := &requestOpts{
+		timeout:      30 * time.Second,
+		pollInterval: 200 * time.Millisecond,
+	}
+	for _, option := range opts {
+		option(requestOptions)
+	}
+	return requestOptions
+}
+
+// RequestTimeout sets how long Request waits for a reply; defaults to 30 seconds
+func RequestTimeout(timeout time.Duration) RequestOption {
+	return func(opts *requestOpts) {
+		opts.timeout = timeout
+	}
+}
+
+// RequestPollInterval sets how often Request checks the reply stream; defaults to 200 milliseconds
+func RequestPollInterval(pollInterval time.Duration) RequestOption {
+	return func(opts *requestOpts) {
+		opts.pollInterval = pollInterval
+	}
+}
+
+// Request writes cmd with a new reply stream in replyCategory as its replyStreamName, then waits for the reply written to it with Reply()
+// The reply is the first message in the reply stream caused by cmd, converted as Get would convert it; ErrRequestTimedOut is returned if it doesn't arrive in time.
+func (ms *msgStore) Request(ctx context.Context, cmd Message, replyCategory string, opts ...RequestOption) (Message, error) {
+	if replyCategory == "" {
+		return nil, ErrMissingMessageCategory
+	}
+	if !streamname.IsCategory(replyCategory) {
+		return nil, ErrInvalidMessageCategory
+	}
+	requestOptions := checkRequestOptions(opts...)
+
+	envelope, err := cmd.ToEnvelope()
+	if err != nil {
+		return nil, err
+	}
+	replyStreamID := NewID().String()
+	request, err := WithMetadata(cmd, Metadata{ReplyStreamName: streamname.Compose(replyCategory, replyStreamID)})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ms.Write(ctx, request); err != nil {
+		return nil, err
+	}
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, requestOptions.timeout)
+	defer cancel()
+
+	reply, err := ms.awaitReply(timeoutCtx, replyCategory, replyStreamID, envelope.ID, requestOptions.pollInterval)
+	if err != nil && ctx.Err() == nil && timeoutCtx.Err() != nil {
+		return nil, ErrRequestTimedOut
+	}
+	return reply, err
+}
+
+// awaitReply polls the reply stream until a message caused by the request shows up
+func (ms *msgStore) awaitReply(ctx context.Context, replyCategory, replyStreamID string, requestID uuid.UUID, pollInterval time.Duration) (Message, error) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	var nextVersion int64
+	for {
+		msgs, err := ms.Get(ctx, EventStreamID(replyCategory, replyStreamID), SinceVersion(nextVersion))
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			return nil, err
+		}
+
+		for _, msg := range msgs {
+			nextVersion = msg.Version() + 1
+			metadata, err := MetadataOf(msg)
+			if err == nil && metadata.CausationMessageID == requestID {
+				return msg, nil
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Reply writes reply to the reply stream named in the metadata of original, following original so Request can match it up
+// Use it from the handler of a command sent with Request(); reply must be an Event, StreamMessage or TypedMessage.
+func Reply(ctx context.Context, ms MessageStore, original, reply Message) error {
+	metadata, err := FollowMetadata(original)
+	if err != nil {
+		return err
+	}
+	replyStreamName := metadata.ReplyStreamName
+	if replyStreamName == "" {
+		return ErrMissingReplyStreamName
+	}
+	metadata.ReplyStreamName = "" // the reply isn't waiting on a reply of its own
+
+	message, err := inStream(reply, replyStreamName)
+	if err != nil {
+		return err
+	}
+	if message, err = WithMetadata(message, metadata); err != nil {
+		return err
+	}
+
+	return ms.Write(ctx, message)
+}
+
+// inStream returns a copy of message that is written to streamName
+func inStream(message Message, streamName string) (Message, error) {
+	switch msg := message.(type) {
+	case *Event:
+		inStream := *msg
+		inStream.StreamCategory = streamname.Category(streamName)
+		inStream.StreamID = streamname.ID(streamName)
+		inStream.EntityID, _ = uuid.Parse(inStream.StreamID) // IDs that aren't UUIDs leave EntityID blank
+		return &inStream, nil
+	case *StreamMessage:
+		inStream := *msg
+		inStream.StreamName = streamName
+		return &inStream, nil
+	case *TypedMessage:
+		inStream := *msg
+		inStream.StreamName = streamName
+		return &inStream, nil
+	}
+
+	return nil, ErrUnsupportedMessage
+}
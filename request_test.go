@@ -0,0 +1,267 @@
+package gomessagestore_test
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/blackhatbrigade/gomessagestore"
+	"github.com/blackhatbrigade/gomessagestore/inmem_repository"
+	"github.com/blackhatbrigade/gomessagestore/repository"
+	"github.com/blackhatbrigade/gomessagestore/repository/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func getSampleReserveInventory() *Command {
+	return &Command{
+		ID:             uuid6,
+		StreamCategory: "inventory",
+		MessageType:    "ReserveInventory",
+		Data:           map[string]interface{}{"sku": "abc", "quantity": float64(2)},
+		Metadata:       map[string]interface{}{"userId": "bob"},
+	}
+}
+
+func getSampleInventoryReserved() *Event {
+	return &Event{
+		ID:          uuid7,
+		MessageType: "InventoryReserved",
+		Data:        map[string]interface{}{"sku": "abc"},
+	}
+}
+
+func TestRequestWaitsForReply(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockRepository(ctrl)
+	ctx := context.Background()
+	replierRepo := inmem_repository.NewInMemoryRepository(nil)
+	replier := NewMessageStoreFromRepository(replierRepo, logrus.New())
+
+	var written *repository.MessageEnvelope
+	mockRepo.
+		EXPECT().
+		WriteMessage(ctx, gomock.Any()).
+		Do(func(_ context.Context, msgEnv *repository.MessageEnvelope) {
+			written = msgEnv
+		})
+
+	polls := 0
+	mockRepo.
+		EXPECT().
+		GetAllMessagesInStreamSince(gomock.Any(), gomock.Any(), gomock.Any(), 1000).
+		DoAndReturn(func(_ context.Context, streamName string, version int64, batchSize int) ([]*repository.MessageEnvelope, error) {
+			polls++
+			switch polls {
+			case 1: // nothing has replied yet
+				return nil, nil
+			case 2: // something else lands in the reply stream, then the handler replies
+				command := MsgEnvelopesToMessages([]*repository.MessageEnvelope{written})[0]
+				unrelated := getSampleInventoryReserved()
+				unrelated.ID = uuid9
+				unrelated.StreamCategory = "inventory:reply"
+				unrelated.StreamID = strings.TrimPrefix(streamName, "inventory:reply-")
+				assert.NoError(t, replier.Write(ctx, unrelated))
+				assert.NoError(t, Reply(ctx, replier, command, getSampleInventoryReserved()))
+			}
+			return replierRepo.GetAllMessagesInStreamSince(ctx, streamName, version, batchSize)
+		}).
+		Times(2)
+
+	msgStore := NewMessageStoreFromRepository(mockRepo, logrus.New())
+	reply, err := msgStore.Request(ctx, getSampleReserveInventory(), "inventory:reply", RequestPollInterval(time.Millisecond))
+
+	assert.NoError(t, err)
+	if assert.NotNil(t, written) {
+		metadata := make(map[string]interface{})
+		assert.NoError(t, json.Unmarshal(written.Metadata, &metadata))
+		assert.Equal(t, "bob", metadata["userId"], "the command keeps its own metadata")
+		assert.Regexp(t, "^inventory:reply-[0-9a-f-]{36}$", metadata[ReplyStreamNameKey])
+	}
+	event, ok := reply.(*Event)
+	if assert.True(t, ok, "expected an Event, got %T", reply) {
+		assert.Equal(t, uuid7, event.ID)
+		assert.Equal(t, "InventoryReserved", event.MessageType)
+		assert.Equal(t, uuid6.String(), event.Metadata[CausationMessageIDKey])
+	}
+}
+
+func TestRequestDoesNotChangeTheCommand(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockRepository(ctrl)
+	mockRepo.EXPECT().WriteMessage(gomock.Any(), gomock.Any())
+	mockRepo.EXPECT().GetAllMessagesInStreamSince(gomock.Any(), gomock.Any(), gomock.Any(), 1000).AnyTimes()
+
+	cmd := getSampleReserveInventory()
+	msgStore := NewMessageStoreFromRepository(mockRepo, logrus.New())
+	msgStore.Request(context.Background(), cmd, "inventory:reply", RequestTimeout(time.Millisecond))
+
+	assert.Equal(t, map[string]interface{}{"userId": "bob"}, cmd.Metadata)
+}
+
+func TestRequestTimesOut(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockRepository(ctrl)
+	mockRepo.EXPECT().WriteMessage(gomock.Any(), gomock.Any())
+	mockRepo.EXPECT().GetAllMessagesInStreamSince(gomock.Any(), gomock.Any(), gomock.Any(), 1000).AnyTimes()
+
+	msgStore := NewMessageStoreFromRepository(mockRepo, logrus.New())
+	reply, err := msgStore.Request(
+		context.Background(),
+		getSampleReserveInventory(),
+		"inventory:reply",
+		RequestTimeout(20*time.Millisecond),
+		RequestPollInterval(time.Millisecond),
+	)
+
+	assert.Equal(t, ErrRequestTimedOut, err)
+	assert.Nil(t, reply)
+}
+
+func TestRequestStopsWhenContextIsCanceled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	mockRepo := mock_repository.NewMockRepository(ctrl)
+	mockRepo.EXPECT().WriteMessage(ctx, gomock.Any())
+	mockRepo.
+		EXPECT().
+		GetAllMessagesInStreamSince(gomock.Any(), gomock.Any(), gomock.Any(), 1000).
+		Do(func(context.Context, string, int64, int) {
+			cancel()
+		})
+
+	msgStore := NewMessageStoreFromRepository(mockRepo, logrus.New())
+	reply, err := msgStore.Request(ctx, getSampleReserveInventory(), "inventory:reply")
+
+	assert.Equal(t, context.Canceled, err)
+	assert.Nil(t, reply)
+}
+
+func TestRequestValidation(t *testing.T) {
+	invalidCommand := getSampleReserveInventory()
+	invalidCommand.MessageType = ""
+
+	tests := []struct {
+		name          string
+		cmd           Message
+		replyCategory string
+		expectedErr   error
+	}{{
+		name:        "fails without a reply category",
+		cmd:         getSampleReserveInventory(),
+		expectedErr: ErrMissingMessageCategory,
+	}, {
+		name:          "fails with a reply stream instead of a category",
+		cmd:           getSampleReserveInventory(),
+		replyCategory: "inventory:reply-123",
+		expectedErr:   ErrInvalidMessageCategory,
+	}, {
+		name:          "fails for an invalid command",
+		cmd:           invalidCommand,
+		replyCategory: "inventory:reply",
+		expectedErr:   ErrMissingMessageType,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mock_repository.NewMockRepository(ctrl)
+
+			msgStore := NewMessageStoreFromRepository(mockRepo, logrus.New())
+			_, err := msgStore.Request(context.Background(), test.cmd, test.replyCategory)
+
+			assert.Equal(t, test.expectedErr, err)
+		})
+	}
+}
+
+func TestReply(t *testing.T) {
+	replyStreamName := "inventory:reply-" + uuid9.String()
+	original := getSampleReserveInventory()
+	original.GlobalPosition = 41
+	original.Metadata = Metadata{
+		ReplyStreamName:       replyStreamName,
+		CorrelationStreamName: "order-123",
+	}.ToMap()
+
+	tests := []struct {
+		name        string
+		original    Message
+		reply       Message
+		expectedErr error
+	}{{
+		name:     "writes an Event to the reply stream",
+		original: original,
+		reply:    getSampleInventoryReserved(),
+	}, {
+		name:     "writes a TypedMessage to the reply stream",
+		original: original,
+		reply:    &TypedMessage{ID: uuid7, MessageType: "InventoryReserved", Data: map[string]string{"sku": "abc"}},
+	}, {
+		name:     "writes a StreamMessage to the reply stream",
+		original: original,
+		reply:    &StreamMessage{ID: uuid7, MessageType: "InventoryReserved", Data: map[string]interface{}{"sku": "abc"}},
+	}, {
+		name:        "fails when the original has no reply stream",
+		original:    getSampleReserveInventory(),
+		reply:       getSampleInventoryReserved(),
+		expectedErr: ErrMissingReplyStreamName,
+	}, {
+		name:        "fails to reply with a Command",
+		original:    original,
+		reply:       getSampleCommand(),
+		expectedErr: ErrUnsupportedMessage,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mock_repository.NewMockRepository(ctrl)
+			ctx := context.Background()
+
+			var written *repository.MessageEnvelope
+			if test.expectedErr == nil {
+				mockRepo.
+					EXPECT().
+					WriteMessage(ctx, gomock.Any()).
+					Do(func(_ context.Context, msgEnv *repository.MessageEnvelope) {
+						written = msgEnv
+					})
+			}
+
+			msgStore := NewMessageStoreFromRepository(mockRepo, logrus.New())
+			err := Reply(ctx, msgStore, test.original, test.reply)
+
+			assert.Equal(t, test.expectedErr, err)
+			if test.expectedErr != nil || !assert.NotNil(t, written) {
+				return
+			}
+			assert.Equal(t, replyStreamName, written.StreamName)
+			assert.Equal(t, "inventory:reply", written.StreamCategory)
+			metadata := Metadata{}
+			assert.NoError(t, json.Unmarshal(written.Metadata, &metadata))
+			assert.Equal(t, Metadata{
+				CausationMessageID:             uuid6,
+				CausationMessageStreamName:     "inventory:command",
+				CausationMessageGlobalPosition: 41,
+				CorrelationStreamName:          "order-123",
+			}, metadata)
+		})
+	}
+}
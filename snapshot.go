@@ -0,0 +1,168 @@
+package gomessagestore
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"reflect"
+	"sort"
+
+	"github.com/blackhatbrigade/gomessagestore/repository"
+	"github.com/blackhatbrigade/gomessagestore/streamname"
+)
+
+// SnapshotMessageType is the message type of snapshots written to snapshot streams
+const SnapshotMessageType = "Snapshot"
+
+// Snapshot is a projected state saved along with the version and global position it was projected to
+// ProjectorVersion identifies the projector's reducers and SnapshotVersion; snapshots with any other ProjectorVersion are ignored.
+type Snapshot struct {
+	State            json.RawMessage `json:"state"`
+	Version          int64           `json:"version"`
+	GlobalPosition   int64           `json:"globalPosition"`
+	ProjectorVersion string          `json:"projectorVersion"`
+}
+
+// SnapshotStore saves and loads the latest snapshot of a stream's projection
+// Load returns nil when the stream has no snapshot yet.
+type SnapshotStore interface {
+	Load(ctx context.Context, streamName string) (*Snapshot, error)
+	Save(ctx context.Context, streamName string, snapshot Snapshot) error
+}
+
+// WithSnapshots has the projector save a snapshot of the state after replaying at least interval messages, and start from the latest snapshot on each run
+// Snapshots go to a snapshot stream in the message store, such as account:snapshot-123, unless WithSnapshotStore is used.
+// The state is saved as JSON and read back into the type of DefaultState, so it must round trip through encoding/json.
+func WithSnapshots(interval int) ProjectorOption {
+	return func(proj *projector) {
+		proj.snapshotInterval = interval
+	}
+}
+
+// WithSnapshotStore has the projector save its snapshots to store instead of snapshot streams
+func WithSnapshotStore(store SnapshotStore) ProjectorOption {
+	return func(proj *projector) {
+		proj.snapshotStore = store
+	}
+}
+
+// SnapshotVersion marks snapshots with version; change it whenever a reducer changes what it does, so older snapshots are ignored
+// Adding or removing reducers invalidates snapshots without it.
+func SnapshotVersion(version string) ProjectorOption {
+	return func(proj *projector) {
+		proj.snapshotVersion = version
+	}
+}
+
+// projectorVersion identifies the reducers of the projector and its SnapshotVersion
+func (proj *projector) projectorVersion() string {
+	types := make([]string, len(proj.reducers))
+	for i, reducer := range proj.reducers {
+		types[i] = reducer.Type()
+	}
+	sort.Strings(types)
+
+	hash := sha256.New()
+	for _, msgType := range types {
+		hash.Write([]byte(msgType + "\n"))
+	}
+	hash.Write([]byte("\n" + proj.snapshotVersion))
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// loadSnapshot returns the projection saved in the latest snapshot of the stream, or false when there is none that this projector can use
+func (proj *projector) loadSnapshot(ctx context.Context, streamName string) (Projection, bool, error) {
+	snapshot, err := proj.snapshotStore.Load(ctx, streamName)
+	if err != nil || snapshot == nil {
+		return Projection{}, false, err
+	}
+
+	log := proj.ms.GetLogger().WithField("streamName", streamName)
+	if snapshot.ProjectorVersion != proj.projectorVersion() {
+		log.Debug("Projector: Ignoring snapshot from a different projector version")
+
+		return Projection{}, false, nil
+	}
+
+	state := reflect.New(reflect.TypeOf(proj.defaultState))
+	if err := json.Unmarshal(snapshot.State, state.Interface()); err != nil {
+		log.WithError(err).Warn("Projector: Ignoring snapshot that can't be read into the default state's type")
+
+		return Projection{}, false, nil
+	}
+
+	return Projection{
+		State:          state.Elem().Interface(),
+		Version:        snapshot.Version,
+		GlobalPosition: snapshot.GlobalPosition,
+	}, true, nil
+}
+
+// saveSnapshot saves the projection; failing to save only costs a longer replay next time, so errors are logged rather than returned
+func (proj *projector) saveSnapshot(ctx context.Context, streamName string, projection Projection) {
+	log := proj.ms.GetLogger().WithField("streamName", streamName)
+
+	state, err := json.Marshal(projection.State)
+	if err != nil {
+		log.WithError(err).Warn("Projector: Can't serialize state for a snapshot")
+
+		return
+	}
+
+	err = proj.snapshotStore.Save(ctx, streamName, Snapshot{
+		State:            state,
+		Version:          projection.Version,
+		GlobalPosition:   projection.GlobalPosition,
+		ProjectorVersion: proj.projectorVersion(),
+	})
+	if err != nil {
+		log.WithError(err).Warn("Projector: Can't save snapshot")
+	}
+}
+
+// streamSnapshotStore keeps snapshots in snapshot streams, next to the streams they are snapshots of
+type streamSnapshotStore struct {
+	ms MessageStore
+}
+
+// snapshotStreamName names the snapshot stream of a stream, as in account:snapshot-123 for account-123
+func snapshotStreamName(streamName string) string {
+	return streamname.Compose(streamname.AddTypes(streamname.Category(streamName), streamname.SnapshotType), streamname.ID(streamName))
+}
+
+// Load reads the last snapshot in the snapshot stream
+func (store *streamSnapshotStore) Load(ctx context.Context, streamName string) (*Snapshot, error) {
+	var snapshot *Snapshot
+	readSnapshot := func(msgEnv *repository.MessageEnvelope) (Message, error) {
+		if msgEnv.MessageType != SnapshotMessageType {
+			return nil, nil
+		}
+		// read the data here, as converting it to a map would round large numbers in the state
+		read := &Snapshot{}
+		if err := json.Unmarshal(msgEnv.Data, read); err != nil {
+			return nil, err
+		}
+		snapshot = read
+		return ConvertEnvelopeToStreamMessage(msgEnv)
+	}
+
+	_, err := store.ms.Get(ctx, Stream(snapshotStreamName(streamName)), Last(), Converter(readSnapshot))
+	return snapshot, err
+}
+
+// Save writes the snapshot to the snapshot stream
+func (store *streamSnapshotStore) Save(ctx context.Context, streamName string, snapshot Snapshot) error {
+	return store.ms.Write(ctx, &StreamMessage{
+		ID:          NewID(),
+		StreamName:  snapshotStreamName(streamName),
+		MessageType: SnapshotMessageType,
+		Data: map[string]interface{}{
+			"state":            snapshot.State,
+			"version":          snapshot.Version,
+			"globalPosition":   snapshot.GlobalPosition,
+			"projectorVersion": snapshot.ProjectorVersion,
+		},
+	})
+}
@@ -0,0 +1,293 @@
+package gomessagestore_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	. "github.com/blackhatbrigade/gomessagestore"
+	"github.com/blackhatbrigade/gomessagestore/inmem_repository"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+type balance struct {
+	Amount   float64 `json:"amount"`
+	Deposits int     `json:"deposits"`
+}
+
+func reduceDeposit(msg Message, previousState interface{}) interface{} {
+	state := previousState.(balance)
+	state.Amount += msg.(*Event).Data["amount"].(float64)
+	state.Deposits++
+	return state
+}
+
+func getSnapshotProjectorOptions(opts ...ProjectorOption) []ProjectorOption {
+	return append([]ProjectorOption{
+		DefaultState(balance{}),
+		WithReducerFunc("Deposited", reduceDeposit),
+	}, opts...)
+}
+
+func writeDeposits(t *testing.T, msgStore MessageStore, amounts ...float64) {
+	for _, amount := range amounts {
+		err := msgStore.Write(context.Background(), &Event{
+			ID:             NewID(),
+			MessageType:    "Deposited",
+			EntityID:       uuid8,
+			StreamCategory: "account",
+			Data:           map[string]interface{}{"amount": amount},
+		})
+		if err != nil {
+			t.Fatalf("Error writing deposit: %s", err)
+		}
+	}
+}
+
+func getSnapshots(t *testing.T, msgStore MessageStore) []Snapshot {
+	msgs, err := msgStore.Get(context.Background(), Stream("account:snapshot-"+uuid8.String()))
+	if err != nil {
+		t.Fatalf("Error reading snapshots: %s", err)
+	}
+
+	snapshots := make([]Snapshot, len(msgs))
+	for i, msg := range msgs {
+		data, err := json.Marshal(msg.(*StreamMessage).Data)
+		assert.NoError(t, err)
+		assert.NoError(t, json.Unmarshal(data, &snapshots[i]))
+	}
+	return snapshots
+}
+
+func TestProjectorSavesSnapshots(t *testing.T) {
+	tests := []struct {
+		name              string
+		deposits          []float64
+		expectedSnapshots int
+	}{{
+		name:     "doesn't save a snapshot before the interval is reached",
+		deposits: []float64{1, 2},
+	}, {
+		name:              "saves a snapshot once the interval is reached",
+		deposits:          []float64{1, 2, 3, 4},
+		expectedSnapshots: 1,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+			msgStore := NewMessageStoreFromRepository(inmem_repository.NewInMemoryRepository(nil), logrus.New())
+			writeDeposits(t, msgStore, test.deposits...)
+
+			projector, err := msgStore.CreateProjector(getSnapshotProjectorOptions(WithSnapshots(3))...)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			projection, err := projector.RunWithVersion(ctx, "account", uuid8)
+
+			assert.NoError(t, err)
+			assert.Equal(t, int64(len(test.deposits)-1), projection.Version)
+			snapshots := getSnapshots(t, msgStore)
+			if assert.Len(t, snapshots, test.expectedSnapshots) && test.expectedSnapshots > 0 {
+				assert.Equal(t, projection.Version, snapshots[0].Version)
+				assert.Equal(t, projection.GlobalPosition, snapshots[0].GlobalPosition)
+				assert.JSONEq(t, `{"amount": 10, "deposits": 4}`, string(snapshots[0].State))
+			}
+		})
+	}
+}
+
+func TestProjectorStartsFromTheLatestSnapshot(t *testing.T) {
+	ctx := context.Background()
+	msgStore := NewMessageStoreFromRepository(inmem_repository.NewInMemoryRepository(nil), logrus.New())
+	writeDeposits(t, msgStore, 1, 2, 3)
+
+	projector, err := msgStore.CreateProjector(getSnapshotProjectorOptions(WithSnapshots(3))...)
+	if !assert.NoError(t, err) {
+		return
+	}
+	_, err = projector.Run(ctx, "account", uuid8)
+	assert.NoError(t, err)
+	snapshots := getSnapshots(t, msgStore)
+	if !assert.Len(t, snapshots, 1) {
+		return
+	}
+
+	// a later snapshot, with a state the deposits couldn't give, shows which snapshot the projector started from
+	err = msgStore.Write(ctx, &StreamMessage{
+		ID:          NewID(),
+		StreamName:  "account:snapshot-" + uuid8.String(),
+		MessageType: SnapshotMessageType,
+		Data: map[string]interface{}{
+			"state":            map[string]interface{}{"amount": 1000, "deposits": 3},
+			"version":          snapshots[0].Version,
+			"globalPosition":   snapshots[0].GlobalPosition,
+			"projectorVersion": snapshots[0].ProjectorVersion,
+		},
+	})
+	assert.NoError(t, err)
+
+	writeDeposits(t, msgStore, 4)
+	projection, err := projector.RunWithVersion(ctx, "account", uuid8)
+
+	assert.NoError(t, err)
+	assert.Equal(t, balance{Amount: 1004, Deposits: 4}, projection.State)
+	assert.Equal(t, int64(3), projection.Version)
+	assert.Len(t, getSnapshots(t, msgStore), 2, "one replayed message is below the interval")
+}
+
+func TestProjectorStartsFromSnapshotsWithOtherCodecs(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []MessageStoreOption
+	}{{
+		name: "when every message type is written with another codec",
+		opts: []MessageStoreOption{WithCodec(MessagePackCodec)},
+	}, {
+		name: "when snapshots are asked to be written with another codec",
+		opts: []MessageStoreOption{WithMessageTypeCodec(SnapshotMessageType, MessagePackCodec)},
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+			msgStore := NewMessageStoreFromRepository(inmem_repository.NewInMemoryRepository(nil), logrus.New(), test.opts...)
+			writeDeposits(t, msgStore, 1, 2, 3)
+
+			projector, err := msgStore.CreateProjector(getSnapshotProjectorOptions(WithSnapshots(3))...)
+			if !assert.NoError(t, err) {
+				return
+			}
+			_, err = projector.Run(ctx, "account", uuid8)
+			assert.NoError(t, err)
+
+			writeDeposits(t, msgStore, 4)
+			projection, err := projector.RunWithVersion(ctx, "account", uuid8)
+
+			assert.NoError(t, err)
+			assert.Equal(t, balance{Amount: 10, Deposits: 4}, projection.State)
+			assert.Len(t, getSnapshots(t, msgStore), 1, "starting from the snapshot replays one message, which is below the interval")
+		})
+	}
+}
+
+func TestProjectorIgnoresSnapshotsItCantUse(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     []ProjectorOption
+		snapshot func(snapshot *Snapshot)
+	}{{
+		name: "from a projector with other reducers",
+		opts: []ProjectorOption{WithReducerFunc("Withdrawn", reduceDeposit)},
+	}, {
+		name: "from another snapshot version",
+		opts: []ProjectorOption{SnapshotVersion("2")},
+	}, {
+		name: "with state that doesn't fit the default state",
+		snapshot: func(snapshot *Snapshot) {
+			snapshot.State = json.RawMessage(`"not a balance"`)
+		},
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+			msgStore := NewMessageStoreFromRepository(inmem_repository.NewInMemoryRepository(nil), logrus.New())
+			writeDeposits(t, msgStore, 1, 2, 3)
+
+			store := &memorySnapshotStore{snapshots: make(map[string]Snapshot)}
+			projector, err := msgStore.CreateProjector(getSnapshotProjectorOptions(WithSnapshots(3), WithSnapshotStore(store))...)
+			if !assert.NoError(t, err) {
+				return
+			}
+			_, err = projector.Run(ctx, "account", uuid8)
+			assert.NoError(t, err)
+
+			// a snapshot this projector could use would skip all three deposits
+			snapshot := store.snapshots["account-"+uuid8.String()]
+			snapshot.State = json.RawMessage(`{"amount": 1000, "deposits": 3}`)
+			if test.snapshot != nil {
+				test.snapshot(&snapshot)
+			}
+			store.snapshots["account-"+uuid8.String()] = snapshot
+
+			changed, err := msgStore.CreateProjector(getSnapshotProjectorOptions(append(test.opts, WithSnapshots(3), WithSnapshotStore(store))...)...)
+			if !assert.NoError(t, err) {
+				return
+			}
+			state, err := changed.Run(ctx, "account", uuid8)
+
+			assert.NoError(t, err)
+			assert.Equal(t, balance{Amount: 6, Deposits: 3}, state)
+		})
+	}
+}
+
+func TestProjectorRunsWhenSnapshotsCantBeSaved(t *testing.T) {
+	ctx := context.Background()
+	msgStore := NewMessageStoreFromRepository(inmem_repository.NewInMemoryRepository(nil), logrus.New())
+	writeDeposits(t, msgStore, 1, 2, 3)
+
+	store := &memorySnapshotStore{saveErr: errors.New("snapshot store is down")}
+	projector, err := msgStore.CreateProjector(getSnapshotProjectorOptions(WithSnapshots(1), WithSnapshotStore(store))...)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	state, err := projector.Run(ctx, "account", uuid8)
+
+	assert.NoError(t, err)
+	assert.Equal(t, balance{Amount: 6, Deposits: 3}, state)
+}
+
+func TestProjectorFailsWhenSnapshotsCantBeLoaded(t *testing.T) {
+	msgStore := NewMessageStoreFromRepository(inmem_repository.NewInMemoryRepository(nil), logrus.New())
+	loadErr := errors.New("snapshot store is down")
+
+	store := &memorySnapshotStore{loadErr: loadErr}
+	projector, err := msgStore.CreateProjector(getSnapshotProjectorOptions(WithSnapshots(1), WithSnapshotStore(store))...)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	_, err = projector.Run(context.Background(), "account", uuid8)
+
+	assert.Equal(t, loadErr, err)
+}
+
+func TestCreateProjectorFailsWithNegativeSnapshotInterval(t *testing.T) {
+	msgStore := NewMessageStoreFromRepository(inmem_repository.NewInMemoryRepository(nil), logrus.New())
+
+	projector, err := msgStore.CreateProjector(getSnapshotProjectorOptions(WithSnapshots(-1))...)
+
+	assert.Equal(t, ErrInvalidSnapshotInterval, err)
+	assert.Nil(t, projector)
+}
+
+type memorySnapshotStore struct {
+	snapshots map[string]Snapshot
+	loadErr   error
+	saveErr   error
+}
+
+func (store *memorySnapshotStore) Load(ctx context.Context, streamName string) (*Snapshot, error) {
+	if store.loadErr != nil {
+		return nil, store.loadErr
+	}
+	snapshot, ok := store.snapshots[streamName]
+	if !ok {
+		return nil, nil
+	}
+	return &snapshot, nil
+}
+
+func (store *memorySnapshotStore) Save(ctx context.Context, streamName string, snapshot Snapshot) error {
+	if store.saveErr != nil {
+		return store.saveErr
+	}
+	store.snapshots[streamName] = snapshot
+	return nil
+}
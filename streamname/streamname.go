@@ -0,0 +1,119 @@
+//Package streamname composes and parses stream names the way Eventide and Message DB do.
+//
+//A stream name is a category, optionally followed by a hyphen and an ID:
+//
+//	account                      a category
+//	account-123                  an entity stream in the account category
+//	account:command              a category with the command type
+//	account:command+position-123 a category with two types, and an entity ID
+//	account-123+456              an entity stream with a compound ID; 123 is its cardinal ID
+//
+//The category is everything before the first hyphen, so IDs (such as UUIDs) may contain hyphens, but categories may not.
+package streamname
+
+import "strings"
+
+//CommandType is the type of command streams, as in account:command
+const CommandType = "command"
+
+//SnapshotType is the type of snapshot streams, as in account:snapshot
+const SnapshotType = "snapshot"
+
+//PositionType is the type of position streams, as in account:position
+const PositionType = "position"
+
+const (
+	idSeparator       = "-"
+	typeSeparator     = ":"
+	compoundSeparator = "+"
+)
+
+//Compose builds a stream name from a category and IDs; more than one ID makes a compound ID, and no IDs leaves just the category
+func Compose(category string, ids ...string) string {
+	if len(ids) == 0 {
+		return category
+	}
+
+	return category + idSeparator + Compound(ids...)
+}
+
+//AddTypes adds types to a category, after any types it already has: AddTypes("account:command", "position") is "account:command+position"
+func AddTypes(category string, types ...string) string {
+	if len(types) == 0 {
+		return category
+	}
+
+	allTypes := append(Types(category), types...)
+	return EntityName(category) + typeSeparator + Compound(allTypes...)
+}
+
+//Compound joins parts the way compound IDs and types are joined: Compound("123", "456") is "123+456"
+func Compound(parts ...string) string {
+	return strings.Join(parts, compoundSeparator)
+}
+
+//Category returns the category of a stream name, including its types, like Message DB's category() function
+func Category(streamName string) string {
+	return strings.SplitN(streamName, idSeparator, 2)[0]
+}
+
+//EntityName returns the category of a stream name without its types
+func EntityName(streamName string) string {
+	return strings.SplitN(Category(streamName), typeSeparator, 2)[0]
+}
+
+//Types returns the types in the category of a stream name, or nil when it has none
+func Types(streamName string) []string {
+	parts := strings.SplitN(Category(streamName), typeSeparator, 2)
+	if len(parts) < 2 || parts[1] == "" {
+		return nil
+	}
+
+	return strings.Split(parts[1], compoundSeparator)
+}
+
+//HasType reports whether the category of a stream name has the type
+func HasType(streamName string, streamType string) bool {
+	for _, t := range Types(streamName) {
+		if t == streamType {
+			return true
+		}
+	}
+
+	return false
+}
+
+//ID returns everything after the category of a stream name, like Message DB's id() function; it is blank for a category
+func ID(streamName string) string {
+	parts := strings.SplitN(streamName, idSeparator, 2)
+	if len(parts) < 2 {
+		return ""
+	}
+
+	return parts[1]
+}
+
+//IDs returns the parts of a compound ID, or nil for a category
+func IDs(streamName string) []string {
+	id := ID(streamName)
+	if id == "" {
+		return nil
+	}
+
+	return strings.Split(id, compoundSeparator)
+}
+
+//CardinalID returns the first part of the ID of a stream name, like Message DB's cardinal_id() function
+func CardinalID(streamName string) string {
+	return strings.SplitN(ID(streamName), compoundSeparator, 2)[0]
+}
+
+//IsPositionStream reports whether the stream name is a subscriber's position stream, as in someSubscriber+position
+func IsPositionStream(streamName string) bool {
+	return strings.HasSuffix(streamName, Compound("", PositionType))
+}
+
+//IsCategory reports whether the stream name is a category rather than an entity stream
+func IsCategory(streamName string) bool {
+	return !strings.Contains(streamName, idSeparator)
+}
@@ -0,0 +1,137 @@
+package streamname_test
+
+import (
+	"testing"
+
+	. "github.com/blackhatbrigade/gomessagestore/streamname"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompose(t *testing.T) {
+	tests := []struct {
+		name     string
+		category string
+		ids      []string
+		expected string
+	}{{
+		name:     "a category without IDs is just the category",
+		category: "account",
+		expected: "account",
+	}, {
+		name:     "an ID follows a hyphen",
+		category: "account",
+		ids:      []string{"123"},
+		expected: "account-123",
+	}, {
+		name:     "several IDs make a compound ID",
+		category: "account:command",
+		ids:      []string{"123", "456"},
+		expected: "account:command-123+456",
+	}, {
+		name:     "IDs may contain hyphens",
+		category: "account",
+		ids:      []string{"00000000-0000-0000-0000-000000000001"},
+		expected: "account-00000000-0000-0000-0000-000000000001",
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, Compose(test.category, test.ids...))
+		})
+	}
+}
+
+func TestAddTypes(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal("account", AddTypes("account"))
+	assert.Equal("account:command", AddTypes("account", CommandType))
+	assert.Equal("account:command+position", AddTypes("account", CommandType, "position"))
+	assert.Equal("account:command+position", AddTypes("account:command", "position"))
+}
+
+func TestCompound(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal("", Compound())
+	assert.Equal("123", Compound("123"))
+	assert.Equal("123+456", Compound("123", "456"))
+	assert.Equal("some id+position", Compound("some id", PositionType))
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		streamName         string
+		expectedCategory   string
+		expectedEntityName string
+		expectedTypes      []string
+		expectedID         string
+		expectedIDs        []string
+		expectedCardinalID string
+		expectedIsCategory bool
+	}{{
+		streamName:         "account",
+		expectedCategory:   "account",
+		expectedEntityName: "account",
+		expectedIsCategory: true,
+	}, {
+		streamName:         "account-123",
+		expectedCategory:   "account",
+		expectedEntityName: "account",
+		expectedID:         "123",
+		expectedIDs:        []string{"123"},
+		expectedCardinalID: "123",
+	}, {
+		streamName:         "account:command",
+		expectedCategory:   "account:command",
+		expectedEntityName: "account",
+		expectedTypes:      []string{"command"},
+		expectedIsCategory: true,
+	}, {
+		streamName:         "account:command+position-123+456",
+		expectedCategory:   "account:command+position",
+		expectedEntityName: "account",
+		expectedTypes:      []string{"command", "position"},
+		expectedID:         "123+456",
+		expectedIDs:        []string{"123", "456"},
+		expectedCardinalID: "123",
+	}, {
+		streamName:         "account-00000000-0000-0000-0000-000000000001",
+		expectedCategory:   "account",
+		expectedEntityName: "account",
+		expectedID:         "00000000-0000-0000-0000-000000000001",
+		expectedIDs:        []string{"00000000-0000-0000-0000-000000000001"},
+		expectedCardinalID: "00000000-0000-0000-0000-000000000001",
+	}}
+
+	for _, test := range tests {
+		t.Run(test.streamName, func(t *testing.T) {
+			assert := assert.New(t)
+
+			assert.Equal(test.expectedCategory, Category(test.streamName))
+			assert.Equal(test.expectedEntityName, EntityName(test.streamName))
+			assert.Equal(test.expectedTypes, Types(test.streamName))
+			assert.Equal(test.expectedID, ID(test.streamName))
+			assert.Equal(test.expectedIDs, IDs(test.streamName))
+			assert.Equal(test.expectedCardinalID, CardinalID(test.streamName))
+			assert.Equal(test.expectedIsCategory, IsCategory(test.streamName))
+		})
+	}
+}
+
+func TestHasType(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.True(HasType("account:command+position-123", "position"))
+	assert.True(HasType("account:command", CommandType))
+	assert.False(HasType("account-123", CommandType))
+	assert.False(HasType("account-x:command", CommandType))
+}
+
+func TestIsPositionStream(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.True(IsPositionStream("someSubscriber+position"))
+	assert.False(IsPositionStream("account-123"))
+	assert.False(IsPositionStream("account:position-123"))
+}
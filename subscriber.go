@@ -12,6 +12,9 @@ import (
 // Subscriber allows for reaching out to the message service on a continual basis
 type Subscriber interface {
 	Start(context.Context) error
+	Pause()                                                  // stops polling until Resume is called; a poll already in progress is allowed to finish
+	Resume()                                                 // continues polling after a call to Pause
+	ResetPosition(ctx context.Context, position int64) error // writes a new position for the subscriber, which is used from the next poll onward
 }
 
 type subscriber struct {
@@ -20,6 +23,7 @@ type subscriber struct {
 	ms           MessageStore
 	handlers     []MessageHandler
 	subscriberID string
+	paused       int32 // set to 1 while paused; accessed atomically so controls can be called from any goroutine
 }
 
 // CreateSubscriber creates a new Subscriber
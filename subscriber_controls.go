@@ -0,0 +1,34 @@
+package gomessagestore
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// Pause stops the subscriber from polling until Resume is called
+func (sub *subscriber) Pause() {
+	atomic.StoreInt32(&sub.paused, 1)
+	sub.config.log.Info("Subscriber paused")
+}
+
+// Resume allows a paused subscriber to continue polling
+func (sub *subscriber) Resume() {
+	atomic.StoreInt32(&sub.paused, 0)
+	sub.config.log.Info("Subscriber resumed")
+}
+
+// ResetPosition writes a new position record for the subscriber and makes the poller start from it
+func (sub *subscriber) ResetPosition(ctx context.Context, position int64) error {
+	if err := sub.poller.ResetPosition(ctx, position); err != nil {
+		sub.config.log.WithError(err).Error("Subscriber failed to reset position")
+		return err
+	}
+
+	sub.config.log.WithField("position", position).Info("Subscriber position reset")
+	return nil
+}
+
+// isPaused reports whether Pause has been called without a matching Resume
+func (sub *subscriber) isPaused() bool {
+	return atomic.LoadInt32(&sub.paused) == 1
+}
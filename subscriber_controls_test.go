@@ -0,0 +1,114 @@
+package gomessagestore_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/blackhatbrigade/gomessagestore"
+	mock_gomessagestore "github.com/blackhatbrigade/gomessagestore/mocks"
+	mock_repository "github.com/blackhatbrigade/gomessagestore/repository/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/sirupsen/logrus"
+)
+
+func TestSubscriberPauseAndResume(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	mockRepo := mock_repository.NewMockRepository(ctrl)
+	mockPoller := mock_gomessagestore.NewMockPoller(ctrl)
+
+	count := make(chan int, 100)
+	mockPoller.
+		EXPECT().
+		Poll(ctx).
+		Do(func(ctx context.Context) {
+			count <- 1
+		}).
+		Return(nil).
+		AnyTimes()
+
+	myMessageStore := NewMessageStoreFromRepository(mockRepo, logrus.New())
+	mySubscriber, err := CreateSubscriberWithPoller(
+		myMessageStore,
+		"someid",
+		[]MessageHandler{&msgHandler{}},
+		mockPoller,
+		SubscribeToCategory("category"),
+		PollTime(5*time.Millisecond),
+	)
+	if err != nil {
+		t.Fatalf("Failed on CreateSubscriber() Got: %s\n", err)
+	}
+
+	mySubscriber.Pause()
+	go mySubscriber.Start(ctx)
+
+	select {
+	case <-count:
+		t.Error("Poll() was called while the subscriber was paused")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	mySubscriber.Resume()
+
+	select {
+	case <-count:
+	case <-time.After(1 * time.Second):
+		t.Error("Poll() was not called after the subscriber was resumed")
+	}
+}
+
+func TestSubscriberResetPosition(t *testing.T) {
+	tests := []struct {
+		name          string
+		position      int64
+		pollerError   error
+		expectedError error
+	}{{
+		name:     "ResetPosition passes the position on to the poller",
+		position: 42,
+	}, {
+		name:          "ResetPosition returns poller errors",
+		position:      42,
+		pollerError:   potato,
+		expectedError: potato,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			ctx := context.Background()
+			mockRepo := mock_repository.NewMockRepository(ctrl)
+			mockPoller := mock_gomessagestore.NewMockPoller(ctrl)
+
+			mockPoller.
+				EXPECT().
+				ResetPosition(ctx, test.position).
+				Return(test.pollerError)
+
+			myMessageStore := NewMessageStoreFromRepository(mockRepo, logrus.New())
+			mySubscriber, err := CreateSubscriberWithPoller(
+				myMessageStore,
+				"someid",
+				[]MessageHandler{&msgHandler{}},
+				mockPoller,
+				SubscribeToCategory("category"),
+			)
+			if err != nil {
+				t.Fatalf("Failed on CreateSubscriber() Got: %s\n", err)
+			}
+
+			err = mySubscriber.ResetPosition(ctx, test.position)
+			if err != test.expectedError {
+				t.Errorf("Failed on ResetPosition()\nWant: %v\nHave: %v\n", test.expectedError, err)
+			}
+		})
+	}
+}
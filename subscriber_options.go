@@ -12,27 +12,57 @@ type SubscriberOption func(config *SubscriberConfig) error
 
 // SubscriberConfig contains configuration information for a subscriber
 type SubscriberConfig struct {
-	entityID        uuid.UUID
+	entityID        string // the ID of the entity stream subscribed to
 	stream          bool
 	category        string
+	categories      []string // the categories subscribed to, read together in global position order
+	allStreams      bool     // when set, every stream in the store is subscribed to, read in global position order
 	commandCategory string
 	pollTime        time.Duration // the time interval between polling operations
 	pollErrorDelay  time.Duration // the time interval to wait after an error occurs during a poll operation
-	updateInterval  int           //
+	updateInterval  int           // how many handled messages between saving the position
 	batchSize       int           // the maximum amount of messages to be retrieved at a time
 	position        int64         // the position from which to retrieve messages
 	log             logrus.FieldLogger
 	errorFunc       func(error)
+
+	catchUp               bool   // when set, the subscriber uses the catch-up settings until it first reaches the head
+	catchUpBatchSize      int    // the maximum amount of messages to be retrieved at a time while catching up
+	catchUpUpdateInterval int    // how often the position is saved while catching up
+	onCaughtUp            func() // called once, the first time a poll finds no more waiting messages
+
+	adaptive          bool          // when set, the time between polls depends on how many messages the last poll found
+	maxPollTime       time.Duration // the longest time to wait between polls while idle, when adaptive
+	maxPollErrorDelay time.Duration // the longest time to wait after repeated poll errors, when adaptive
+	pollJitter        float64       // the fraction by which every wait is randomly moved up or down
+
+	checkpointInterval time.Duration // the longest time between saving the position while messages are being handled
+
+	startTime time.Time // when set, a subscriber without a saved position starts at the first message written at or after this time
 }
 
 //SubscribeToEntityStream subscribes to a specific entity stream and ensures that multiple streams are not subscribed to
 func SubscribeToEntityStream(category string, entityID uuid.UUID) SubscriberOption {
+	if entityID == NilUUID {
+		return SubscribeToEntityStreamID(category, "")
+	}
+	return SubscribeToEntityStreamID(category, entityID.String())
+}
+
+//SubscribeToEntityStreamID subscribes to a specific entity stream whose ID is not a UUID, such as order-ORD123, and ensures that multiple streams are not subscribed to
+func SubscribeToEntityStreamID(category string, streamID string) SubscriberOption {
 	return func(sub *SubscriberConfig) error {
 		if sub.stream {
 			return ErrSubscriberCannotSubscribeToMultipleStreams
 		}
-		if category != "" && entityID != NilUUID {
-			sub.entityID = entityID
+		if len(sub.categories) > 0 {
+			return ErrSubscriberCannotUseBothStreamAndCategory
+		}
+		if sub.allStreams {
+			return ErrSubscriberAllStreamsCannotBeCombined
+		}
+		if category != "" && streamID != "" {
+			sub.entityID = streamID
 			sub.category = category
 			sub.stream = true
 		}
@@ -46,9 +76,12 @@ func SubscribeToCommandStream(category string) SubscriberOption {
 		if sub.stream {
 			return ErrSubscriberCannotSubscribeToMultipleStreams
 		}
-		if sub.category != "" {
+		if len(sub.categories) > 0 {
 			return ErrSubscriberCannotUseBothStreamAndCategory
 		}
+		if sub.allStreams {
+			return ErrSubscriberAllStreamsCannotBeCombined
+		}
 		if category != "" {
 			sub.commandCategory = category
 			sub.stream = true
@@ -57,16 +90,86 @@ func SubscribeToCommandStream(category string) SubscriberOption {
 	}
 }
 
-//SubscribeToCategory subscribes to a category of streams and ensures that it is not also subscribed to a stream
+//SubscribeToEntityCommandStream subscribes to the command stream of a single entity and ensures that multiple streams are not subscribed to
+func SubscribeToEntityCommandStream(category string, entityID uuid.UUID) SubscriberOption {
+	if entityID == NilUUID {
+		return SubscribeToEntityCommandStreamID(category, "")
+	}
+	return SubscribeToEntityCommandStreamID(category, entityID.String())
+}
+
+//SubscribeToEntityCommandStreamID subscribes to the command stream of a single entity whose ID is not a UUID, and ensures that multiple streams are not subscribed to
+func SubscribeToEntityCommandStreamID(category string, streamID string) SubscriberOption {
+	return func(sub *SubscriberConfig) error {
+		if sub.stream {
+			return ErrSubscriberCannotSubscribeToMultipleStreams
+		}
+		if len(sub.categories) > 0 {
+			return ErrSubscriberCannotUseBothStreamAndCategory
+		}
+		if sub.allStreams {
+			return ErrSubscriberAllStreamsCannotBeCombined
+		}
+		if category != "" && streamID != "" {
+			sub.entityID = streamID
+			sub.commandCategory = category
+			sub.stream = true
+		}
+		return nil
+	}
+}
+
+//SubscribeToCategory subscribes to a category of streams and ensures that it is not also subscribed to a stream; may be used more than once to read several categories in global position order
 func SubscribeToCategory(category string) SubscriberOption {
 	return func(sub *SubscriberConfig) error {
 		if sub.stream {
 			return ErrSubscriberCannotUseBothStreamAndCategory
 		}
-		if sub.category != "" {
-			return ErrSubscriberCannotSubscribeToMultipleCategories
+		if sub.allStreams {
+			return ErrSubscriberAllStreamsCannotBeCombined
+		}
+		for _, existing := range sub.categories {
+			if existing == category {
+				return ErrSubscriberCannotSubscribeToSameCategoryTwice
+			}
+		}
+		if category != "" {
+			sub.categories = append(sub.categories, category)
+		}
+		return nil
+	}
+}
+
+//SubscribeToCategories subscribes to several categories of streams at once, read in global position order with a single position
+func SubscribeToCategories(categories ...string) SubscriberOption {
+	return func(sub *SubscriberConfig) error {
+		for _, category := range categories {
+			if err := SubscribeToCategory(category)(sub); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+//SubscribeToAllStreams subscribes to every stream in the store, read in global position order; cannot be combined with a stream or category subscription
+func SubscribeToAllStreams() SubscriberOption {
+	return func(sub *SubscriberConfig) error {
+		if sub.stream || len(sub.categories) > 0 {
+			return ErrSubscriberAllStreamsCannotBeCombined
+		}
+		sub.allStreams = true
+		return nil
+	}
+}
+
+// SubscribeSince starts a subscriber that has no saved position at the first message written at or after startTime, instead of at the beginning
+func SubscribeSince(startTime time.Time) SubscriberOption {
+	return func(sub *SubscriberConfig) error {
+		if startTime.IsZero() {
+			return ErrInvalidSubscriberStartTime
 		}
-		sub.category = category
+		sub.startTime = startTime
 		return nil
 	}
 }
@@ -95,6 +198,17 @@ func UpdatePositionEvery(msgInterval int) SubscriberOption {
 	}
 }
 
+// CheckpointEvery saves the position of the worker once this much time has passed since the last save, even when UpdatePositionEvery has not been reached; whichever comes first wins
+func CheckpointEvery(checkpointInterval time.Duration) SubscriberOption {
+	return func(sub *SubscriberConfig) error {
+		if checkpointInterval <= 0 {
+			return ErrInvalidCheckpointInterval
+		}
+		sub.checkpointInterval = checkpointInterval
+		return nil
+	}
+}
+
 // SubscribeBatchSize sets the amount of messages to retrieve in a single handling operation
 func SubscribeBatchSize(batchSize int) SubscriberOption {
 	return func(sub *SubscriberConfig) error {
@@ -112,6 +226,7 @@ func GetSubscriberConfig(opts ...SubscriberOption) (*SubscriberConfig, error) {
 		pollTime:       200 * time.Millisecond,
 		pollErrorDelay: 5 * time.Second,
 		updateInterval: 100,
+		batchSize:      1000,
 	}
 
 	for _, option := range opts {
@@ -123,7 +238,7 @@ func GetSubscriberConfig(opts ...SubscriberOption) (*SubscriberConfig, error) {
 		}
 	}
 
-	if !config.stream && config.category == "" {
+	if !config.stream && len(config.categories) == 0 && !config.allStreams {
 		return nil, ErrSubscriberNeedsCategoryOrStream
 	}
 	if config.pollTime <= 0 {
@@ -135,6 +250,37 @@ func GetSubscriberConfig(opts ...SubscriberOption) (*SubscriberConfig, error) {
 	if config.updateInterval < 2 {
 		return nil, ErrInvalidMsgInterval
 	}
+	if config.adaptive {
+		if config.maxPollTime < config.pollTime {
+			return nil, ErrInvalidMaxPollTime
+		}
+		if config.maxPollErrorDelay == 0 {
+			config.maxPollErrorDelay = time.Minute
+			if config.pollErrorDelay > config.maxPollErrorDelay {
+				config.maxPollErrorDelay = config.pollErrorDelay
+			}
+		}
+		if config.maxPollErrorDelay < config.pollErrorDelay {
+			return nil, ErrInvalidMaxPollErrorDelay
+		}
+	}
+	if config.pollJitter < 0 || config.pollJitter >= 1 {
+		return nil, ErrInvalidPollJitter
+	}
+	if config.catchUp {
+		if config.catchUpBatchSize == 0 {
+			config.catchUpBatchSize = config.batchSize
+		}
+		if config.catchUpUpdateInterval == 0 {
+			config.catchUpUpdateInterval = config.updateInterval
+		}
+		if config.catchUpBatchSize < 1 {
+			return nil, ErrInvalidBatchSize
+		}
+		if config.catchUpUpdateInterval < 2 {
+			return nil, ErrInvalidMsgInterval
+		}
+	}
 	if config.log == nil {
 		config.log = logrus.New()
 	}
@@ -157,3 +303,57 @@ func OnError(errorFunc func(error)) SubscriberOption {
 		return nil
 	}
 }
+
+// CatchUpBatchSize enables catch-up mode and sets the amount of messages to retrieve in a single handling operation until the subscriber first reaches the head
+func CatchUpBatchSize(batchSize int) SubscriberOption {
+	return func(sub *SubscriberConfig) error {
+		if batchSize < 1 {
+			return ErrInvalidBatchSize
+		}
+		sub.catchUp = true
+		sub.catchUpBatchSize = batchSize
+		return nil
+	}
+}
+
+// CatchUpUpdatePositionEvery enables catch-up mode and sets how often the position is saved until the subscriber first reaches the head; must be >= 2
+func CatchUpUpdatePositionEvery(msgInterval int) SubscriberOption {
+	return func(sub *SubscriberConfig) error {
+		sub.catchUp = true
+		sub.catchUpUpdateInterval = msgInterval
+		return nil
+	}
+}
+
+// OnCaughtUp is called once, the first time the subscriber has handled every message that was waiting for it
+func OnCaughtUp(caughtUpFunc func()) SubscriberOption {
+	return func(sub *SubscriberConfig) error {
+		sub.onCaughtUp = caughtUpFunc
+		return nil
+	}
+}
+
+// AdaptivePolling polls again right away when a poll returns a full batch, and doubles the time between polls while idle, up to maxPollTime; repeated poll errors also double the error delay, up to MaxPollErrorDelay
+func AdaptivePolling(maxPollTime time.Duration) SubscriberOption {
+	return func(sub *SubscriberConfig) error {
+		sub.adaptive = true
+		sub.maxPollTime = maxPollTime
+		return nil
+	}
+}
+
+// MaxPollErrorDelay sets the longest time to wait after repeated poll errors when using AdaptivePolling (default 1 minute)
+func MaxPollErrorDelay(maxPollErrorDelay time.Duration) SubscriberOption {
+	return func(sub *SubscriberConfig) error {
+		sub.maxPollErrorDelay = maxPollErrorDelay
+		return nil
+	}
+}
+
+// PollJitter randomly moves every wait between polls up or down by at most the given fraction (0 to less than 1), so subscribers don't poll in lockstep
+func PollJitter(fraction float64) SubscriberOption {
+	return func(sub *SubscriberConfig) error {
+		sub.pollJitter = fraction
+		return nil
+	}
+}
@@ -11,16 +11,20 @@ func (sub *subscriber) Start(ctx context.Context) error {
 	// make a channel to handle cancel signal from context in order to stop the infinite loop
 	cancelled := make(chan error, 1)
 	go func() {
+		strategy := newPollStrategy(sub.config)
 		for {
-			err := sub.poller.Poll(ctx)
-			if err != nil {
-				sub.config.log.WithError(err).Error("There is an error with Poller in Start")
-				time.Sleep(sub.config.pollErrorDelay)
+			wait := sub.config.pollTime
+			if !sub.isPaused() {
+				err := sub.poller.Poll(ctx)
+				if err != nil {
+					sub.config.log.WithError(err).Error("There is an error with Poller in Start")
+				}
+				wait = sub.nextPollDelay(strategy, err)
 			}
 			select {
 			case <-cancelled:
 				return
-			case <-time.After(sub.config.pollTime):
+			case <-time.After(wait):
 				// wait between poll
 			}
 		}
@@ -31,3 +35,21 @@ func (sub *subscriber) Start(ctx context.Context) error {
 		return ctx.Err()
 	}
 }
+
+// nextPollDelay works out how long to wait before polling again, based on the outcome of the last poll
+func (sub *subscriber) nextPollDelay(strategy *pollStrategy, err error) time.Duration {
+	if err != nil {
+		return strategy.afterError()
+	}
+
+	if sub.config.catchUp && sub.poller.CatchingUp() {
+		// more messages are waiting, so poll again right away
+		return strategy.afterFullBatch()
+	}
+
+	if !sub.config.adaptive {
+		return strategy.afterPoll()
+	}
+
+	return strategy.afterBatch(sub.poller.LastBatch())
+}
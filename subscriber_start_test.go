@@ -136,3 +136,159 @@ func TestSubscriberStartWithContext(t *testing.T) {
 		})
 	}
 }
+
+func TestSubscriberStartDoesNotWaitWhileCatchingUp(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	mockRepo := mock_repository.NewMockRepository(ctrl)
+	mockPoller := mock_gomessagestore.NewMockPoller(ctrl)
+
+	count := make(chan int, 100)
+	mockPoller.
+		EXPECT().
+		Poll(ctx).
+		Do(func(ctx context.Context) {
+			count <- 1
+		}).
+		Return(nil).
+		AnyTimes()
+
+	catchingUpCalls := 0
+	mockPoller.
+		EXPECT().
+		CatchingUp().
+		DoAndReturn(func() bool {
+			catchingUpCalls++
+			return catchingUpCalls < 4 // the fourth poll reaches the head
+		}).
+		AnyTimes()
+
+	myMessageStore := NewMessageStoreFromRepository(mockRepo, logrus.New())
+	mySubscriber, err := CreateSubscriberWithPoller(
+		myMessageStore,
+		"someid",
+		[]MessageHandler{&msgHandler{}},
+		mockPoller,
+		SubscribeToCategory("category"),
+		CatchUpBatchSize(5000),
+		PollTime(time.Second),
+	)
+	if err != nil {
+		t.Fatalf("Failed on CreateSubscriber() Got: %s\n", err)
+	}
+
+	go mySubscriber.Start(ctx)
+
+	time.Sleep(100 * time.Millisecond)
+	cancel()
+
+	if len(count) != 4 {
+		t.Errorf("Failed to meet expected number of calls to Poll()\nHave: %d\nWant: %d\n", len(count), 4)
+	}
+}
+
+func TestSubscriberStartWithAdaptivePolling(t *testing.T) {
+	tests := []struct {
+		name                string
+		pollError           error
+		lastBatches         [][2]int // received, requested
+		expectedTimesPolled int
+		opts                []SubscriberOption
+		cancelDelay         time.Duration
+	}{{
+		name:                "Polls again right away when a full batch is returned",
+		lastBatches:         [][2]int{{10, 10}, {10, 10}, {10, 10}, {3, 10}},
+		expectedTimesPolled: 4,
+		opts: []SubscriberOption{
+			SubscribeToCategory("category"),
+			PollTime(time.Second),
+			AdaptivePolling(10 * time.Second),
+		},
+		cancelDelay: 100 * time.Millisecond,
+	}, {
+		name:                "Backs off while idle", // polls at 0, 30, 90, 210, then 450 ms
+		lastBatches:         [][2]int{{0, 10}},
+		expectedTimesPolled: 4,
+		opts: []SubscriberOption{
+			SubscribeToCategory("category"),
+			PollTime(30 * time.Millisecond),
+			AdaptivePolling(time.Second),
+		},
+		cancelDelay: 300 * time.Millisecond,
+	}, {
+		name:                "Backs off on repeated errors, up to the max", // polls at 0, 30, 90, 150, then 210 ms
+		pollError:           potato,
+		expectedTimesPolled: 4,
+		opts: []SubscriberOption{
+			SubscribeToCategory("category"),
+			PollTime(1),
+			PollErrorDelay(30 * time.Millisecond),
+			AdaptivePolling(time.Second),
+			MaxPollErrorDelay(60 * time.Millisecond),
+		},
+		cancelDelay: 180 * time.Millisecond,
+	}}
+
+	for _, test := range tests {
+		test := test // the polling goroutine outlives the subtest and reads test, so each subtest needs its own
+		t.Run(test.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			mockRepo := mock_repository.NewMockRepository(ctrl)
+			mockPoller := mock_gomessagestore.NewMockPoller(ctrl)
+
+			count := make(chan int, 100)
+			mockPoller.
+				EXPECT().
+				Poll(ctx).
+				Do(func(ctx context.Context) {
+					count <- 1
+				}).
+				Return(test.pollError).
+				AnyTimes()
+
+			lastBatchCalls := 0
+			mockPoller.
+				EXPECT().
+				LastBatch().
+				DoAndReturn(func() (int, int) {
+					batch := test.lastBatches[len(test.lastBatches)-1]
+					if lastBatchCalls < len(test.lastBatches) {
+						batch = test.lastBatches[lastBatchCalls]
+					}
+					lastBatchCalls++
+					return batch[0], batch[1]
+				}).
+				AnyTimes()
+
+			myMessageStore := NewMessageStoreFromRepository(mockRepo, logrus.New())
+			mySubscriber, err := CreateSubscriberWithPoller(
+				myMessageStore,
+				"someid",
+				[]MessageHandler{&msgHandler{}},
+				mockPoller,
+				test.opts...,
+			)
+			if err != nil {
+				t.Fatalf("Failed on CreateSubscriber() Got: %s\n", err)
+			}
+
+			go mySubscriber.Start(ctx)
+
+			time.Sleep(test.cancelDelay)
+			cancel()
+
+			if len(count) != test.expectedTimesPolled {
+				t.Errorf("Failed to meet expected number of calls to Poll()\nHave: %d\nWant: %d\n", len(count), test.expectedTimesPolled)
+			}
+		})
+	}
+}
@@ -97,6 +97,24 @@ func TestCreateSubscriberOptions(t *testing.T) {
 		opts: []SubscriberOption{
 			SubscribeToCommandStream("some category"),
 		},
+	}, {
+		name: "Subscribe to entity command stream does not return error",
+		opts: []SubscriberOption{
+			SubscribeToEntityCommandStream("some category", uuid1),
+		},
+	}, {
+		name:          "Subscribe to entity command stream, entityID cannot be blank",
+		expectedError: ErrSubscriberNeedsCategoryOrStream,
+		opts: []SubscriberOption{
+			SubscribeToEntityCommandStream("some category", NilUUID),
+		},
+	}, {
+		name:          "Subscribe should only accept one subscription request, (entity command and command)",
+		expectedError: ErrSubscriberCannotSubscribeToMultipleStreams,
+		opts: []SubscriberOption{
+			SubscribeToEntityCommandStream("some category", uuid1),
+			SubscribeToCommandStream("some category"),
+		},
 	}, {
 		name:          "Subscribe to command stream category cannot be blank",
 		expectedError: ErrSubscriberNeedsCategoryOrStream,
@@ -115,6 +133,19 @@ func TestCreateSubscriberOptions(t *testing.T) {
 		opts: []SubscriberOption{
 			SubscribeToEntityStream("some category", NilUUID),
 		},
+	}, {
+		name:          "Subscribe to entity stream, stream ID cannot be blank",
+		expectedError: ErrSubscriberNeedsCategoryOrStream,
+		opts: []SubscriberOption{
+			SubscribeToEntityStreamID("some category", ""),
+		},
+	}, {
+		name:          "Subscribe should only accept one subscription request, (entity by stream ID and entity)",
+		expectedError: ErrSubscriberCannotSubscribeToMultipleStreams,
+		opts: []SubscriberOption{
+			SubscribeToEntityStreamID("some category", "ORD123"),
+			SubscribeToEntityStream("some category", uuid1),
+		},
 	}, {
 		name:          "Subscribe to category stream, category cannot be blank",
 		expectedError: ErrSubscriberNeedsCategoryOrStream,
@@ -129,11 +160,77 @@ func TestCreateSubscriberOptions(t *testing.T) {
 			SubscribeToEntityStream("some category", uuid1),
 		},
 	}, {
-		name:          "Subscribe should only accept one category subscription request, (command and entity)",
-		expectedError: ErrSubscriberCannotSubscribeToMultipleCategories,
+		name:          "Subscribe should not accept the same category twice",
+		expectedError: ErrSubscriberCannotSubscribeToSameCategoryTwice,
+		opts: []SubscriberOption{
+			SubscribeToCategory("some category"),
+			SubscribeToCategory("some category"),
+		},
+	}, {
+		name: "Subscribe accepts several different categories",
+		opts: []SubscriberOption{
+			SubscribeToCategory("some category"),
+			SubscribeToCategory("some other category"),
+			SubscribeToCategories("third category", "fourth category"),
+		},
+	}, {
+		name:          "Subscribe to categories should not accept the same category twice",
+		expectedError: ErrSubscriberCannotSubscribeToSameCategoryTwice,
+		opts: []SubscriberOption{
+			SubscribeToCategories("some category", "some other category", "some category"),
+		},
+	}, {
+		name: "Subscribe accepts all streams",
+		opts: []SubscriberOption{
+			SubscribeToAllStreams(),
+		},
+	}, {
+		name:          "all streams and a category cannot both be set",
+		expectedError: ErrSubscriberAllStreamsCannotBeCombined,
+		opts: []SubscriberOption{
+			SubscribeToAllStreams(),
+			SubscribeToCategory("some category"),
+		},
+	}, {
+		name:          "a category and all streams cannot both be set",
+		expectedError: ErrSubscriberAllStreamsCannotBeCombined,
+		opts: []SubscriberOption{
+			SubscribeToCategory("some category"),
+			SubscribeToAllStreams(),
+		},
+	}, {
+		name:          "all streams and an entity stream cannot both be set",
+		expectedError: ErrSubscriberAllStreamsCannotBeCombined,
+		opts: []SubscriberOption{
+			SubscribeToAllStreams(),
+			SubscribeToEntityStream("some category", uuid1),
+		},
+	}, {
+		name:          "a command stream and all streams cannot both be set",
+		expectedError: ErrSubscriberAllStreamsCannotBeCombined,
+		opts: []SubscriberOption{
+			SubscribeToCommandStream("some category"),
+			SubscribeToAllStreams(),
+		},
+	}, {
+		name: "Subscribe accepts a start time",
 		opts: []SubscriberOption{
 			SubscribeToCategory("some category"),
+			SubscribeSince(time.Unix(1000, 0)),
+		},
+	}, {
+		name:          "Subscribe does not accept a zero start time",
+		expectedError: ErrInvalidSubscriberStartTime,
+		opts: []SubscriberOption{
 			SubscribeToCategory("some category"),
+			SubscribeSince(time.Time{}),
+		},
+	}, {
+		name:          "both categories and stream cannot be set",
+		expectedError: ErrSubscriberCannotUseBothStreamAndCategory,
+		opts: []SubscriberOption{
+			SubscribeToCategories("some category", "some other category"),
+			SubscribeToEntityStream("some stream", uuid1),
 		},
 	}, {
 		name:          "Cannot set 0 poll time",
@@ -190,6 +287,81 @@ func TestCreateSubscriberOptions(t *testing.T) {
 			SubscribeBatchSize(-1),
 			SubscribeToCategory("some category"),
 		},
+	}, {
+		name:          "Catch-up batch size cannot be zero",
+		expectedError: ErrInvalidBatchSize,
+		opts: []SubscriberOption{
+			CatchUpBatchSize(0),
+			SubscribeToCategory("some category"),
+		},
+	}, {
+		name:          "Catch-up update position cannot be less than 2 for msgInterval",
+		expectedError: ErrInvalidMsgInterval,
+		opts: []SubscriberOption{
+			CatchUpUpdatePositionEvery(1),
+			SubscribeToCategory("some category"),
+		},
+	}, {
+		name: "Catch-up options with OnCaughtUp don't Error",
+		opts: []SubscriberOption{
+			CatchUpBatchSize(5000),
+			CatchUpUpdatePositionEvery(1000),
+			OnCaughtUp(func() {}),
+			SubscribeToCategory("some category"),
+		},
+	}, {
+		name:          "Adaptive max poll time cannot be less than the poll time",
+		expectedError: ErrInvalidMaxPollTime,
+		opts: []SubscriberOption{
+			PollTime(time.Second),
+			AdaptivePolling(time.Millisecond),
+			SubscribeToCategory("some category"),
+		},
+	}, {
+		name:          "Adaptive max poll error delay cannot be less than the poll error delay",
+		expectedError: ErrInvalidMaxPollErrorDelay,
+		opts: []SubscriberOption{
+			PollErrorDelay(time.Second),
+			AdaptivePolling(time.Minute),
+			MaxPollErrorDelay(time.Millisecond),
+			SubscribeToCategory("some category"),
+		},
+	}, {
+		name:          "Poll jitter cannot be negative",
+		expectedError: ErrInvalidPollJitter,
+		opts: []SubscriberOption{
+			PollJitter(-0.1),
+			SubscribeToCategory("some category"),
+		},
+	}, {
+		name:          "Poll jitter cannot be 1 or more",
+		expectedError: ErrInvalidPollJitter,
+		opts: []SubscriberOption{
+			PollJitter(1),
+			SubscribeToCategory("some category"),
+		},
+	}, {
+		name: "Adaptive polling with jitter doesn't Error",
+		opts: []SubscriberOption{
+			AdaptivePolling(time.Minute),
+			MaxPollErrorDelay(time.Minute),
+			PollJitter(0.2),
+			SubscribeToCategory("some category"),
+		},
+	}, {
+		name:          "Checkpoint interval cannot be zero",
+		expectedError: ErrInvalidCheckpointInterval,
+		opts: []SubscriberOption{
+			CheckpointEvery(0),
+			SubscribeToCategory("some category"),
+		},
+	}, {
+		name: "Checkpoint interval with a message count doesn't Error",
+		opts: []SubscriberOption{
+			CheckpointEvery(time.Minute),
+			UpdatePositionEvery(1000),
+			SubscribeToCategory("some category"),
+		},
 	}, {
 		name: "Logger doesn't Error",
 		opts: []SubscriberOption{
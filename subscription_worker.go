@@ -8,7 +8,7 @@ import (
 
 // SubscriptionWorker handles the processes for retrieving and processing messages from the message store and updating positions
 type SubscriptionWorker interface {
-	GetMessages(ctx context.Context, position int64) ([]Message, error)
+	GetMessages(ctx context.Context, position int64, batchSize int) ([]Message, ReadBatch, error) // the batch read can hold more envelopes than the messages returned, when some can't be read
 	ProcessMessages(ctx context.Context, msgs []Message) (messagesHandled int, positionOfLastHandled int64, err error)
 	GetPosition(ctx context.Context) (int64, error)
 	SetPosition(ctx context.Context, position int64) error
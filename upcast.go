@@ -0,0 +1,151 @@
+package gomessagestore
+
+import (
+	"encoding/json"
+	"math"
+
+	"github.com/blackhatbrigade/gomessagestore/repository"
+)
+
+// SchemaVersionKey is the metadata key holding the schema version of a message's data; messages without it are at version 1
+const SchemaVersionKey = "schemaVersion"
+
+// Upcaster turns the data of a message at one schema version into the shape of the next version
+type Upcaster func(data map[string]interface{}) (map[string]interface{}, error)
+
+// RegisterUpcaster adds the step that upcasts the data of messageType from fromVersion to fromVersion+1
+// Steps are registered in order starting from version 1, and the current schema version is one past the last step.
+func (r *Registry) RegisterUpcaster(messageType string, fromVersion int, upcaster Upcaster) error {
+	if messageType == "" {
+		return ErrMissingMessageType
+	}
+	if upcaster == nil {
+		return ErrMissingUpcaster
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if fromVersion != len(r.upcasters[messageType])+1 {
+		return ErrUpcasterOutOfOrder
+	}
+
+	r.upcasters[messageType] = append(r.upcasters[messageType], upcaster)
+	return nil
+}
+
+// SchemaVersion returns the current schema version of messageType, which Write stamps into the metadata of new messages
+func (r *Registry) SchemaVersion(messageType string) int {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	return len(r.upcasters[messageType]) + 1
+}
+
+// Upcast runs the upcasters of the message type over an envelope's data until it is at the current schema version
+// The envelope passed in is left untouched; a copy is returned when any upcasting was needed.
+func (r *Registry) Upcast(messageEnvelope *repository.MessageEnvelope) (*repository.MessageEnvelope, error) {
+	return r.upcast(messageEnvelope, false)
+}
+
+// upcast runs the upcasters, handing them numbers as json.Number when exactNumbers is set
+func (r *Registry) upcast(messageEnvelope *repository.MessageEnvelope, exactNumbers bool) (*repository.MessageEnvelope, error) {
+	r.mutex.RLock()
+	upcasters := r.upcasters[messageEnvelope.MessageType]
+	r.mutex.RUnlock()
+	if len(upcasters) == 0 {
+		return messageEnvelope, nil // nothing to upcast, so the metadata isn't read
+	}
+
+	metadata, err := decodeMetadata(messageEnvelope.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	version, err := schemaVersion(metadata)
+	if err != nil {
+		return nil, err
+	}
+	if version > len(upcasters) {
+		return messageEnvelope, nil // already current
+	}
+
+	data := make(map[string]interface{})
+	if err := decodeJSON(messageEnvelope.Data, &data, exactNumbers); err != nil {
+		return nil, err
+	}
+	for _, upcaster := range upcasters[version-1:] {
+		if data, err = upcaster(data); err != nil {
+			return nil, err
+		}
+	}
+	metadata[SchemaVersionKey] = len(upcasters) + 1
+
+	upcast := *messageEnvelope
+	if upcast.Data, err = json.Marshal(data); err != nil {
+		return nil, err
+	}
+	if upcast.Metadata, err = json.Marshal(metadata); err != nil {
+		return nil, err
+	}
+
+	return &upcast, nil
+}
+
+// stampSchemaVersion adds the current schema version of the message type to the envelope's metadata, keeping any version already there
+func (r *Registry) stampSchemaVersion(messageEnvelope *repository.MessageEnvelope) error {
+	metadata, err := decodeMetadata(messageEnvelope.Metadata)
+	if err != nil {
+		return err
+	}
+	if _, ok := metadata[SchemaVersionKey]; ok {
+		return nil
+	}
+
+	metadata[SchemaVersionKey] = r.SchemaVersion(messageEnvelope.MessageType)
+	messageEnvelope.Metadata, err = json.Marshal(metadata)
+	return err
+}
+
+// decodeMetadata unmarshals envelope metadata, keeping numbers as they were written so the metadata can be written back unchanged
+func decodeMetadata(raw []byte) (map[string]interface{}, error) {
+	metadata := make(map[string]interface{})
+	if len(raw) == 0 {
+		return metadata, nil
+	}
+
+	if err := decodeJSON(raw, &metadata, true); err != nil {
+		return nil, err
+	}
+	if metadata == nil {
+		metadata = make(map[string]interface{}) // metadata was null
+	}
+
+	return metadata, nil
+}
+
+// schemaVersion reads the schema version out of metadata, defaulting to 1
+func schemaVersion(metadata map[string]interface{}) (int, error) {
+	value, ok := metadata[SchemaVersionKey]
+	if !ok {
+		return 1, nil
+	}
+
+	var version float64
+	switch v := value.(type) {
+	case json.Number:
+		f, err := v.Float64()
+		if err != nil {
+			return 0, ErrInvalidSchemaVersion
+		}
+		version = f
+	case float64:
+		version = v
+	default:
+		return 0, ErrInvalidSchemaVersion
+	}
+	if version < 1 || version != math.Trunc(version) {
+		return 0, ErrInvalidSchemaVersion
+	}
+
+	return int(version), nil
+}
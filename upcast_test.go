@@ -0,0 +1,491 @@
+package gomessagestore_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	. "github.com/blackhatbrigade/gomessagestore"
+	"github.com/blackhatbrigade/gomessagestore/repository"
+	"github.com/blackhatbrigade/gomessagestore/repository/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+type accountOpened struct {
+	FirstName string `json:"firstName"`
+	LastName  string `json:"lastName"`
+	Currency  string `json:"currency"`
+}
+
+// getSampleUpcastingRegistry has AccountOpened at schema version 3:
+// version 1 had a single "name", version 2 split it into first and last names, and version 3 added a currency
+func getSampleUpcastingRegistry(t *testing.T) *Registry {
+	registry := NewRegistry()
+	if err := registry.Register("AccountOpened", accountOpened{}); err != nil {
+		t.Fatalf("registering AccountOpened failed: %s", err)
+	}
+	if err := registry.RegisterUpcaster("AccountOpened", 1, func(data map[string]interface{}) (map[string]interface{}, error) {
+		name, _ := data["name"].(string)
+		names := strings.SplitN(name, " ", 2)
+		if len(names) != 2 {
+			return nil, fmt.Errorf("can't split %q into first and last names", name)
+		}
+		return map[string]interface{}{"firstName": names[0], "lastName": names[1]}, nil
+	}); err != nil {
+		t.Fatalf("registering upcaster 1 failed: %s", err)
+	}
+	if err := registry.RegisterUpcaster("AccountOpened", 2, func(data map[string]interface{}) (map[string]interface{}, error) {
+		data["currency"] = "USD"
+		return data, nil
+	}); err != nil {
+		t.Fatalf("registering upcaster 2 failed: %s", err)
+	}
+
+	return registry
+}
+
+func getSampleAccountOpenedEnvelope(data, metadata string) *repository.MessageEnvelope {
+	return &repository.MessageEnvelope{
+		ID:             uuid5,
+		MessageType:    "AccountOpened",
+		StreamName:     "account-" + uuid8.String(),
+		StreamCategory: "account",
+		Data:           []byte(data),
+		Metadata:       []byte(metadata),
+		Version:        0,
+		GlobalPosition: 4,
+	}
+}
+
+func TestRegisterUpcaster(t *testing.T) {
+	noop := func(data map[string]interface{}) (map[string]interface{}, error) { return data, nil }
+
+	tests := []struct {
+		name            string
+		messageType     string
+		fromVersion     int
+		upcaster        Upcaster
+		expectedErr     error
+		expectedVersion int
+	}{{
+		name:            "adds the next step",
+		messageType:     "AccountOpened",
+		fromVersion:     3,
+		upcaster:        noop,
+		expectedVersion: 4,
+	}, {
+		name:            "starts a new message type at version 1",
+		messageType:     "AccountClosed",
+		fromVersion:     1,
+		upcaster:        noop,
+		expectedVersion: 2,
+	}, {
+		name:        "fails without a message type",
+		fromVersion: 1,
+		upcaster:    noop,
+		expectedErr: ErrMissingMessageType,
+	}, {
+		name:        "fails without an upcaster",
+		messageType: "AccountOpened",
+		fromVersion: 3,
+		expectedErr: ErrMissingUpcaster,
+	}, {
+		name:        "fails when a step is skipped",
+		messageType: "AccountOpened",
+		fromVersion: 4,
+		upcaster:    noop,
+		expectedErr: ErrUpcasterOutOfOrder,
+	}, {
+		name:        "fails when a step is registered twice",
+		messageType: "AccountOpened",
+		fromVersion: 2,
+		upcaster:    noop,
+		expectedErr: ErrUpcasterOutOfOrder,
+	}, {
+		name:        "fails when a new message type doesn't start at version 1",
+		messageType: "AccountClosed",
+		fromVersion: 0,
+		upcaster:    noop,
+		expectedErr: ErrUpcasterOutOfOrder,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			registry := getSampleUpcastingRegistry(t)
+
+			err := registry.RegisterUpcaster(test.messageType, test.fromVersion, test.upcaster)
+
+			assert.Equal(t, test.expectedErr, err)
+			if test.expectedErr == nil {
+				assert.Equal(t, test.expectedVersion, registry.SchemaVersion(test.messageType))
+			}
+		})
+	}
+}
+
+func TestRegistryUpcast(t *testing.T) {
+	tests := []struct {
+		name             string
+		envelope         *repository.MessageEnvelope
+		expectedData     string
+		expectedMetadata string
+		expectedErr      error
+		expectError      bool
+	}{{
+		name:             "upcasts through every step when the schema version is missing",
+		envelope:         getSampleAccountOpenedEnvelope(`{"name":"Jane Doe"}`, `{"correlationId":"abc"}`),
+		expectedData:     `{"currency":"USD","firstName":"Jane","lastName":"Doe"}`,
+		expectedMetadata: `{"correlationId":"abc","schemaVersion":3}`,
+	}, {
+		name:             "upcasts through every step from version 1",
+		envelope:         getSampleAccountOpenedEnvelope(`{"name":"Jane Doe"}`, `{"schemaVersion":1}`),
+		expectedData:     `{"currency":"USD","firstName":"Jane","lastName":"Doe"}`,
+		expectedMetadata: `{"schemaVersion":3}`,
+	}, {
+		name:             "upcasts through the remaining step from version 2",
+		envelope:         getSampleAccountOpenedEnvelope(`{"firstName":"Jane","lastName":"Doe"}`, `{"schemaVersion":2}`),
+		expectedData:     `{"currency":"USD","firstName":"Jane","lastName":"Doe"}`,
+		expectedMetadata: `{"schemaVersion":3}`,
+	}, {
+		name:             "leaves the current version alone",
+		envelope:         getSampleAccountOpenedEnvelope(`{"firstName":"Jane","lastName":"Doe","currency":"EUR"}`, `{"schemaVersion":3}`),
+		expectedData:     `{"firstName":"Jane","lastName":"Doe","currency":"EUR"}`,
+		expectedMetadata: `{"schemaVersion":3}`,
+	}, {
+		name:             "leaves versions newer than the registry alone",
+		envelope:         getSampleAccountOpenedEnvelope(`{"fullName":"Jane Doe"}`, `{"schemaVersion":4}`),
+		expectedData:     `{"fullName":"Jane Doe"}`,
+		expectedMetadata: `{"schemaVersion":4}`,
+	}, {
+		name:             "upcasts envelopes with null metadata",
+		envelope:         getSampleAccountOpenedEnvelope(`{"name":"Jane Doe"}`, `null`),
+		expectedData:     `{"currency":"USD","firstName":"Jane","lastName":"Doe"}`,
+		expectedMetadata: `{"schemaVersion":3}`,
+	}, {
+		name:             "keeps large numbers in metadata exact",
+		envelope:         getSampleAccountOpenedEnvelope(`{"name":"Jane Doe"}`, `{"sequence":9007199254740993}`),
+		expectedData:     `{"currency":"USD","firstName":"Jane","lastName":"Doe"}`,
+		expectedMetadata: `{"schemaVersion":3,"sequence":9007199254740993}`,
+	}, {
+		name:        "fails on a schema version that isn't a whole number",
+		envelope:    getSampleAccountOpenedEnvelope(`{"name":"Jane Doe"}`, `{"schemaVersion":1.5}`),
+		expectedErr: ErrInvalidSchemaVersion,
+	}, {
+		name:        "fails on a schema version below 1",
+		envelope:    getSampleAccountOpenedEnvelope(`{"name":"Jane Doe"}`, `{"schemaVersion":0}`),
+		expectedErr: ErrInvalidSchemaVersion,
+	}, {
+		name:        "fails on a schema version that isn't a number",
+		envelope:    getSampleAccountOpenedEnvelope(`{"name":"Jane Doe"}`, `{"schemaVersion":"2"}`),
+		expectedErr: ErrInvalidSchemaVersion,
+	}, {
+		name:        "fails when an upcaster fails",
+		envelope:    getSampleAccountOpenedEnvelope(`{"name":"Cher"}`, `{}`),
+		expectError: true,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			registry := getSampleUpcastingRegistry(t)
+			original := *test.envelope
+
+			upcast, err := registry.Upcast(test.envelope)
+
+			assert.Equal(t, original, *test.envelope, "the envelope passed in should not be changed")
+			if test.expectedErr != nil || test.expectError {
+				assert.Error(t, err)
+				if test.expectedErr != nil {
+					assert.Equal(t, test.expectedErr, err)
+				}
+				return
+			}
+			assert.NoError(t, err)
+			assert.JSONEq(t, test.expectedData, string(upcast.Data))
+			assert.JSONEq(t, test.expectedMetadata, string(upcast.Metadata))
+			assert.Equal(t, original.ID, upcast.ID)
+			assert.Equal(t, original.GlobalPosition, upcast.GlobalPosition)
+		})
+	}
+}
+
+func TestGetUpcastsMessages(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockRepository(ctrl)
+	ctx := context.Background()
+
+	v1 := getSampleAccountOpenedEnvelope(`{"name":"Jane Doe"}`, `{}`)
+	v2 := getSampleAccountOpenedEnvelope(`{"firstName":"John","lastName":"Roe"}`, `{"schemaVersion":2}`)
+	v2.ID = uuid6
+	v3 := getSampleAccountOpenedEnvelope(`{"firstName":"Ann","lastName":"Poe","currency":"EUR"}`, `{"schemaVersion":3}`)
+	v3.ID = uuid7
+
+	mockRepo.
+		EXPECT().
+		GetAllMessagesInStream(ctx, v1.StreamName, 1000).
+		Return([]*repository.MessageEnvelope{v1, v2, v3}, nil)
+
+	msgStore := NewMessageStoreFromRepository(mockRepo, logrus.New(), WithRegistry(getSampleUpcastingRegistry(t)))
+	msgs, err := msgStore.Get(ctx, EventStream("account", uuid8))
+
+	assert.NoError(t, err)
+	var data []interface{}
+	for _, msg := range msgs {
+		typed, ok := msg.(*TypedMessage)
+		if assert.True(t, ok, "expected a TypedMessage, got %T", msg) {
+			data = append(data, typed.Data)
+			assert.Equal(t, float64(3), typed.Metadata[SchemaVersionKey])
+		}
+	}
+	assert.Equal(t, []interface{}{
+		accountOpened{FirstName: "Jane", LastName: "Doe", Currency: "USD"},
+		accountOpened{FirstName: "John", LastName: "Roe", Currency: "USD"},
+		accountOpened{FirstName: "Ann", LastName: "Poe", Currency: "EUR"},
+	}, data)
+}
+
+func TestGetUpcastsUnregisteredTypes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockRepository(ctrl)
+	ctx := context.Background()
+
+	registry := NewRegistry()
+	registry.RegisterUpcaster("AccountOpened", 1, func(data map[string]interface{}) (map[string]interface{}, error) {
+		data["currency"] = "USD"
+		return data, nil
+	})
+
+	msgEnv := getSampleAccountOpenedEnvelope(`{"name":"Jane Doe"}`, `{}`)
+
+	mockRepo.
+		EXPECT().
+		GetAllMessagesInStream(ctx, msgEnv.StreamName, 1000).
+		Return([]*repository.MessageEnvelope{msgEnv}, nil)
+
+	msgStore := NewMessageStoreFromRepository(mockRepo, logrus.New(), WithRegistry(registry))
+	msgs, err := msgStore.Get(ctx, EventStream("account", uuid8))
+
+	assert.NoError(t, err)
+	if assert.Len(t, msgs, 1) {
+		event, ok := msgs[0].(*Event)
+		if assert.True(t, ok, "expected an Event, got %T", msgs[0]) {
+			assert.Equal(t, map[string]interface{}{"name": "Jane Doe", "currency": "USD"}, event.Data)
+		}
+	}
+}
+
+func TestGetSkipsMessagesThatCantBeUpcast(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockRepository(ctrl)
+	ctx := context.Background()
+
+	failing := getSampleAccountOpenedEnvelope(`{"name":"Cher"}`, `{}`)
+	badVersion := getSampleAccountOpenedEnvelope(`{"name":"Jane Doe"}`, `{"schemaVersion":"2"}`)
+	badVersion.ID = uuid6
+	good := getSampleAccountOpenedEnvelope(`{"name":"Jane Doe"}`, `{}`)
+	good.ID = uuid7
+
+	mockRepo.
+		EXPECT().
+		GetAllMessagesInStream(ctx, failing.StreamName, 1000).
+		Return([]*repository.MessageEnvelope{failing, badVersion, good}, nil)
+
+	msgStore := NewMessageStoreFromRepository(mockRepo, logrus.New(), WithRegistry(getSampleUpcastingRegistry(t)))
+	msgs, err := msgStore.Get(ctx, EventStream("account", uuid8))
+
+	assert.NoError(t, err)
+	if assert.Len(t, msgs, 1) {
+		typed, ok := msgs[0].(*TypedMessage)
+		if assert.True(t, ok, "expected a TypedMessage, got %T", msgs[0]) {
+			assert.Equal(t, uuid7, typed.ID)
+		}
+	}
+}
+
+func TestGetDoesntReadMetadataOfTypesWithoutUpcasters(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockRepository(ctrl)
+	ctx := context.Background()
+
+	msgEnv := getSampleAccountOpenedEnvelope(`{"name":"Jane Doe"}`, `{"schemaVersion":"not a number"}`)
+	msgEnv.MessageType = "AccountClosed"
+
+	mockRepo.
+		EXPECT().
+		GetAllMessagesInStream(ctx, msgEnv.StreamName, 1000).
+		Return([]*repository.MessageEnvelope{msgEnv}, nil)
+
+	msgStore := NewMessageStoreFromRepository(mockRepo, logrus.New(), WithRegistry(getSampleUpcastingRegistry(t)))
+	msgs, err := msgStore.Get(ctx, EventStream("account", uuid8))
+
+	assert.NoError(t, err)
+	assert.Len(t, msgs, 1)
+}
+
+func TestGetByIDFailsWhenUpcastingFails(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockRepository(ctrl)
+	ctx := context.Background()
+
+	msgEnv := getSampleAccountOpenedEnvelope(`{"name":"Cher"}`, `{}`)
+
+	mockRepo.
+		EXPECT().
+		GetMessageByID(ctx, msgEnv.ID).
+		Return(msgEnv, nil)
+
+	msgStore := NewMessageStoreFromRepository(mockRepo, logrus.New(), WithRegistry(getSampleUpcastingRegistry(t)))
+	msg, err := msgStore.GetByID(ctx, msgEnv.ID)
+
+	assert.Error(t, err)
+	assert.Nil(t, msg)
+}
+
+func TestGetByIDUpcastsMessages(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockRepository(ctrl)
+	ctx := context.Background()
+
+	msgEnv := getSampleAccountOpenedEnvelope(`{"firstName":"John","lastName":"Roe"}`, `{"schemaVersion":2}`)
+
+	mockRepo.
+		EXPECT().
+		GetMessageByID(ctx, msgEnv.ID).
+		Return(msgEnv, nil)
+
+	msgStore := NewMessageStoreFromRepository(mockRepo, logrus.New(), WithRegistry(getSampleUpcastingRegistry(t)))
+	msg, err := msgStore.GetByID(ctx, msgEnv.ID)
+
+	assert.NoError(t, err)
+	typed, ok := msg.(*TypedMessage)
+	if assert.True(t, ok, "expected a TypedMessage, got %T", msg) {
+		assert.Equal(t, accountOpened{FirstName: "John", LastName: "Roe", Currency: "USD"}, typed.Data)
+	}
+}
+
+func TestWriteStampsSchemaVersion(t *testing.T) {
+	tests := []struct {
+		name             string
+		registry         bool
+		messageType      string
+		metadata         map[string]interface{}
+		expectedMetadata string
+	}{{
+		name:             "stamps the current schema version",
+		registry:         true,
+		messageType:      "AccountOpened",
+		metadata:         map[string]interface{}{"correlationId": "abc"},
+		expectedMetadata: `{"correlationId":"abc","schemaVersion":3}`,
+	}, {
+		name:             "stamps version 1 for types without upcasters",
+		registry:         true,
+		messageType:      "AccountClosed",
+		expectedMetadata: `{"schemaVersion":1}`,
+	}, {
+		name:             "keeps a schema version that was set",
+		registry:         true,
+		messageType:      "AccountOpened",
+		metadata:         map[string]interface{}{SchemaVersionKey: 1},
+		expectedMetadata: `{"schemaVersion":1}`,
+	}, {
+		name:             "doesn't stamp without a registry",
+		messageType:      "AccountOpened",
+		metadata:         map[string]interface{}{"correlationId": "abc"},
+		expectedMetadata: `{"correlationId":"abc"}`,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mock_repository.NewMockRepository(ctrl)
+			ctx := context.Background()
+
+			var opts []MessageStoreOption
+			if test.registry {
+				opts = append(opts, WithRegistry(getSampleUpcastingRegistry(t)))
+			}
+
+			var written *repository.MessageEnvelope
+			mockRepo.
+				EXPECT().
+				WriteMessage(ctx, gomock.Any()).
+				Do(func(_ context.Context, msgEnv *repository.MessageEnvelope) {
+					written = msgEnv
+				})
+
+			msgStore := NewMessageStoreFromRepository(mockRepo, logrus.New(), opts...)
+			err := msgStore.Write(ctx, &Event{
+				ID:             uuid5,
+				EntityID:       uuid8,
+				StreamCategory: "account",
+				MessageType:    test.messageType,
+				Data:           map[string]interface{}{"firstName": "Jane"},
+				Metadata:       test.metadata,
+			})
+
+			assert.NoError(t, err)
+			if assert.NotNil(t, written) {
+				assert.JSONEq(t, test.expectedMetadata, string(written.Metadata))
+			}
+		})
+	}
+}
+
+func TestMsgEnvelopesToMessagesDoesNotUpcastWithoutARegistry(t *testing.T) {
+	msgEnv := getSampleAccountOpenedEnvelope(`{"name":"Jane Doe"}`, `{}`)
+
+	msgs := MsgEnvelopesToMessages([]*repository.MessageEnvelope{msgEnv})
+
+	if assert.Len(t, msgs, 1) {
+		event, ok := msgs[0].(*Event)
+		if assert.True(t, ok, "expected an Event, got %T", msgs[0]) {
+			assert.Equal(t, map[string]interface{}{"name": "Jane Doe"}, event.Data)
+		}
+	}
+}
+
+func TestUpcastersGetExactNumbers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockRepository(ctrl)
+	ctx := context.Background()
+
+	registry := NewRegistry()
+	registry.RegisterUpcaster("Deposited", 1, func(data map[string]interface{}) (map[string]interface{}, error) {
+		return map[string]interface{}{"amountCents": data["amount"]}, nil
+	})
+
+	msgEnv := getSampleAccountOpenedEnvelope(`{"amount":9007199254740993}`, `{}`)
+	msgEnv.MessageType = "Deposited"
+
+	mockRepo.
+		EXPECT().
+		GetAllMessagesInStream(ctx, msgEnv.StreamName, 1000).
+		Return([]*repository.MessageEnvelope{msgEnv}, nil)
+
+	msgStore := NewMessageStoreFromRepository(mockRepo, logrus.New(), WithRegistry(registry), WithExactNumbers())
+	msgs, err := msgStore.Get(ctx, EventStream("account", uuid8))
+
+	assert.NoError(t, err)
+	if assert.Len(t, msgs, 1) {
+		assert.Equal(t, map[string]interface{}{"amountCents": json.Number("9007199254740993")}, msgs[0].(*Event).Data)
+	}
+}
@@ -4,19 +4,40 @@ import (
 	"context"
 )
 
-// GetMessages retrieves messages from the message store; Second process in the polling loop
-func (sw *subscriptionWorker) GetMessages(ctx context.Context, position int64) ([]Message, error) {
-	opts := []GetOption{}
-	if !sw.config.stream { // for stream subscription
-		opts = append(opts, SincePosition(position), Category(sw.config.category))
-	} else { // for category subscription
-		opts = append(opts, SinceVersion(position))
-		if sw.config.commandCategory != "" { // for commands
-			opts = append(opts, CommandStream(sw.config.commandCategory))
-		} else { // for events
-			opts = append(opts, EventStream(sw.config.category, sw.config.entityID))
+// GetMessages retrieves messages from the message store, along with what was read to get them; Second process in the polling loop
+func (sw *subscriptionWorker) GetMessages(ctx context.Context, position int64, batchSize int) ([]Message, ReadBatch, error) {
+	var read ReadBatch
+	msgs, err := sw.ms.Get(ctx, append(sw.readOptions(position, batchSize), ReportRead(&read))...)
+	return msgs, read, err
+}
+
+// readOptions are the options for reading a batch of the subscribed messages from position
+func (sw *subscriptionWorker) readOptions(position int64, batchSize int) []GetOption {
+	opts := []GetOption{BatchSize(batchSize)}
+	if !sw.config.stream { // for category and all stream subscriptions
+		opts = append(opts, SincePosition(position))
+		if sw.config.allStreams {
+			opts = append(opts, AllStreams())
+		} else if len(sw.config.categories) == 1 {
+			opts = append(opts, Category(sw.config.categories[0]))
+		} else {
+			opts = append(opts, Categories(sw.config.categories...))
+		}
+	} else { // for stream subscription
+		opts = append(opts, SinceVersion(position), sw.streamOption())
+	}
+
+	return opts
+}
+
+// streamOption picks the stream of a stream subscription
+func (sw *subscriptionWorker) streamOption() GetOption {
+	if sw.config.commandCategory != "" { // for commands
+		if sw.config.entityID != "" {
+			return EntityCommandStreamID(sw.config.commandCategory, sw.config.entityID)
 		}
+		return CommandStream(sw.config.commandCategory)
 	}
 
-	return sw.ms.Get(ctx, opts...)
+	return EventStreamID(sw.config.category, sw.config.entityID) // for events
 }
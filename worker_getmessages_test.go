@@ -18,15 +18,17 @@ func TestSubscriberGetsMessages(t *testing.T) {
 	messageHandler := &msgHandler{}
 
 	tests := []struct {
-		name             string
-		expectedError    error
-		handlers         []MessageHandler
-		expectedPosition int64
-		expectedStream   string
-		expectedCategory string
-		opts             []SubscriberOption
-		messageEnvelopes []*repository.MessageEnvelope
-		repoReturnError  error
+		name               string
+		expectedError      error
+		handlers           []MessageHandler
+		expectedPosition   int64
+		expectedStream     string
+		expectedCategory   string
+		expectedCategories []string
+		expectedAllStreams bool
+		opts               []SubscriberOption
+		messageEnvelopes   []*repository.MessageEnvelope
+		repoReturnError    error
 	}{{
 		name:             "When subscriber is called with SubscribeToEntityStream() option, repository is called correctly",
 		expectedStream:   "some category-10000000-0000-0000-0000-000000000001",
@@ -35,6 +37,21 @@ func TestSubscriberGetsMessages(t *testing.T) {
 		opts: []SubscriberOption{
 			SubscribeToEntityStream("some category", uuid1),
 		},
+	}, {
+		name:             "When subscriber is called with SubscribeToEntityStreamID() option, repository is called correctly",
+		expectedStream:   "some category-ORD123",
+		handlers:         []MessageHandler{messageHandler},
+		expectedPosition: 5,
+		opts: []SubscriberOption{
+			SubscribeToEntityStreamID("some category", "ORD123"),
+		},
+	}, {
+		name:           "When subscriber is called with SubscribeToEntityCommandStreamID() option, repository is called correctly",
+		expectedStream: "some category:command-42",
+		handlers:       []MessageHandler{messageHandler},
+		opts: []SubscriberOption{
+			SubscribeToEntityCommandStreamID("some category", "42"),
+		},
 	}, {
 		name:             "When subscriber is called with SubscribeToCategory() option, repository is called correctly",
 		expectedCategory: "some category",
@@ -43,6 +60,41 @@ func TestSubscriberGetsMessages(t *testing.T) {
 		opts: []SubscriberOption{
 			SubscribeToCategory("some category"),
 		},
+	}, {
+		name:               "When subscriber is called with several SubscribeToCategory() options, repository is called correctly",
+		expectedCategories: []string{"some category", "some other category"},
+		handlers:           []MessageHandler{messageHandler},
+		expectedPosition:   5,
+		opts: []SubscriberOption{
+			SubscribeToCategory("some category"),
+			SubscribeToCategory("some other category"),
+		},
+	}, {
+		name:               "repository errors are passed on down when subscribed to several categories",
+		repoReturnError:    potato,
+		expectedError:      potato,
+		expectedCategories: []string{"some category", "some other category"},
+		handlers:           []MessageHandler{messageHandler},
+		opts: []SubscriberOption{
+			SubscribeToCategories("some category", "some other category"),
+		},
+	}, {
+		name:               "When subscriber is called with SubscribeToAllStreams() option, repository is called correctly",
+		expectedAllStreams: true,
+		handlers:           []MessageHandler{messageHandler},
+		expectedPosition:   5,
+		opts: []SubscriberOption{
+			SubscribeToAllStreams(),
+		},
+	}, {
+		name:               "repository errors are passed on down when subscribed to all streams",
+		repoReturnError:    potato,
+		expectedError:      potato,
+		expectedAllStreams: true,
+		handlers:           []MessageHandler{messageHandler},
+		opts: []SubscriberOption{
+			SubscribeToAllStreams(),
+		},
 	}, {
 		name:           "When subscriber is called with SubscribeToEntityStream() option, repository is called correctly",
 		handlers:       []MessageHandler{messageHandler},
@@ -50,6 +102,13 @@ func TestSubscriberGetsMessages(t *testing.T) {
 		opts: []SubscriberOption{
 			SubscribeToCommandStream("some category"),
 		},
+	}, {
+		name:           "When subscriber is called with SubscribeToEntityCommandStream() option, repository is called correctly",
+		handlers:       []MessageHandler{messageHandler},
+		expectedStream: "some category:command-10000000-0000-0000-0000-000000000001",
+		opts: []SubscriberOption{
+			SubscribeToEntityCommandStream("some category", uuid1),
+		},
 	}, {
 		name:            "repository errors are passed on down",
 		repoReturnError: potato,
@@ -84,6 +143,18 @@ func TestSubscriberGetsMessages(t *testing.T) {
 					GetAllMessagesInStreamSince(ctx, test.expectedStream, test.expectedPosition, 1000).
 					Return(test.messageEnvelopes, test.repoReturnError)
 			}
+			if test.expectedCategories != nil {
+				mockRepo.
+					EXPECT().
+					GetAllMessagesInCategoriesSince(ctx, test.expectedCategories, test.expectedPosition, 1000).
+					Return(test.messageEnvelopes, test.repoReturnError)
+			}
+			if test.expectedAllStreams {
+				mockRepo.
+					EXPECT().
+					GetAllMessagesSince(ctx, test.expectedPosition, 1000).
+					Return(test.messageEnvelopes, test.repoReturnError)
+			}
 			if test.expectedCategory != "" {
 				mockRepo.
 					EXPECT().
@@ -109,7 +180,7 @@ func TestSubscriberGetsMessages(t *testing.T) {
 				return
 			}
 
-			_, err = myWorker.GetMessages(ctx, test.expectedPosition)
+			_, _, err = myWorker.GetMessages(ctx, test.expectedPosition, 1000)
 			if err != test.expectedError {
 				t.Errorf("Failed to get expected error from GetMessages()\nExpected: %s\n and got: %s\n", test.expectedError, err)
 			}
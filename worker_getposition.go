@@ -3,10 +3,10 @@ package gomessagestore
 import (
 	"context"
 	"encoding/json"
-	"fmt"
 	"strings"
 
 	"github.com/blackhatbrigade/gomessagestore/repository"
+	"github.com/blackhatbrigade/gomessagestore/streamname"
 	"github.com/blackhatbrigade/gomessagestore/uuid"
 	"github.com/sirupsen/logrus"
 )
@@ -26,7 +26,7 @@ func (sw *subscriptionWorker) GetPosition(ctx context.Context) (int64, error) {
 	)
 	if len(msgs) < 1 {
 		log.Debug("no messages found for subscriber, using default")
-		return 0, nil
+		return sw.startingPosition(ctx)
 	}
 
 	switch pos := msgs[0].(type) {
@@ -40,6 +40,54 @@ func (sw *subscriptionWorker) GetPosition(ctx context.Context) (int64, error) {
 	}
 }
 
+// startingPosition is where a subscriber without a saved position starts: the beginning, or the first message written at or after its start time
+// Message DB doesn't index the time messages were written, so rather than scan for the start time, this binary searches the positions the subscriber reads, which were written in time order.
+func (sw *subscriptionWorker) startingPosition(ctx context.Context) (int64, error) {
+	if sw.config.startTime.IsZero() {
+		return 0, nil
+	}
+
+	last, err := sw.lastPosition(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	// the first message read from before was written before the start time, and nothing read from after was
+	before, after := int64(-1), last+1
+	for after-before > 1 {
+		middle := before + (after-before)/2
+		var read ReadBatch // the envelope's time, as the message may not be readable
+		if _, err := sw.ms.Get(ctx, append(sw.readOptions(middle, 1), ReportRead(&read))...); err != nil {
+			return 0, err
+		}
+
+		if read.Count == 0 || !read.LastTime.Before(sw.config.startTime) {
+			after = middle
+		} else {
+			before = middle
+		}
+	}
+
+	return after, nil
+}
+
+// lastPosition is the position of the last message a subscriber could read, or -1 when there is none
+// Category subscriptions use the last message in the whole store, as global positions are shared by every category.
+func (sw *subscriptionWorker) lastPosition(ctx context.Context) (int64, error) {
+	var read ReadBatch
+	if sw.config.stream {
+		if _, err := sw.ms.Get(ctx, sw.streamOption(), Last(), ReportRead(&read)); err != nil || read.Count == 0 {
+			return -1, err
+		}
+		return read.LastVersion, nil
+	}
+
+	if _, err := sw.ms.Get(ctx, AllStreams(), Last(), ReportRead(&read)); err != nil || read.Count == 0 {
+		return -1, err
+	}
+	return read.LastPosition, nil
+}
+
 // convertEnvelopeToPositionMessage takes a messageEnvelope and converts it into a PositionMessage that is used to keep track of position changes
 func convertEnvelopeToPositionMessage(messageEnvelope *repository.MessageEnvelope) (Message, error) {
 	data := positionData{}
@@ -48,8 +96,8 @@ func convertEnvelopeToPositionMessage(messageEnvelope *repository.MessageEnvelop
 		return nil, err
 	}
 
-	halves := strings.Split(messageEnvelope.StreamName, "+")
-	if len(halves) != 2 || halves[1] != "position" {
+	subscriberID, ok := subscriberIDFromPositionStream(messageEnvelope.StreamName)
+	if !ok {
 		return nil, ErrInvalidPositionStream
 	}
 
@@ -57,11 +105,26 @@ func convertEnvelopeToPositionMessage(messageEnvelope *repository.MessageEnvelop
 		ID:             messageEnvelope.ID,
 		MyPosition:     data.Position,
 		MessageVersion: messageEnvelope.Version,
-		SubscriberID:   halves[0],
+		SubscriberID:   subscriberID,
 	}
 	return positionMsg, nil
 }
 
+// positionStream is the stream a subscriber saves its position to; it predates Eventide naming (which would be subscriberID:position) and is kept so saved positions are still found
+func positionStream(subscriberID string) string {
+	return streamname.Compound(subscriberID, streamname.PositionType)
+}
+
+// subscriberIDFromPositionStream gets the subscriber ID back from a position stream name
+func subscriberIDFromPositionStream(streamName string) (string, bool) {
+	subscriberID := strings.TrimSuffix(streamName, positionStream(""))
+	if subscriberID == streamName || strings.Contains(subscriberID, "+") {
+		return "", false
+	}
+
+	return subscriberID, true
+}
+
 // positionMessage is a message type used to keep track of changes in position so that messages are not read multiple times or skipped
 type positionMessage struct {
 	ID             uuid.UUID
@@ -116,7 +179,7 @@ func (posMsg *positionMessage) ToEnvelope() (*repository.MessageEnvelope, error)
 	msgEnv := &repository.MessageEnvelope{
 		ID:             posMsg.ID,
 		MessageType:    messageType,
-		StreamName:     fmt.Sprintf("%s+position", posMsg.SubscriberID),
+		StreamName:     positionStream(posMsg.SubscriberID),
 		Data:           data,
 		Version:        posMsg.MessageVersion,
 		GlobalPosition: posMsg.GlobalPosition,
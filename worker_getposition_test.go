@@ -6,6 +6,7 @@ import (
 	"time"
 
 	. "github.com/blackhatbrigade/gomessagestore"
+	"github.com/blackhatbrigade/gomessagestore/inmem_repository"
 	"github.com/blackhatbrigade/gomessagestore/repository"
 	mock_repository "github.com/blackhatbrigade/gomessagestore/repository/mocks"
 	"github.com/blackhatbrigade/gomessagestore/uuid"
@@ -138,3 +139,168 @@ func TestSubscriberGetsPosition(t *testing.T) {
 		})
 	}
 }
+
+func TestSubscriberGetsStartingPosition(t *testing.T) {
+	startTime := time.Unix(1000, 0)
+
+	// global positions 0 to 4; "some category" has versions 0 and 1 of uuid1's stream
+	history := []*Event{
+		{MessageType: "Happened", StreamCategory: "some category", EntityID: uuid1, Data: map[string]interface{}{}, Time: time.Unix(998, 0)},
+		{MessageType: "Happened", StreamCategory: "other category", EntityID: uuid2, Data: map[string]interface{}{}, Time: time.Unix(999, 0)},
+		{MessageType: "Happened", StreamCategory: "other category", EntityID: uuid2, Data: map[string]interface{}{}, Time: time.Unix(1000, 0)},
+		{MessageType: "Happened", StreamCategory: "some category", EntityID: uuid1, Data: map[string]interface{}{}, Time: time.Unix(1001, 0)},
+		{MessageType: "Happened", StreamCategory: "some other category", EntityID: uuid3, Data: map[string]interface{}{}, Time: time.Unix(1002, 0)},
+	}
+
+	tests := []struct {
+		name             string
+		opts             []SubscriberOption
+		history          []*Event
+		expectedPosition int64
+	}{{
+		name:             "A category subscriber starts after the last message it would read from before the start time",
+		opts:             []SubscriberOption{SubscribeToCategory("some category"), SubscribeSince(startTime)},
+		history:          history,
+		expectedPosition: 1,
+	}, {
+		name:             "A category subscriber starts after the last message when nothing was written since the start time",
+		opts:             []SubscriberOption{SubscribeToCategories("some category", "some other category"), SubscribeSince(time.Unix(1003, 0))},
+		history:          history,
+		expectedPosition: 5,
+	}, {
+		name:             "An all streams subscriber starts at the first message written at the start time",
+		opts:             []SubscriberOption{SubscribeToAllStreams(), SubscribeSince(startTime)},
+		history:          history,
+		expectedPosition: 2,
+	}, {
+		name:             "An all streams subscriber starts at the beginning when everything was written since the start time",
+		opts:             []SubscriberOption{SubscribeToAllStreams(), SubscribeSince(time.Unix(900, 0))},
+		history:          history,
+		expectedPosition: 0,
+	}, {
+		name:             "An all streams subscriber starts at the beginning of an empty store",
+		opts:             []SubscriberOption{SubscribeToAllStreams(), SubscribeSince(startTime)},
+		expectedPosition: 0,
+	}, {
+		name:             "A stream subscriber starts at the first version written since the start time",
+		opts:             []SubscriberOption{SubscribeToEntityStream("some category", uuid1), SubscribeSince(startTime)},
+		history:          history,
+		expectedPosition: 1,
+	}, {
+		name:             "A stream subscriber starts after the last version when nothing was written since the start time",
+		opts:             []SubscriberOption{SubscribeToEntityStream("some category", uuid1), SubscribeSince(time.Unix(1002, 0))},
+		history:          history,
+		expectedPosition: 2,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+			myMessageStore := NewMessageStoreFromRepository(inmem_repository.NewInMemoryRepository(nil), logrus.New())
+			for _, event := range test.history {
+				written := *event
+				written.ID = NewID()
+				panicIf(myMessageStore.Write(ctx, &written))
+			}
+
+			opts, err := GetSubscriberConfig(test.opts...)
+			panicIf(err)
+
+			myWorker, err := CreateWorker(
+				myMessageStore,
+				"some id",
+				[]MessageHandler{&msgHandler{}},
+				opts,
+			)
+			panicIf(err)
+
+			pos, err := myWorker.GetPosition(ctx)
+
+			if err != nil {
+				t.Errorf("Failed on GetPosition(): %s", err)
+			}
+			if pos != test.expectedPosition {
+				t.Errorf("Failed on GetPosition()\n Expected%d\n Got: %d", test.expectedPosition, pos)
+			}
+		})
+	}
+}
+
+func TestSubscriberStartingPositionReadsTheTimeOfMessagesThatCantBeDecoded(t *testing.T) {
+	ctx := context.Background()
+
+	var history []repository.MessageEnvelope
+	for position := int64(0); position < 5; position++ {
+		msgEnv := repository.MessageEnvelope{
+			ID:             NewID(),
+			StreamName:     "some category-" + uuid1.String(),
+			StreamCategory: "some category",
+			MessageType:    "Happened",
+			Version:        position,
+			GlobalPosition: position,
+			Data:           []byte(`{}`),
+			Metadata:       []byte(`{}`),
+			Time:           time.Unix(997+position, 0),
+		}
+		if position%2 == 0 { // the first probe and the last message
+			msgEnv.Data = []byte(`{"payload":"not base64"}`)
+			msgEnv.Metadata = []byte(`{"gomessagestore.codec":"msgpack"}`)
+		}
+		history = append(history, msgEnv)
+	}
+
+	myMessageStore := NewMessageStoreFromRepository(inmem_repository.NewInMemoryRepository(history), logrus.New())
+	opts, err := GetSubscriberConfig(SubscribeToCategory("some category"), SubscribeSince(time.Unix(1000, 0)))
+	panicIf(err)
+	myWorker, err := CreateWorker(myMessageStore, "some id", []MessageHandler{&msgHandler{}}, opts)
+	panicIf(err)
+
+	pos, err := myWorker.GetPosition(ctx)
+
+	if err != nil {
+		t.Errorf("Failed on GetPosition(): %s", err)
+	}
+	if pos != 3 {
+		t.Errorf("Failed on GetPosition()\n Expected%d\n Got: %d", 3, pos)
+	}
+}
+
+func TestSubscriberStartingPositionSearchesByPosition(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	mockRepo := mock_repository.NewMockRepository(ctrl)
+
+	// no time range queries, which would scan the messages table
+	gomock.InOrder(
+		mockRepo.
+			EXPECT().
+			GetLastMessageInStream(ctx, "some id+position").
+			Return(nil, nil),
+		mockRepo.
+			EXPECT().
+			GetLastMessage(ctx).
+			Return(&repository.MessageEnvelope{GlobalPosition: 2, StreamName: "other category-1", Time: time.Unix(1002, 0)}, nil),
+		mockRepo.
+			EXPECT().
+			GetAllMessagesInCategorySince(ctx, "some category", int64(1), 1).
+			Return([]*repository.MessageEnvelope{{GlobalPosition: 1, StreamName: "some category-1", Time: time.Unix(999, 0)}}, nil),
+		mockRepo.
+			EXPECT().
+			GetAllMessagesInCategorySince(ctx, "some category", int64(2), 1).
+			Return(nil, potato),
+	)
+
+	myMessageStore := NewMessageStoreFromRepository(mockRepo, logrus.New())
+	opts, err := GetSubscriberConfig(SubscribeToCategory("some category"), SubscribeSince(time.Unix(1000, 0)))
+	panicIf(err)
+	myWorker, err := CreateWorker(myMessageStore, "some id", []MessageHandler{&msgHandler{}}, opts)
+	panicIf(err)
+
+	_, err = myWorker.GetPosition(ctx)
+
+	if err != potato {
+		t.Errorf("Failed to get expected error from GetPosition()\nExpected: %s\n and got: %s\n", potato, err)
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"regexp"
+
+	"github.com/blackhatbrigade/gomessagestore/repository"
 )
 
 type writer struct {
@@ -23,41 +25,115 @@ func checkWriteOptions(opts ...WriteOption) *writer {
 
 // Write writes a Message to the message store.
 func (ms *msgStore) Write(ctx context.Context, message Message, opts ...WriteOption) error {
-	envelope, err := Message.ToEnvelope(message)
+	envelope, err := ms.toEnvelope(message)
+	if err != nil {
+		return err
+	}
+
+	writeOptions := checkWriteOptions(opts...)
+	if writeOptions.atPosition != nil {
+		err = expectedVersionError(ms.repo.WriteMessageWithExpectedPosition(ctx, envelope, *writeOptions.atPosition))
+	} else {
+		err = ms.repo.WriteMessage(ctx, envelope)
+	}
 	if err != nil {
 
 		ms.
 			log.
 			WithError(err).
-			Error("Write: Validation Error")
+			Error("Write: Error writing message")
 
 		return err
 	}
+	return nil
+}
 
-	errMsg := `ERROR: Wrong expected version: .* \(SQLSTATE P0001\)`
-	writeOptions := checkWriteOptions(opts...)
-	if writeOptions.atPosition != nil {
-		err = ms.repo.WriteMessageWithExpectedPosition(ctx, envelope, *writeOptions.atPosition)
+// writeAll writes messages to one stream in one go, the first at position and each of the rest after the one before; none are written if any can't be
+func (ms *msgStore) writeAll(ctx context.Context, messages []Message, position int64) error {
+	envelopes := make([]*repository.MessageEnvelope, len(messages))
+	for i, message := range messages {
+		envelope, err := ms.toEnvelope(message)
 		if err != nil {
-			if matched, _ := regexp.Match(errMsg, []byte(err.Error())); matched {
-				err = ErrExpectedVersionFailed
-			}
+			return err
 		}
-	} else {
-		err = ms.repo.WriteMessage(ctx, envelope)
+		envelopes[i] = envelope
 	}
-	if err != nil {
 
+	if err := expectedVersionError(ms.repo.WriteMessagesWithExpectedPosition(ctx, envelopes, position)); err != nil {
 		ms.
 			log.
 			WithError(err).
-			Error("Write: Error writing message")
+			Error("Write: Error writing messages")
 
 		return err
 	}
 	return nil
 }
 
+// toEnvelope turns a message into the envelope that is written: registered data gets its message type, then the schema version is stamped and the data is encoded
+func (ms *msgStore) toEnvelope(message Message) (*repository.MessageEnvelope, error) {
+	if ms.registry != nil {
+		var err error
+		if message, err = ms.registry.withRegisteredType(message); err != nil {
+			ms.
+				log.
+				WithError(err).
+				Error("Write: Validation Error")
+
+			return nil, err
+		}
+	}
+
+	envelope, err := Message.ToEnvelope(message)
+	if err != nil {
+
+		ms.
+			log.
+			WithError(err).
+			Error("Write: Validation Error")
+
+		return nil, err
+	}
+
+	if ms.registry != nil {
+		if err = ms.registry.stampSchemaVersion(envelope); err != nil {
+			ms.
+				log.
+				WithError(err).
+				Error("Write: Error stamping schema version")
+
+			return nil, err
+		}
+	}
+
+	if err = ms.codecs.encode(envelope, message); err != nil {
+		ms.
+			log.
+			WithError(err).
+			Error("Write: Error encoding message data")
+
+		return nil, err
+	}
+
+	return envelope, nil
+}
+
+// expectedVersionError turns the errors repositories give when a stream isn't at the expected version into ErrExpectedVersionFailed
+func expectedVersionError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if err == repository.ErrWrongStreamVersion {
+		return ErrExpectedVersionFailed
+	}
+
+	errMsg := `ERROR: Wrong expected version: .* \(SQLSTATE P0001\)`
+	if matched, _ := regexp.Match(errMsg, []byte(err.Error())); matched {
+		return ErrExpectedVersionFailed
+	}
+	return err
+}
+
 // AtPosition allows for writing messages using an expected position
 func AtPosition(position int64) WriteOption {
 	return func(w *writer) {